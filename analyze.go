@@ -0,0 +1,150 @@
+package envfile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// definition is a key definition in one of the files.
+type definition struct {
+
+	// file name
+	file string
+
+	// payload
+	payload Payload
+}
+
+// Analyze checks files given in precedence order as Load applies them, where the first exported
+// definition of a key wins unless a later file overloads it, and reports keys defined multiple times,
+// definitions that are never loaded and overrides identical to the overridden value.
+// Local keys are not loaded, so they are not compared.
+func Analyze(filenames ...string) ([]Finding, error) {
+
+	// loader that leaves values as they are written
+	loader := NewLoader()
+
+	// key names in order of first definition
+	var keys []string
+
+	// key definitions by key name
+	definitions := make(map[string][]definition)
+
+	// iterating over a list of filenames
+	for _, filename := range filenames {
+
+		// read file
+		payloads, err := loader.read(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		// iteration over payloads
+		for _, payload := range payloads {
+
+			// local key is not loaded
+			if !payload.Export && !payload.Overload {
+				continue
+			}
+
+			// first definition of the key
+			if _, ok := definitions[payload.Key]; !ok {
+				keys = append(keys, payload.Key)
+			}
+
+			// add definition to list
+			definitions[payload.Key] = append(definitions[payload.Key], definition{file: filename, payload: payload})
+		}
+	}
+
+	// findings list
+	var findings []Finding
+
+	// iterating over a list of keys
+	for _, key := range keys {
+
+		// key definitions
+		defs := definitions[key]
+
+		// key is defined once
+		if len(defs) < 2 {
+			continue
+		}
+
+		// locations of definitions
+		locations := make([]string, len(defs))
+
+		// effective definition: the first one, unless a later one overloads it
+		effective := 0
+
+		// iterating over a list of definitions
+		for i, def := range defs {
+
+			// add location
+			locations[i] = fmt.Sprintf("%s:%d", def.file, def.payload.Line)
+
+			// definition does not overload the effective one
+			if i == 0 || !def.payload.Overload {
+				continue
+			}
+
+			// override is identical to the overridden value
+			if def.payload.Value == defs[effective].payload.Value {
+				findings = append(findings, Finding{
+					Rule:    "redundant-override",
+					File:    def.file,
+					Line:    def.payload.Line,
+					Key:     key,
+					Message: fmt.Sprintf("key '%s' overrides the same value from '%s'", key, defs[effective].file),
+				})
+			}
+
+			// update effective definition
+			effective = i
+		}
+
+		// effective definition
+		last := defs[effective]
+
+		// iterating over a list of definitions
+		for i, def := range defs {
+
+			switch {
+
+			// definition is loaded
+			case i == effective:
+
+			// definition is overridden by a later one
+			case i < effective:
+				findings = append(findings, Finding{
+					Rule:    "dead-definition",
+					File:    def.file,
+					Line:    def.payload.Line,
+					Key:     key,
+					Message: fmt.Sprintf("key '%s' is always overridden in '%s'", key, last.file),
+				})
+
+			// definition is ignored, the key is already loaded
+			default:
+				findings = append(findings, Finding{
+					Rule:    "dead-definition",
+					File:    def.file,
+					Line:    def.payload.Line,
+					Key:     key,
+					Message: fmt.Sprintf("key '%s' is never loaded, it is already defined in '%s'", key, last.file),
+				})
+			}
+		}
+
+		// add finding to list
+		findings = append(findings, Finding{
+			Rule:    "duplicate-key",
+			File:    last.file,
+			Line:    last.payload.Line,
+			Key:     key,
+			Message: fmt.Sprintf("key '%s' is defined %d times: %s", key, len(defs), strings.Join(locations, ", ")),
+		})
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,78 @@
+package envfile
+
+import "testing"
+
+// TestAnalyze tests cross-file analysis.
+func TestAnalyze(t *testing.T) {
+
+	// base file
+	base := createFile(t, "export KEY_1 = value\nexport KEY_2 = value\nexport KEY_3 = value\n")
+
+	// file overriding the base one
+	local := createFile(t, "overload KEY_1 = value\noverload KEY_2 = other\n")
+
+	// analyze files
+	findings, err := Analyze(base, local)
+	if err != nil {
+		t.Fatalf("error analyzing env files: %v", err)
+	}
+
+	// expected findings by rule and key
+	expected := map[string]bool{
+		"dead-definition KEY_1":    true,
+		"redundant-override KEY_1": true,
+		"duplicate-key KEY_1":      true,
+		"dead-definition KEY_2":    true,
+		"duplicate-key KEY_2":      true,
+	}
+
+	// number of findings is different from expected
+	if len(findings) != len(expected) {
+		t.Errorf("expected %d findings, got %d: %v", len(expected), len(findings), findings)
+	}
+
+	// iterating over a list of findings
+	for _, finding := range findings {
+
+		// finding is not expected
+		if !expected[finding.Rule+" "+finding.Key] {
+			t.Errorf("unexpected finding: %s", finding)
+		}
+	}
+}
+
+// TestAnalyzePrecedence tests that findings follow the precedence of Load and skip local keys.
+func TestAnalyzePrecedence(t *testing.T) {
+
+	// base file
+	base := createFile(t, "export KEY_1 = base\nLOCAL = base\n")
+
+	// later file exporting the same key without overloading it
+	later := createFile(t, "export KEY_1 = later\nLOCAL = later\n")
+
+	// analyze files
+	findings, err := Analyze(base, later)
+	if err != nil {
+		t.Fatalf("error analyzing env files: %v", err)
+	}
+
+	// expected findings by rule, key and file
+	expected := map[string]bool{
+		"dead-definition KEY_1 " + later: true,
+		"duplicate-key KEY_1 " + base:    true,
+	}
+
+	// number of findings is different from expected
+	if len(findings) != len(expected) {
+		t.Errorf("expected %d findings, got %d: %v", len(expected), len(findings), findings)
+	}
+
+	// iterating over a list of findings
+	for _, finding := range findings {
+
+		// finding is not expected
+		if !expected[finding.Rule+" "+finding.Key+" "+finding.File] {
+			t.Errorf("unexpected finding: %s", finding)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package envfile
+
+import (
+	"strings"
+	"testing"
+)
+
+// parseText parses the text into a document.
+func parseText(t *testing.T, text string) *Document {
+
+	// parse document
+	doc, err := NewLoader().readDocument("test.envfile", strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("error parsing document: %v", err)
+	}
+
+	return doc
+}
+
+// TestSortKeys tests sorting of keys within sections.
+func TestSortKeys(t *testing.T) {
+
+	// document with two sections
+	doc := parseText(t, "# header\n\n# port of the app\nPORT = 80\nHOST = localhost\n\nZ_KEY = z\n# a key\nA_KEY = a\n")
+
+	// sort keys
+	doc.SortKeys()
+
+	// comments move with their keys, sections stay
+	if expected := "# header\n\nHOST = localhost\n# port of the app\nPORT = 80\n\n# a key\nA_KEY = a\nZ_KEY = z\n"; string(doc.Bytes()) != expected {
+		t.Errorf("expected %q, got %q", expected, doc.Bytes())
+	}
+
+	// lines are renumbered
+	if doc.Nodes[4].Line != 5 || doc.Nodes[4].Key != "PORT" {
+		t.Errorf("expected PORT on line 5, got %+v", doc.Nodes[4])
+	}
+}
+
+// TestGroupByPrefix tests grouping of keys by prefix.
+func TestGroupByPrefix(t *testing.T) {
+
+	// document with mixed keys
+	doc := parseText(t, "# header\n\nDB_HOST = db\n# cache host\nCACHE_HOST = cache\n\nDB_PORT = 5432\nCACHE_TTL = 60\n")
+
+	// group keys
+	doc.GroupByPrefix()
+
+	// groups in order of the first key
+	if expected := "# header\n\nDB_HOST = db\nDB_PORT = 5432\n\n# cache host\nCACHE_HOST = cache\nCACHE_TTL = 60\n"; string(doc.Bytes()) != expected {
+		t.Errorf("expected %q, got %q", expected, doc.Bytes())
+	}
+}
+
+// TestMoveKeyAfter tests moving of a key with its comments.
+func TestMoveKeyAfter(t *testing.T) {
+
+	// document
+	doc := parseText(t, "# url of the app\nURL = { HOST }\nHOST = localhost\nPORT = 80\n")
+
+	// move key
+	if err := doc.MoveKeyAfter("URL", "PORT"); err != nil {
+		t.Fatalf("error moving key: %v", err)
+	}
+
+	// key is moved with its comment
+	if expected := "HOST = localhost\nPORT = 80\n# url of the app\nURL = { HOST }\n"; string(doc.Bytes()) != expected {
+		t.Errorf("expected %q, got %q", expected, doc.Bytes())
+	}
+
+	// missing key
+	if err := doc.MoveKeyAfter("MISSING", "PORT"); err == nil {
+		t.Error("key doesn't exist but move didn't return an error")
+	}
+}
@@ -0,0 +1,66 @@
+package envfile
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+)
+
+// AuditRecord is a record of setting a variable by Load, written as a JSON line.
+type AuditRecord struct {
+
+	// time of setting in UTC
+	Time time.Time `json:"time"`
+
+	// key
+	Key string `json:"key"`
+
+	// file name
+	File string `json:"file"`
+
+	// line number in file
+	Line int `json:"line"`
+
+	// SHA-256 hash of the value
+	Hash string `json:"hash"`
+
+	// process identifier
+	PID int `json:"pid"`
+}
+
+// WithAuditLog appends an audit record to the writer whenever Load sets a variable.
+func WithAuditLog(w io.Writer) Option {
+	return func(l *Loader) {
+
+		// set audit log
+		l.audit = w
+	}
+}
+
+// writeAudit appends the audit record of setting the entry to the audit log, if there is one.
+func (l *Loader) writeAudit(entry Entry) error {
+
+	// audit log is disabled
+	if l.audit == nil {
+		return nil
+	}
+
+	// encode record
+	data, err := json.Marshal(AuditRecord{
+		Time: time.Now().UTC(),
+		Key:  entry.Key,
+		File: entry.File,
+		Line: entry.Line,
+		Hash: hashValue(entry.Value),
+		PID:  os.Getpid(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// write record as one line
+	_, err = l.audit.Write(append(data, '\n'))
+
+	return err
+}
@@ -0,0 +1,65 @@
+package envfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestAuditLog tests audit records of variables set by Load.
+func TestAuditLog(t *testing.T) {
+
+	// set environment variable for the test
+	os.Setenv("ENVFILE_AUDIT_KEPT", "environment")
+
+	// deferred removal of environment variables
+	defer os.Unsetenv("ENVFILE_AUDIT_KEPT")
+	defer os.Unsetenv("ENVFILE_AUDIT_SET")
+
+	// file content
+	filename := createFile(t, "export ENVFILE_AUDIT_KEPT = file\nLOCAL = local\nexport ENVFILE_AUDIT_SET = secret\n")
+
+	// audit log
+	var buffer bytes.Buffer
+
+	// load file
+	if err := NewLoader(WithAuditLog(&buffer)).Load(filename); err != nil {
+		t.Fatalf("error loading env file: %v", err)
+	}
+
+	// records list
+	var records []AuditRecord
+
+	// line by line reading
+	scanner := bufio.NewScanner(&buffer)
+	for scanner.Scan() {
+
+		// record
+		var record AuditRecord
+
+		// decode record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("error decoding audit record: %v", err)
+		}
+
+		// add record to list
+		records = append(records, record)
+	}
+
+	// number of records is different from expected
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+
+	// record
+	record := records[0]
+
+	// record is different from expected
+	if record.Key != "ENVFILE_AUDIT_SET" || record.File != filename || record.Line != 3 ||
+		record.Hash != hashValue("secret") || record.PID != os.Getpid() || time.Since(record.Time) > time.Minute {
+		t.Errorf("unexpected record: %+v", record)
+	}
+}
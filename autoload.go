@@ -0,0 +1,83 @@
+package envfile
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// autoloadVariable turns Autoload off when set to a false value, e.g. ENVFILE_AUTOLOAD=0.
+const autoloadVariable = "ENVFILE_AUTOLOAD"
+
+var (
+	// autoloadOnce loads the default file once for all callers of Autoload
+	autoloadOnce sync.Once
+
+	// autoloadErr is the error of the first Autoload
+	autoloadErr error
+
+	// autoloadDisabled is set by Disable
+	autoloadDisabled atomic.Bool
+)
+
+// Autoload loads the default file of the working directory and the fragments of .envfile.d once, as Load does
+// without file names, later calls return the result of the first one. It is safe to call from init functions
+// of frameworks and does nothing if no file exists, if Disable was called or if ENVFILE_AUTOLOAD is set
+// to a false value. Importing the autoload package calls it on init.
+func Autoload() error {
+
+	// automatic loading is turned off
+	if !autoloadEnabled() {
+		return nil
+	}
+
+	// load default file once
+	autoloadOnce.Do(func() {
+
+		// existing default files
+		var filenames []string
+
+		// iterating over default file and drop-in fragments
+		for _, filename := range std.defaultNames() {
+
+			// file exists
+			if _, err := os.Stat(filename); err == nil {
+				filenames = append(filenames, filename)
+			}
+		}
+
+		// nothing to load
+		if len(filenames) == 0 {
+			return
+		}
+
+		autoloadErr = std.Load(filenames...)
+	})
+
+	return autoloadErr
+}
+
+// Disable turns Autoload off for the rest of the process, e.g. in tests that control the environment themselves.
+func Disable() {
+	autoloadDisabled.Store(true)
+}
+
+// autoloadEnabled reports whether Autoload is neither disabled nor turned off by the environment.
+func autoloadEnabled() bool {
+
+	// disabled by the program
+	if autoloadDisabled.Load() {
+		return false
+	}
+
+	// variable is not set
+	value, ok := os.LookupEnv(autoloadVariable)
+	if !ok {
+		return true
+	}
+
+	// disabled by the environment, yes/no and on/off are accepted as for feature flags
+	enabled, valid := parseFlag(value)
+
+	return !valid || enabled
+}
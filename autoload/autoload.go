@@ -0,0 +1,13 @@
+//go:build !envfile_noautoload
+
+package autoload
+
+import "github.com/afonichev/envfile"
+
+// init loads the default file before the init functions of the importing packages,
+// a file that can't be loaded is a configuration error the program can't start with.
+func init() {
+	if err := envfile.Autoload(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,7 @@
+// Package autoload loads the default env file of the working directory when it is imported:
+//
+//	import _ "github.com/afonichev/envfile/autoload"
+//
+// Loading is skipped if the program is built with the envfile_noautoload tag or
+// if ENVFILE_AUTOLOAD is set to a false value, e.g. ENVFILE_AUTOLOAD=0.
+package autoload
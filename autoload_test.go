@@ -0,0 +1,138 @@
+package envfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// TestAutoload tests loading of the default file once.
+func TestAutoload(t *testing.T) {
+
+	// temporary directory
+	dir, err := ioutil.TempDir("", "autoload")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v", err)
+	}
+
+	// deferred removal of the directory
+	defer os.RemoveAll(dir)
+
+	// current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("error getting working directory: %v", err)
+	}
+
+	// change working directory
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("error changing working directory: %v", err)
+	}
+
+	// deferred restore of working directory
+	defer os.Chdir(wd)
+
+	// default file
+	ioutil.WriteFile(filepath.Join(dir, defaultFilename), []byte("export AUTOLOAD_KEY = first\n"), 0644)
+	defer os.Unsetenv("AUTOLOAD_KEY")
+
+	// load default file
+	if err := Autoload(); err != nil {
+		t.Fatalf("error autoloading: %v", err)
+	}
+
+	// changed default file is not loaded again
+	ioutil.WriteFile(filepath.Join(dir, defaultFilename), []byte("export AUTOLOAD_KEY = second\n"), 0644)
+	os.Unsetenv("AUTOLOAD_KEY")
+
+	// second call does nothing
+	if err := Autoload(); err != nil {
+		t.Fatalf("error autoloading: %v", err)
+	}
+
+	// variable is not set again
+	if value, ok := os.LookupEnv("AUTOLOAD_KEY"); ok {
+		t.Errorf("expected the file to be loaded once, got %s", value)
+	}
+}
+
+// TestAutoloadEnabled tests turning Autoload off by the environment and by Disable.
+func TestAutoloadEnabled(t *testing.T) {
+
+	// deferred unset of the variable
+	defer os.Unsetenv(autoloadVariable)
+
+	// cases of the variable
+	for value, expected := range map[string]bool{"1": true, "on": true, "0": false, "off": false, "false": false, "unknown": true} {
+
+		// set variable
+		os.Setenv(autoloadVariable, value)
+
+		// enabled state
+		if enabled := autoloadEnabled(); enabled != expected {
+			t.Errorf("expected %v for %s=%s, got %v", expected, autoloadVariable, value, enabled)
+		}
+	}
+
+	// variable is not set
+	os.Unsetenv(autoloadVariable)
+
+	// deferred enabling of Autoload
+	defer autoloadDisabled.Store(false)
+
+	// disable Autoload
+	Disable()
+
+	// disabled state
+	if autoloadEnabled() {
+		t.Errorf("expected Autoload to be disabled")
+	}
+}
+
+// TestAutoloadDropIn tests loading of the drop-in fragments without the default file.
+func TestAutoloadDropIn(t *testing.T) {
+
+	// autoload state is reset for the test and after it
+	autoloadOnce, autoloadErr = sync.Once{}, nil
+	defer func() { autoloadOnce, autoloadErr = sync.Once{}, nil }()
+
+	// temporary directory
+	dir, err := ioutil.TempDir("", "autoload")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v", err)
+	}
+
+	// deferred removal of the directory
+	defer os.RemoveAll(dir)
+
+	// current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("error getting working directory: %v", err)
+	}
+
+	// change working directory
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("error changing working directory: %v", err)
+	}
+
+	// deferred restore of working directory
+	defer os.Chdir(wd)
+
+	// fragment of the drop-in directory
+	os.Mkdir(filepath.Join(dir, dropInDir), 0755)
+	ioutil.WriteFile(filepath.Join(dir, dropInDir, "10-base.envfile"), []byte("export AUTOLOAD_FRAGMENT = fragment\n"), 0644)
+	defer os.Unsetenv("AUTOLOAD_FRAGMENT")
+
+	// load fragments
+	if err := Autoload(); err != nil {
+		t.Fatalf("error autoloading: %v", err)
+	}
+
+	// key of the fragment is set
+	if value := os.Getenv("AUTOLOAD_FRAGMENT"); value != "fragment" {
+		t.Errorf("expected AUTOLOAD_FRAGMENT to be fragment, got '%s'", value)
+	}
+}
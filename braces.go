@@ -0,0 +1,30 @@
+package envfile
+
+import "github.com/afonichev/envfile/parser"
+
+// BraceMode is the way literal curly braces are written in values of the default dialect.
+type BraceMode = parser.BraceMode
+
+const (
+
+	// BracesDoubled takes {{ and }} as literal curly braces.
+	BracesDoubled = parser.BracesDoubled
+
+	// BracesBackslash also takes \{ and \} as literal curly braces.
+	BracesBackslash = parser.BracesBackslash
+
+	// BracesLenient takes \{ and \} as literal curly braces and leaves braces that do not
+	// enclose a variable name, a JSON path or a secret URI untouched, so Go templates
+	// and JSON snippets are written as they are; {{ and }} are not escapes in this mode,
+	// braces next to other braces are always literal.
+	BracesLenient = parser.BracesLenient
+)
+
+// WithBraces sets the way literal curly braces are written in values of the default dialect.
+func WithBraces(mode BraceMode) Option {
+	return func(l *Loader) {
+
+		// set mode
+		l.braces = mode
+	}
+}
@@ -0,0 +1,73 @@
+package envfile
+
+import (
+	"testing"
+)
+
+// TestBraces tests literal curly braces written in the modes.
+func TestBraces(t *testing.T) {
+
+	// file content
+	filename := createFile(t, `
+NAME = world
+ESCAPED = \{ { NAME } \} {{x}} \\{ NAME }
+TEMPLATE = {{ .Name }} { NAME }
+JSON = {"name": "{ NAME }", "list": [1, 2]}
+`)
+
+	// expected values by mode
+	expected := map[BraceMode]map[string]string{
+		BracesBackslash: {
+			"ESCAPED": `{ world } {x} \world`,
+		},
+		BracesLenient: {
+			"ESCAPED":  `{ world } {{x}} \world`,
+			"TEMPLATE": "{{ .Name }} world",
+			"JSON":     `{"name": "world", "list": [1, 2]}`,
+		},
+	}
+
+	// iterating over modes
+	for mode, values := range expected {
+
+		// parse file
+		payloads, err := NewLoader(WithBraces(mode)).Parse(filename)
+
+		// only the lenient mode accepts single curly braces
+		if mode != BracesLenient {
+
+			// error of the first value with single curly braces
+			if err == nil {
+				t.Errorf("mode %d: single curly braces but parse didn't return an error", mode)
+			}
+
+			// parse the line with escapes only
+			payloads, err = NewLoader(WithBraces(mode)).Parse(createFile(t, "NAME = world\nESCAPED = \\{ { NAME } \\} {{x}} \\\\{ NAME }\n"))
+		}
+
+		// parse error
+		if err != nil {
+			t.Fatalf("mode %d: error parsing env file: %v", mode, err)
+		}
+
+		// iterating over expected values
+		for key, value := range values {
+
+			// value is different from expected
+			if actual, _ := payloads.Lookup(key); actual.Value != value {
+				t.Errorf("mode %d: expected %s = %s, got %s", mode, key, value, actual.Value)
+			}
+		}
+	}
+
+	// document references in the lenient mode
+	doc, err := NewLoader(WithBraces(BracesLenient)).ParseDocument(filename)
+	if err != nil {
+		t.Fatalf("error parsing document: %v", err)
+	}
+
+	// references of the JSON value
+	if refs := doc.Nodes[4].References; len(refs) != 1 || refs[0].Name != "NAME" || refs[0].Span != (Span{Start: 17, End: 25}) {
+		t.Errorf("unexpected references: %+v", refs)
+	}
+}
@@ -0,0 +1,18 @@
+package envfile
+
+import "github.com/afonichev/envfile/parser"
+
+// PayloadBuilder builds a payload checking the rules of the parser as it goes,
+// the first problem is kept and returned by Build.
+type PayloadBuilder = parser.PayloadBuilder
+
+// NewPayload starts building the payload of the key, e.g. NewPayload("PORT").Export().Value("8080").
+func NewPayload(key string) *PayloadBuilder {
+	return parser.NewPayload(key)
+}
+
+// NewDocument returns an empty document of the default dialect, payloads are added with Add
+// and the text is written by Bytes.
+func NewDocument() *Document {
+	return parser.NewDocument()
+}
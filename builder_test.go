@@ -0,0 +1,46 @@
+package envfile
+
+import "testing"
+
+// TestPayloadBuilder tests building of payloads and documents.
+func TestPayloadBuilder(t *testing.T) {
+
+	// build payload
+	payload, err := NewPayload("PORT").Export().Value("8080").Build()
+	if err != nil {
+		t.Fatalf("error building payload: %v", err)
+	}
+
+	// built payload
+	if payload.Key != "PORT" || payload.Value != "8080" || !payload.Export || payload.Kind != KindInt {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+
+	// problems found while building
+	for name, builder := range map[string]*PayloadBuilder{
+		"invalid key":            NewPayload("BAD-KEY").Value("value"),
+		"empty key":              NewPayload(""),
+		"carriage return":        NewPayload("KEY").Value("a\rb"),
+		"overloaded conditional": NewPayload("KEY").Conditional().Overload(),
+	} {
+		if _, err := builder.Build(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	// document of built payloads
+	doc := NewDocument()
+	if err := doc.Add(NewPayload("HOST").Export().Value("localhost"), NewPayload("TEMPLATE").Value("{ not a reference }")); err != nil {
+		t.Fatalf("error adding payloads: %v", err)
+	}
+
+	// duplicate key
+	if err := doc.Add(NewPayload("LATER"), NewPayload("HOST")); err == nil {
+		t.Error("key is duplicated but add didn't return an error")
+	}
+
+	// text of the document
+	if expected := "export HOST = localhost\nTEMPLATE = {{ not a reference }}\n"; string(doc.Bytes()) != expected {
+		t.Errorf("expected %q, got %q", expected, doc.Bytes())
+	}
+}
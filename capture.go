@@ -0,0 +1,85 @@
+package envfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// Capture writes the selected variables of the process environment into the file, see Loader.Capture.
+func Capture(filename string, patterns ...string) error {
+	return NewLoader().Capture(filename, patterns...)
+}
+
+// Capture writes the variables of the environment matching any of the patterns into the file as exported
+// keys in alphabetical order, so the current environment can be restored by Load. Patterns are keys or
+// globs of path.Match, e.g. "HOME" or "DB_*". The file is readable by its owner only, since variables
+// often hold secrets.
+func (l *Loader) Capture(filename string, patterns ...string) error {
+
+	// variables of the environment
+	environ, err := l.environ()
+	if err != nil {
+		return err
+	}
+
+	// selected variables
+	values := make(map[string]string)
+
+	// iterating over variables of the environment
+	for _, variable := range environ {
+
+		// name and value of the variable
+		key, value, ok := strings.Cut(variable, "=")
+		if !ok || !parser.ValidKey(key) {
+			continue
+		}
+
+		// iterating over patterns
+		for _, pattern := range patterns {
+
+			// variable matches the pattern
+			matched, err := path.Match(pattern, key)
+			if err != nil {
+				return err
+			}
+			if matched {
+				values[key] = value
+				break
+			}
+		}
+	}
+
+	// content of the file
+	content, err := MarshalMap(values)
+	if err != nil {
+		return err
+	}
+
+	// temporary file readable by its owner only, an existing file would keep its mode
+	file, err := ioutil.TempFile(filepath.Dir(filename), filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+
+	// deferred removal of the temporary file, if it's not renamed
+	defer os.Remove(file.Name())
+
+	// write content
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		return err
+	}
+
+	// close file
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	// replace file
+	return os.Rename(file.Name(), filename)
+}
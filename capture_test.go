@@ -0,0 +1,68 @@
+package envfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// TestCapture tests writing of selected variables into a file.
+func TestCapture(t *testing.T) {
+
+	// temporary directory
+	dir, err := ioutil.TempDir("", "capture")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v", err)
+	}
+
+	// deferred removal of the directory
+	defer os.RemoveAll(dir)
+
+	// environment of the loader
+	env := MapEnvironment{"CAPTURE_DB_HOST": "db", "CAPTURE_DB_PORT": "5432", "CAPTURE_NAME": " app ", "OTHER": "other"}
+
+	// capture variables
+	filename := filepath.Join(dir, ".envfile")
+	if err := NewLoader(WithEnvironment(env)).Capture(filename, "CAPTURE_DB_*", "CAPTURE_NAME"); err != nil {
+		t.Fatalf("error capturing environment: %v", err)
+	}
+
+	// content of the file
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("error reading captured file: %v", err)
+	}
+
+	// selected variables are exported in alphabetical order
+	if expected := "export CAPTURE_DB_HOST = db\nexport CAPTURE_DB_PORT = 5432\nexport CAPTURE_NAME = \" app \"\n"; string(content) != expected {
+		t.Errorf("expected %q, got %q", expected, content)
+	}
+
+	// existing file readable by everyone
+	if err := os.Chmod(filename, 0644); err != nil {
+		t.Fatalf("error changing mode of file: %v", err)
+	}
+
+	// capture variables over the existing file
+	if err := NewLoader(WithEnvironment(env)).Capture(filename, "CAPTURE_NAME"); err != nil {
+		t.Fatalf("error capturing environment: %v", err)
+	}
+
+	// mode of the captured file
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatalf("error reading mode of captured file: %v", err)
+	}
+
+	// captured file is readable by its owner only
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("expected captured file with mode 0600, got %v", info.Mode().Perm())
+	}
+
+	// invalid pattern
+	if err := NewLoader(WithEnvironment(env)).Capture(filename, "["); err == nil {
+		t.Error("pattern is invalid but capture didn't return an error")
+	}
+}
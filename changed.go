@@ -0,0 +1,131 @@
+package envfile
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// fileStamp is the state of a file when it was loaded.
+type fileStamp struct {
+
+	// file size
+	size int64
+
+	// modification time
+	modTime time.Time
+
+	// hash of the content
+	hash string
+}
+
+// Changed reports whether any of the files has changed since the last package-level Load,
+// see Loader.Changed.
+func Changed(filenames ...string) (bool, error) {
+	return std.Changed(filenames...)
+}
+
+// Changed reports whether any of the files has changed since the last Load, the files of the last
+// Load are checked if no file names are given. Files with the same size and modification time are
+// unchanged without reading them, otherwise their content is compared by hash. Files that were not
+// loaded, removed files and remote configurations are always reported as changed.
+func (l *Loader) Changed(filenames ...string) (bool, error) {
+
+	// lock stamps
+	l.mu.Lock()
+
+	// stamps of the last loading
+	stamps := l.stamps
+
+	// files of the last loading
+	if len(filenames) == 0 && l.result != nil {
+		filenames = l.result.Files
+	}
+
+	// unlock stamps
+	l.mu.Unlock()
+
+	// nothing was loaded
+	if len(filenames) == 0 {
+		return true, nil
+	}
+
+	// iterating over a list of filenames
+	for _, filename := range filenames {
+
+		// state of the file when it was loaded
+		stamp, ok := stamps[filename]
+		if !ok {
+			return true, nil
+		}
+
+		// file is changed or can't be checked
+		if changed, err := stamp.changed(filename); changed || err != nil {
+			return changed, err
+		}
+	}
+
+	return false, nil
+}
+
+// changed reports whether the file has changed since the state was taken, files with the same size
+// and modification time are unchanged without reading them.
+func (s fileStamp) changed(filename string) (bool, error) {
+
+	// current state of the file
+	info, err := os.Stat(filename)
+
+	// file is removed
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+
+	// file can't be checked
+	if err != nil {
+		return false, err
+	}
+
+	// size and modification time are the same
+	if info.Size() == s.size && info.ModTime().Equal(s.modTime) {
+		return false, nil
+	}
+
+	// current content of the file
+	current, err := stampFile(filename)
+	if err != nil {
+		return false, err
+	}
+
+	return current.hash != s.hash, nil
+}
+
+// stampFile returns the current state of the file.
+func stampFile(filename string) (fileStamp, error) {
+
+	// file information
+	info, err := os.Stat(filename)
+	if err != nil {
+		return fileStamp{}, err
+	}
+
+	// file content
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return fileStamp{}, err
+	}
+
+	return fileStamp{size: info.Size(), modTime: info.ModTime(), hash: hashValue(string(content))}, nil
+}
+
+// setStamps stores the states of the loaded files.
+func (l *Loader) setStamps(stamps map[string]fileStamp) {
+
+	// lock stamps
+	l.mu.Lock()
+
+	// deferred unlock of stamps
+	defer l.mu.Unlock()
+
+	// update stamps
+	l.stamps = stamps
+}
@@ -0,0 +1,67 @@
+package envfile
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestChanged tests detection of changed files since the last loading.
+func TestChanged(t *testing.T) {
+
+	// deferred removal of the environment variable
+	defer os.Unsetenv("ENVFILE_CHANGED")
+
+	// file content
+	filename := createFile(t, "export ENVFILE_CHANGED = first\n")
+
+	// loader
+	loader := NewLoader()
+
+	// nothing is loaded yet
+	if changed, err := loader.Changed(filename); err != nil || !changed {
+		t.Errorf("expected file to be changed before loading, got %v, %v", changed, err)
+	}
+
+	// load file
+	if err := loader.Load(filename); err != nil {
+		t.Fatalf("error loading env file: %v", err)
+	}
+
+	// file is unchanged
+	if changed, err := loader.Changed(); err != nil || changed {
+		t.Errorf("expected file to be unchanged, got %v, %v", changed, err)
+	}
+
+	// modification time in the future
+	future := time.Now().Add(time.Hour)
+
+	// file is touched without changing its content
+	if err := os.Chtimes(filename, future, future); err != nil {
+		t.Fatalf("error changing file times: %v", err)
+	}
+
+	// content is compared by hash
+	if changed, err := loader.Changed(filename); err != nil || changed {
+		t.Errorf("expected touched file to be unchanged, got %v, %v", changed, err)
+	}
+
+	// content of the same size is changed
+	if err := ioutil.WriteFile(filename, []byte("export ENVFILE_CHANGED = other\n"), 0644); err != nil {
+		t.Fatalf("error writing env file: %v", err)
+	}
+
+	// file is changed
+	if changed, err := loader.Changed(filename); err != nil || !changed {
+		t.Errorf("expected file to be changed, got %v, %v", changed, err)
+	}
+
+	// file is removed
+	os.Remove(filename)
+
+	// removed file is changed
+	if changed, err := loader.Changed(); err != nil || !changed {
+		t.Errorf("expected removed file to be changed, got %v, %v", changed, err)
+	}
+}
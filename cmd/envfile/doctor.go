@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/afonichev/envfile"
+)
+
+// doctor checks the default file and the drop-in fragments of the directory: files that can't be parsed
+// are errors, fragments with the same numeric prefix are warnings since their order depends on names only.
+func doctor(w io.Writer, dir string) error {
+
+	// fragments of the drop-in directory
+	fragments, err := envfile.DropInFiles(filepath.Join(dir, ".envfile.d"))
+	if err != nil {
+		return err
+	}
+
+	// files in order of loading
+	var files []string
+
+	// default file
+	if filename := filepath.Join(dir, ".envfile"); fileExists(filename) {
+		files = append(files, filename)
+	}
+
+	// add fragments
+	files = append(files, fragments...)
+
+	// nothing to check
+	if len(files) == 0 {
+		return fmt.Errorf("neither .envfile nor .envfile.d is found in %s", dir)
+	}
+
+	// loader reporting every problem of a file
+	loader := envfile.NewLoader(envfile.WithContinueOnError())
+
+	// number of files that can't be parsed
+	var failed int
+
+	// iterating over files
+	for _, filename := range files {
+
+		// parse file
+		_, err := loader.Parse(filename)
+		if err == nil {
+			continue
+		}
+
+		// problems of the file
+		errs := []error{err}
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			errs = joined.Unwrap()
+		}
+
+		// iterating over problems
+		for _, err := range errs {
+			fmt.Fprintf(w, "error: %s\n", err)
+		}
+
+		failed++
+	}
+
+	// iterating over ordering collisions
+	for _, collision := range envfile.DropInCollisions(fragments) {
+		fmt.Fprintf(w, "warning: %v share the numeric prefix, they are ordered by name\n", collision)
+	}
+
+	// some files can't be parsed
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files can't be parsed", failed, len(files))
+	}
+
+	// files are fine
+	fmt.Fprintf(w, "%d files are checked\n", len(files))
+
+	return nil
+}
+
+// fileExists reports whether the file exists.
+func fileExists(filename string) bool {
+
+	// state of the file
+	_, err := os.Stat(filename)
+
+	return err == nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDoctor tests checking of the default file and the fragments.
+func TestDoctor(t *testing.T) {
+
+	// temporary directory
+	dir, err := ioutil.TempDir("", "doctor")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v", err)
+	}
+
+	// deferred removal of the directory
+	defer os.RemoveAll(dir)
+
+	// default file and fragments with the same prefix
+	os.MkdirAll(filepath.Join(dir, ".envfile.d"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, ".envfile"), []byte("export HOST = localhost\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, ".envfile.d", "20-db.envfile"), []byte("export DB = db\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, ".envfile.d", "20-cache.envfile"), []byte("export CACHE = cache\n"), 0644)
+
+	// report of the doctor
+	var output bytes.Buffer
+
+	// check files
+	if err := doctor(&output, dir); err != nil {
+		t.Fatalf("error checking files: %v", err)
+	}
+
+	// collision is reported as a warning
+	if !strings.Contains(output.String(), "warning:") || !strings.Contains(output.String(), "3 files are checked") {
+		t.Errorf("expected a warning about the collision, got %q", output.String())
+	}
+
+	// invalid fragment
+	ioutil.WriteFile(filepath.Join(dir, ".envfile.d", "30-invalid.envfile"), []byte("invalid line\n"), 0644)
+
+	// invalid fragment is an error
+	if err := doctor(&output, dir); err == nil {
+		t.Error("fragment is invalid but doctor didn't return an error")
+	}
+}
@@ -0,0 +1,833 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/afonichev/envfile"
+)
+
+// request is a JSON-RPC request or notification.
+type request struct {
+
+	// request identifier, missing for notifications
+	ID json.RawMessage `json:"id,omitempty"`
+
+	// method name
+	Method string `json:"method"`
+
+	// method parameters
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// response is a JSON-RPC response.
+type response struct {
+
+	// protocol version
+	JSONRPC string `json:"jsonrpc"`
+
+	// request identifier
+	ID json.RawMessage `json:"id"`
+
+	// method result
+	Result json.RawMessage `json:"result,omitempty"`
+
+	// method error
+	Error *responseError `json:"error,omitempty"`
+}
+
+// responseError is a JSON-RPC error.
+type responseError struct {
+
+	// error code
+	Code int `json:"code"`
+
+	// error message
+	Message string `json:"message"`
+}
+
+// notification is a JSON-RPC notification sent to the client.
+type notification struct {
+
+	// protocol version
+	JSONRPC string `json:"jsonrpc"`
+
+	// method name
+	Method string `json:"method"`
+
+	// method parameters
+	Params interface{} `json:"params"`
+}
+
+// position is a place in the text document: line and UTF-16 character counted from zero.
+type position struct {
+
+	// line number
+	Line int `json:"line"`
+
+	// character in the line
+	Character int `json:"character"`
+}
+
+// textRange is a part of the text document.
+type textRange struct {
+
+	// first position
+	Start position `json:"start"`
+
+	// position after the last one
+	End position `json:"end"`
+}
+
+// textDocument identifies the text document and carries its text when it is opened.
+type textDocument struct {
+
+	// document URI
+	URI string `json:"uri"`
+
+	// document text
+	Text string `json:"text,omitempty"`
+}
+
+// contentChange is a change of the text document.
+type contentChange struct {
+
+	// changed range, missing when the whole text is replaced
+	Range *textRange `json:"range,omitempty"`
+
+	// new text
+	Text string `json:"text"`
+}
+
+// initializeParams are parameters of the initialize request.
+type initializeParams struct {
+
+	// URI of the workspace directory
+	RootURI string `json:"rootUri"`
+
+	// path to the workspace directory, deprecated by the protocol in favor of rootUri
+	RootPath string `json:"rootPath"`
+}
+
+// documentParams are parameters of the text document notifications and requests.
+type documentParams struct {
+
+	// text document
+	TextDocument textDocument `json:"textDocument"`
+
+	// changes of the text document
+	ContentChanges []contentChange `json:"contentChanges,omitempty"`
+
+	// position in the text document
+	Position position `json:"position"`
+}
+
+// diagnostic is a problem in the text document.
+type diagnostic struct {
+
+	// place of the problem
+	Range textRange `json:"range"`
+
+	// severity, 1 is error
+	Severity int `json:"severity"`
+
+	// source of the problem
+	Source string `json:"source"`
+
+	// message
+	Message string `json:"message"`
+}
+
+// location is a place in a text document.
+type location struct {
+
+	// document URI
+	URI string `json:"uri"`
+
+	// place in the document
+	Range textRange `json:"range"`
+}
+
+// errorLine finds the line number in error messages.
+var errorLine = regexp.MustCompile(`line (\d+):`)
+
+// server is a minimal language server for files with environment variables.
+type server struct {
+
+	// input stream
+	reader *bufio.Reader
+
+	// output stream
+	writer io.Writer
+
+	// loader used to resolve values
+	loader *envfile.Loader
+
+	// open documents by URI
+	documents map[string]*envfile.Document
+
+	// workspace directory with the default files, none if empty
+	root string
+
+	// references missing in the workspace are looked up in the environment of the server process
+	environment bool
+
+	// shutdown request status
+	shutdown bool
+}
+
+// newServer creates a language server reading requests from the reader and writing responses to the writer,
+// references are resolved from the workspace and, if environment is set, from the environment of the process.
+func newServer(reader io.Reader, writer io.Writer, environment bool) *server {
+
+	// server
+	s := &server{
+		reader:      bufio.NewReader(reader),
+		writer:      writer,
+		documents:   make(map[string]*envfile.Document),
+		environment: environment,
+	}
+
+	// loader resolving references from the workspace
+	s.loader = envfile.NewLoader(envfile.WithEnvironment(workspace{s}))
+
+	return s
+}
+
+// serve handles requests until the exit notification or the end of input.
+func (s *server) serve() error {
+
+	// header reader
+	headers := textproto.NewReader(s.reader)
+
+	for {
+
+		// read message headers
+		header, err := headers.ReadMIMEHeader()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// length of message content
+		length, err := strconv.Atoi(header.Get("Content-Length"))
+		if err != nil {
+			return fmt.Errorf("invalid Content-Length header: %s", err)
+		}
+
+		// message content
+		content := make([]byte, length)
+
+		// read message content
+		if _, err := io.ReadFull(s.reader, content); err != nil {
+			return err
+		}
+
+		// request
+		var req request
+
+		// decode request
+		if err := json.Unmarshal(content, &req); err != nil {
+			return err
+		}
+
+		// exit notification
+		if req.Method == "exit" {
+			return nil
+		}
+
+		// handle request
+		result, err := s.handle(req)
+
+		// notifications have no response
+		if req.ID == nil {
+			continue
+		}
+
+		// response
+		resp := response{JSONRPC: "2.0", ID: req.ID}
+
+		// request failed
+		if err != nil {
+			resp.Error = &responseError{Code: -32603, Message: err.Error()}
+		} else {
+
+			// encode result
+			if resp.Result, err = json.Marshal(result); err != nil {
+				return err
+			}
+		}
+
+		// send response
+		if err := s.send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// send writes the message with headers.
+func (s *server) send(message interface{}) error {
+
+	// encode message
+	content, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	// write message
+	_, err = fmt.Fprintf(s.writer, "Content-Length: %d\r\n\r\n%s", len(content), content)
+
+	return err
+}
+
+// handle handles the request and returns its result.
+func (s *server) handle(req request) (interface{}, error) {
+
+	// request parameters
+	var params documentParams
+
+	// decode parameters of text document methods
+	if strings.HasPrefix(req.Method, "textDocument/") {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, err
+		}
+	}
+
+	switch req.Method {
+
+	// initialization
+	case "initialize":
+
+		// parameters of the request
+		var init initializeParams
+
+		// decode parameters
+		if len(req.Params) > 0 {
+			if err := json.Unmarshal(req.Params, &init); err != nil {
+				return nil, err
+			}
+		}
+
+		// workspace directory
+		s.root = init.RootPath
+		if len(init.RootURI) > 0 {
+			s.root = documentPath(init.RootURI)
+		}
+
+		return map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync":   2,
+				"hoverProvider":      true,
+				"definitionProvider": true,
+			},
+			"serverInfo": map[string]string{"name": "envfile"},
+		}, nil
+
+	// shutdown
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	// document is opened
+	case "textDocument/didOpen":
+
+		// path to the document
+		path := documentPath(params.TextDocument.URI)
+
+		// empty document
+		doc := &envfile.Document{Name: path}
+
+		// fill document with text
+		if _, err := doc.ApplyEdit(envfile.Range{Start: envfile.Position{Line: 1}, End: envfile.Position{Line: 1}},
+			params.TextDocument.Text); err != nil {
+			return nil, err
+		}
+
+		// store document
+		s.documents[params.TextDocument.URI] = doc
+
+		return nil, s.publishDiagnostics(params.TextDocument.URI)
+
+	// document is changed
+	case "textDocument/didChange":
+
+		// document
+		doc, ok := s.documents[params.TextDocument.URI]
+		if !ok {
+			return nil, fmt.Errorf("document '%s' is not open", params.TextDocument.URI)
+		}
+
+		// iterating over a list of changes
+		for _, change := range params.ContentChanges {
+
+			// apply change
+			if _, err := doc.ApplyEdit(editRange(doc, change.Range), change.Text); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, s.publishDiagnostics(params.TextDocument.URI)
+
+	// document is closed
+	case "textDocument/didClose":
+		delete(s.documents, params.TextDocument.URI)
+		return nil, nil
+
+	// hover
+	case "textDocument/hover":
+		return s.hover(params), nil
+
+	// go to definition
+	case "textDocument/definition":
+		return s.definition(params), nil
+
+	// any
+	default:
+
+		// unknown request
+		if req.ID != nil {
+			return nil, fmt.Errorf("method '%s' is not supported", req.Method)
+		}
+
+		return nil, nil
+	}
+}
+
+// publishDiagnostics sends problems of the document to the client.
+func (s *server) publishDiagnostics(uri string) error {
+
+	// document
+	doc := s.documents[uri]
+
+	// problems list
+	diagnostics := []diagnostic{}
+
+	// iterating over a list of nodes
+	for _, node := range doc.Nodes {
+
+		// line has a problem
+		if len(node.Error) > 0 {
+			diagnostics = append(diagnostics, lineDiagnostic(doc, node.Line, node.Error))
+		}
+	}
+
+	// resolve values when every line is valid
+	if len(diagnostics) == 0 {
+
+		// resolve document
+		if _, err := s.loader.Resolve(doc); err != nil {
+
+			// line of the problem
+			line := 1
+			if match := errorLine.FindStringSubmatch(err.Error()); match != nil {
+				line, _ = strconv.Atoi(match[1])
+			}
+
+			// add problem to list
+			diagnostics = append(diagnostics, lineDiagnostic(doc, line, err.Error()))
+		}
+	}
+
+	return s.send(notification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params: map[string]interface{}{
+			"uri":         uri,
+			"diagnostics": diagnostics,
+		},
+	})
+}
+
+// hover returns the resolved value of the key or reference under the cursor, hiding secrets.
+func (s *server) hover(params documentParams) interface{} {
+
+	// document, node and column under the cursor
+	doc, node, column := s.locate(params)
+	if node == nil || node.Kind != envfile.NodeEntry {
+		return nil
+	}
+
+	// resolve document
+	payloads, err := s.loader.Resolve(doc)
+	if err != nil {
+		return nil
+	}
+
+	// key and its value
+	var key, value string
+
+	// position of the hovered text
+	span := node.KeySpan
+
+	// cursor is on the key
+	if column >= node.KeySpan.Start && column <= node.KeySpan.End {
+
+		// iterating over a list of payloads
+		for _, payload := range payloads {
+
+			// payload is defined on the line
+			if payload.Line == node.Line {
+				key, value = payload.Key, payload.Value
+			}
+		}
+	}
+
+	// iterating over references of the value
+	for _, reference := range node.References {
+
+		// cursor is on the reference
+		if column >= reference.Span.Start && column < reference.Span.End {
+
+			// update key and position
+			key, span = reference.Name, reference.Span
+
+			// value from the list of payloads or the workspace
+			value = s.lookup(payloads, reference.Name)
+		}
+	}
+
+	// nothing is hovered
+	if len(key) == 0 {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"contents": map[string]string{
+			"kind":  "markdown",
+			"value": fmt.Sprintf("`%s` = `%s`", key, s.loader.Redact(key, value)),
+		},
+		"range": spanRange(doc, node.Line, span),
+	}
+}
+
+// definition returns the location of the key referenced under the cursor.
+func (s *server) definition(params documentParams) interface{} {
+
+	// document, node and column under the cursor
+	doc, node, column := s.locate(params)
+	if node == nil {
+		return nil
+	}
+
+	// iterating over references of the value
+	for _, reference := range node.References {
+
+		// cursor is not on the reference
+		if column < reference.Span.Start || column >= reference.Span.End {
+			continue
+		}
+
+		// referenced variable, without the path to a JSON field
+		variable := strings.SplitN(reference.Name, ".", 2)[0]
+
+		// iterating over a list of nodes
+		for _, def := range doc.Nodes {
+
+			// key is defined on the line
+			if def.Kind == envfile.NodeEntry && def.Key == variable {
+				return location{
+					URI:   params.TextDocument.URI,
+					Range: spanRange(doc, def.Line, def.KeySpan),
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// locate returns the document, the node and the byte column under the cursor.
+func (s *server) locate(params documentParams) (*envfile.Document, *envfile.Node, int) {
+
+	// document
+	doc, ok := s.documents[params.TextDocument.URI]
+
+	// document is not open or the line is outside of it
+	if !ok || params.Position.Line < 0 || params.Position.Line >= len(doc.Nodes) {
+		return nil, nil, 0
+	}
+
+	// node under the cursor
+	node := &doc.Nodes[params.Position.Line]
+
+	return doc, node, byteColumn(node.Text, params.Position.Character)
+}
+
+// lookup returns the value of the variable from the list of payloads or the workspace.
+func (s *server) lookup(payloads []envfile.Payload, variable string) string {
+
+	// variable exists in the list of payloads
+	if value, ok := findKey(payloads, variable); ok {
+		return value
+	}
+
+	// value from the workspace
+	value, _ := s.lookupWorkspace(variable)
+
+	return value
+}
+
+// lookupWorkspace returns the value of the variable from the open documents, then from the default files
+// of the workspace, then, if enabled, from the environment of the process. References of those documents
+// and files are resolved from the environment only, so documents referencing each other are not followed.
+func (s *server) lookupWorkspace(variable string) (string, bool) {
+
+	// loader of the documents and files, without the environment unless it is enabled
+	loader := envfile.NewLoader(envfile.WithEnvironment(envfile.MapEnvironment{}))
+	if s.environment {
+		loader = envfile.NewLoader()
+	}
+
+	// URIs of the open documents in order
+	uris := make([]string, 0, len(s.documents))
+	for uri := range s.documents {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+
+	// paths to the open documents
+	open := make(map[string]bool)
+
+	// iterating over open documents
+	for _, uri := range uris {
+
+		// document is open
+		open[s.documents[uri].Name] = true
+
+		// resolve document, invalid documents are reported by diagnostics
+		payloads, err := loader.Resolve(s.documents[uri])
+		if err != nil {
+			continue
+		}
+
+		// variable is defined in the document
+		if value, ok := findKey(payloads, variable); ok {
+			return value, true
+		}
+	}
+
+	// iterating over default files of the workspace
+	for _, filename := range s.defaultFiles() {
+
+		// open document is newer than the file
+		if open[filename] {
+			continue
+		}
+
+		// parse file, invalid and missing files are skipped
+		payloads, err := loader.Parse(filename)
+		if err != nil {
+			continue
+		}
+
+		// variable is defined in the file
+		if value, ok := findKey(payloads, variable); ok {
+			return value, true
+		}
+	}
+
+	// variable of the process is not used
+	if !s.environment {
+		return "", false
+	}
+
+	return os.LookupEnv(variable)
+}
+
+// defaultFiles returns the default file and the drop-in fragments of the workspace.
+func (s *server) defaultFiles() []string {
+
+	// workspace is unknown
+	if len(s.root) == 0 {
+		return nil
+	}
+
+	// fragments of the drop-in directory, a missing directory has none
+	fragments, _ := envfile.DropInFiles(filepath.Join(s.root, ".envfile.d"))
+
+	return append([]string{filepath.Join(s.root, ".envfile")}, fragments...)
+}
+
+// findKey returns the value of the key from the list of payloads.
+func findKey(payloads []envfile.Payload, key string) (string, bool) {
+
+	// iterating over a list of payloads
+	for _, payload := range payloads {
+
+		// key exists in the list of payloads
+		if payload.Key == key {
+			return payload.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// workspace is the environment of the language server, variables are keys of the workspace.
+type workspace struct {
+
+	// language server
+	server *server
+}
+
+// LookupEnv returns the value of the variable from the workspace.
+func (w workspace) LookupEnv(key string) (string, bool) {
+	return w.server.lookupWorkspace(key)
+}
+
+// Setenv fails, variables of the workspace are read-only.
+func (w workspace) Setenv(key, value string) error {
+	return errors.New("variables of the workspace are read-only")
+}
+
+// lineDiagnostic returns the problem covering the whole line.
+func lineDiagnostic(doc *envfile.Document, line int, message string) diagnostic {
+
+	// length of the line
+	length := 0
+	if line >= 1 && line <= len(doc.Nodes) {
+		length = len(doc.Nodes[line-1].Text)
+	}
+
+	return diagnostic{
+		Range:    spanRange(doc, line, envfile.Span{Start: 0, End: length}),
+		Severity: 1,
+		Source:   "envfile",
+		Message:  message,
+	}
+}
+
+// spanRange converts the span on the line into the range of the text document.
+func spanRange(doc *envfile.Document, line int, span envfile.Span) textRange {
+
+	// text of the line
+	var text string
+	if line >= 1 && line <= len(doc.Nodes) {
+		text = doc.Nodes[line-1].Text
+	}
+
+	return textRange{
+		Start: position{Line: line - 1, Character: utf16Column(text, span.Start)},
+		End:   position{Line: line - 1, Character: utf16Column(text, span.End)},
+	}
+}
+
+// editRange converts the changed range into the range of the document, the whole document if it is missing.
+func editRange(doc *envfile.Document, r *textRange) envfile.Range {
+
+	// whole document
+	if r == nil {
+
+		// empty document
+		if len(doc.Nodes) == 0 {
+			return envfile.Range{Start: envfile.Position{Line: 1}, End: envfile.Position{Line: 1}}
+		}
+
+		// last node
+		last := doc.Nodes[len(doc.Nodes)-1]
+
+		return envfile.Range{
+			Start: envfile.Position{Line: 1},
+			End:   envfile.Position{Line: last.Line, Column: len(last.Text)},
+		}
+	}
+
+	// range of the document
+	var result envfile.Range
+
+	// iterating over positions of the range
+	for i, pos := range []position{r.Start, r.End} {
+
+		// text of the line
+		var text string
+		if pos.Line < len(doc.Nodes) {
+			text = doc.Nodes[pos.Line].Text
+		}
+
+		// position of the document
+		converted := envfile.Position{Line: pos.Line + 1, Column: byteColumn(text, pos.Character)}
+
+		// set position
+		if i == 0 {
+			result.Start = converted
+		} else {
+			result.End = converted
+		}
+	}
+
+	return result
+}
+
+// byteColumn converts the UTF-16 character offset in the text into the byte column.
+func byteColumn(text string, character int) int {
+
+	// number of UTF-16 code units
+	var units int
+
+	// iteration over text
+	for i, r := range text {
+
+		// offset is reached
+		if units >= character {
+			return i
+		}
+
+		// increase number of code units
+		units += len(utf16.Encode([]rune{r}))
+	}
+
+	return len(text)
+}
+
+// utf16Column converts the byte column in the text into the UTF-16 character offset.
+func utf16Column(text string, column int) int {
+
+	// column is outside of the text
+	if column > len(text) {
+		column = len(text)
+	}
+
+	// number of UTF-16 code units
+	var units int
+
+	// iteration over text before the column
+	for _, r := range text[:column] {
+
+		// increase number of code units
+		units += len(utf16.Encode([]rune{r}))
+	}
+
+	// invalid UTF-8 is counted by bytes
+	if !utf8.ValidString(text[:column]) {
+		return column
+	}
+
+	return units
+}
+
+// documentPath converts the document URI into the file path.
+func documentPath(uri string) string {
+
+	// parse URI
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "file" {
+		return uri
+	}
+
+	return parsed.Path
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// session runs the language server on the client messages and returns the server messages.
+func session(t *testing.T, messages []string, environment bool) []map[string]interface{} {
+
+	// client input
+	var input bytes.Buffer
+
+	// iterating over client messages
+	for _, message := range messages {
+
+		// write message with headers
+		fmt.Fprintf(&input, "Content-Length: %d\r\n\r\n%s", len(message), message)
+	}
+
+	// server output
+	var output bytes.Buffer
+
+	// run server
+	if err := newServer(&input, &output, environment).serve(); err != nil {
+		t.Fatalf("error serving: %v", err)
+	}
+
+	// server messages
+	var replies []map[string]interface{}
+
+	// iterating over server messages
+	for _, part := range strings.Split(output.String(), "Content-Length: ")[1:] {
+
+		// message
+		var reply map[string]interface{}
+
+		// decode message content after headers
+		if err := json.Unmarshal([]byte(part[strings.Index(part, "\r\n\r\n")+4:]), &reply); err != nil {
+			t.Fatalf("error decoding message: %v", err)
+		}
+
+		// add message to list
+		replies = append(replies, reply)
+	}
+
+	return replies
+}
+
+// TestServer tests the language server session.
+func TestServer(t *testing.T) {
+
+	// client messages
+	messages := []string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///tmp/.envfile","text":"DB_PASSWORD = qwerty\nHOST = localhost\nexport URL = { HOST }/{ DB_PASSWORD }\n"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///tmp/.envfile"},"position":{"line":2,"character":16}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///tmp/.envfile"},"position":{"line":2,"character":25}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"textDocument/definition","params":{"textDocument":{"uri":"file:///tmp/.envfile"},"position":{"line":2,"character":16}}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didChange","params":{"textDocument":{"uri":"file:///tmp/.envfile"},"contentChanges":[{"range":{"start":{"line":1,"character":0},"end":{"line":1,"character":4}},"text":"HOSTNAME"}]}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+	}
+
+	// server messages
+	replies := session(t, messages, false)
+
+	// encoded server messages
+	encoded, _ := json.Marshal(replies)
+
+	// expected fragments of server messages
+	fragments := []string{
+		`"hoverProvider":true`,
+		`"value":"` + "`HOST` = `localhost`" + `"`,
+		`"value":"` + "`DB_PASSWORD` = `******`" + `"`,
+		`"range":{"end":{"character":4,"line":1},"start":{"character":0,"line":1}}`,
+		`variable 'HOST' does not exist`,
+	}
+
+	// iterating over expected fragments
+	for _, fragment := range fragments {
+
+		// fragment is missing
+		if !strings.Contains(string(encoded), fragment) {
+			t.Errorf("expected %s in server messages: %s", fragment, encoded)
+		}
+	}
+}
+
+// TestServerWorkspace tests that references are resolved from the open documents and the default files
+// of the workspace, and from the environment only if it is enabled.
+func TestServerWorkspace(t *testing.T) {
+
+	// workspace directory
+	root, err := ioutil.TempDir("", "workspace")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v", err)
+	}
+
+	// deferred removal of the directory
+	defer os.RemoveAll(root)
+
+	// default file of the workspace
+	if err := ioutil.WriteFile(filepath.Join(root, ".envfile"), []byte("PORT = 80\n"), 0644); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+
+	// variable of the process
+	t.Setenv("LSP_PROCESS_KEY", "process")
+
+	// client messages
+	messages := []string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"rootUri":"file://` + root + `"}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///tmp/a.envfile","text":"HOST = localhost\n"}}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///tmp/b.envfile","text":"export URL = { HOST }:{ PORT }\n"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///tmp/b.envfile"},"position":{"line":0,"character":16}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///tmp/b.envfile"},"position":{"line":0,"character":25}}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"file:///tmp/c.envfile","text":"export NAME = { LSP_PROCESS_KEY }\n"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"textDocument/hover","params":{"textDocument":{"uri":"file:///tmp/c.envfile"},"position":{"line":0,"character":18}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+	}
+
+	// expected fragments of server messages by environment status
+	expected := map[bool][]string{
+		false: {"`HOST` = `localhost`", "`PORT` = `80`", "variable 'LSP_PROCESS_KEY' does not exist"},
+		true:  {"`HOST` = `localhost`", "`PORT` = `80`", "`LSP_PROCESS_KEY` = `process`"},
+	}
+
+	// iterating over environment statuses
+	for environment, fragments := range expected {
+
+		// encoded server messages
+		encoded, _ := json.Marshal(session(t, messages, environment))
+
+		// iterating over expected fragments
+		for _, fragment := range fragments {
+
+			// fragment is missing
+			if !strings.Contains(string(encoded), fragment) {
+				t.Errorf("expected %s in server messages with environment %v: %s", fragment, environment, encoded)
+			}
+		}
+
+		// variable of the process is used without the environment
+		if !environment && strings.Contains(string(encoded), "`process`") {
+			t.Errorf("expected variable of the process to be ignored: %s", encoded)
+		}
+	}
+}
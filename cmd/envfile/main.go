@@ -0,0 +1,68 @@
+// Command envfile is a tool for files with environment variables.
+//
+// Usage:
+//
+//	envfile lsp [--environment]    run the language server on standard input and output
+//	envfile doctor [dir]           check .envfile and the fragments of .envfile.d
+//
+// The language server resolves references from the open documents and the default files of the workspace,
+// --environment also resolves the ones missing there from the environment of the server.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// usage is a help message.
+const usage = `Usage: envfile <command>
+
+Commands:
+  lsp [--environment]    run the language server on standard input and output
+  doctor [dir]           check .envfile and the fragments of .envfile.d
+`
+
+func main() {
+
+	// command is missing
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// command result
+	var err error
+
+	switch os.Args[1] {
+
+	// language server
+	case "lsp":
+
+		// references missing in the workspace are resolved from the environment
+		environment := len(os.Args) > 2 && os.Args[2] == "--environment"
+
+		err = newServer(os.Stdin, os.Stdout, environment).serve()
+
+	// check of files
+	case "doctor":
+
+		// directory of the files
+		dir := "."
+		if len(os.Args) > 2 {
+			dir = os.Args[2]
+		}
+
+		err = doctor(os.Stdout, dir)
+
+	// any
+	default:
+		fmt.Fprintf(os.Stderr, "envfile: unknown command '%s'\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	// command failed
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "envfile: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,128 @@
+package envfile
+
+import (
+	"errors"
+	"strings"
+)
+
+// SplitCommand splits the value holding a command line into words the way a POSIX shell does,
+// without expansions: words are separated by spaces, single quotes keep everything literally,
+// double quotes keep spaces and allow escaping of \, ", $ and `, a backslash outside of quotes
+// escapes the next character. The words can be passed to exec.Command.
+func SplitCommand(value string) ([]string, error) {
+
+	// words list
+	words := []string{}
+
+	// current word
+	var word strings.Builder
+
+	// current word exists, even if it is empty quotes
+	var started bool
+
+	// iteration over value
+	for i := 0; i < len(value); i++ {
+
+		// current character
+		c := value[i]
+
+		switch {
+
+		// word separator
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+
+			// word is finished
+			if started {
+				words = append(words, word.String())
+				word.Reset()
+				started = false
+			}
+
+		// escaped character
+		case c == '\\':
+
+			// backslash is the last character
+			if i+1 == len(value) {
+				return nil, errors.New("command ends with a backslash")
+			}
+
+			// escaped line break is removed
+			if value[i+1] != '\n' {
+				word.WriteByte(value[i+1])
+				started = true
+			}
+
+			// skip escaped character
+			i++
+
+		// single quoted text
+		case c == '\'':
+
+			// closing quote
+			end := strings.IndexByte(value[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("command has an unterminated single quote")
+			}
+
+			// add quoted text
+			word.WriteString(value[i+1 : i+1+end])
+			started = true
+
+			// skip quoted text
+			i += end + 1
+
+		// double quoted text
+		case c == '"':
+
+			// word exists even if quotes are empty
+			started = true
+
+			// closing quote is found
+			closed := false
+
+			// iteration over quoted text
+			for i++; i < len(value); i++ {
+
+				// closing quote
+				if value[i] == '"' {
+					closed = true
+					break
+				}
+
+				// escaped character within double quotes
+				if value[i] == '\\' && i+1 < len(value) && strings.IndexByte("\\\"$`\n", value[i+1]) >= 0 {
+
+					// escaped line break is removed
+					if value[i+1] != '\n' {
+						word.WriteByte(value[i+1])
+					}
+
+					// skip escaped character
+					i++
+
+					continue
+				}
+
+				// add character
+				word.WriteByte(value[i])
+			}
+
+			// closing quote is missing
+			if !closed {
+				return nil, errors.New("command has an unterminated double quote")
+			}
+
+		// any
+		default:
+			word.WriteByte(c)
+			started = true
+		}
+	}
+
+	// last word
+	if started {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
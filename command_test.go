@@ -0,0 +1,47 @@
+package envfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestSplitCommand tests shell-like splitting of command lines.
+func TestSplitCommand(t *testing.T) {
+
+	// expected words by command line
+	commands := map[string][]string{
+		"":                                  {},
+		"  worker  --queue default ":        {"worker", "--queue", "default"},
+		`sh -c 'echo "$HOME" && exit 1'`:    {"sh", "-c", `echo "$HOME" && exit 1`},
+		`app --name "my app" --empty ""`:    {"app", "--name", "my app", "--empty", ""},
+		`app "a \"quoted\" \$var \n"`:       {"app", `a "quoted" $var \n`},
+		`app my\ file it\'s`:                {"app", "my file", "it's"},
+		"app --flag=\"one two\"'three'four": {"app", "--flag=one twothreefour"},
+		"app \\\n  --next":                  {"app", "--next"},
+	}
+
+	// iterating over command lines
+	for command, expected := range commands {
+
+		// split command line
+		words, err := SplitCommand(command)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", command, err)
+			continue
+		}
+
+		// words are different from expected
+		if !reflect.DeepEqual(words, expected) {
+			t.Errorf("%q: expected %q, got %q", command, expected, words)
+		}
+	}
+
+	// iterating over invalid command lines
+	for _, command := range []string{`app 'open`, `app "open`, `app \`} {
+
+		// invalid command line is split
+		if _, err := SplitCommand(command); err == nil {
+			t.Errorf("%q: command is invalid but split didn't return an error", command)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package envfile
+
+// WithInlineComments strips comments written after values, as in KEY = value # explains the key.
+// A comment starts with a number sign at the start of the value or after a space, so values like
+// color#1 or URL fragments are kept; number signs inside a quoted value are part of it. The comment
+// text is kept in the Comment field of the node. Without the option the number sign and the rest
+// of the line belong to the value.
+func WithInlineComments(enabled bool) Option {
+	return func(l *Loader) {
+
+		// set inline comments status
+		l.inlineComments = enabled
+	}
+}
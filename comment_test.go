@@ -0,0 +1,69 @@
+package envfile
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// TestInlineComments tests stripping of comments after values.
+func TestInlineComments(t *testing.T) {
+
+	// content with comments after values
+	content := "PLAIN = value # explains the key\n" +
+		"COLOR = color#1\n" +
+		"EMPTY = # nothing yet\n" +
+		"DOUBLE = \"quoted # kept\" # stripped\n" +
+		"SINGLE = 'quoted # kept'\n" +
+		"HEREDOC = <<EOF # body follows\nline\nEOF\n"
+
+	// comments are part of values by default
+	payloads, err := ParseString("PLAIN = value # explains the key\n")
+	if err != nil {
+		t.Fatalf("error parsing content: %v", err)
+	}
+	if payloads[0].Value != "value # explains the key" {
+		t.Errorf("expected comment in value, got %q", payloads[0].Value)
+	}
+
+	// comments are stripped
+	payloads, err = NewLoader(WithInlineComments(true)).ParseString(content)
+	if err != nil {
+		t.Fatalf("error parsing content: %v", err)
+	}
+
+	// expected values
+	expected := map[string]string{"PLAIN": "value", "COLOR": "color#1", "EMPTY": "", "DOUBLE": "quoted # kept", "SINGLE": "quoted # kept", "HEREDOC": "line"}
+
+	// iterating over payloads
+	for _, payload := range payloads {
+		if value := expected[payload.Key]; payload.Value != value {
+			t.Errorf("expected %s = %q, got %q", payload.Key, value, payload.Value)
+		}
+	}
+
+	// comment text is kept in the node
+	if node := parser.ParseLine(parser.Syntax{Comments: true}, 1, "KEY = value # explains"); node.Comment != " explains" {
+		t.Errorf("expected comment ' explains', got %q", node.Comment)
+	}
+
+	// comment token
+	tokens, _ := NewLoader(WithInlineComments(true)).Tokenize("KEY = value # explains")
+	if expected := (Token{Kind: TokenComment, Text: "# explains", Span: Span{Start: 12, End: 22}}); len(tokens) != 4 || !reflect.DeepEqual(tokens[3], expected) {
+		t.Errorf("expected comment token %+v, got %+v", expected, tokens)
+	}
+
+	// values looking like comments are written so they are read back
+	marshaled, err := MarshalMap(map[string]string{"A": "# hash", "B": `say "hi" # there`})
+	if err != nil {
+		t.Fatalf("error marshaling values: %v", err)
+	}
+	values, err := NewLoader(WithInlineComments(true)).ParseString(string(marshaled))
+	if err != nil {
+		t.Fatalf("error parsing marshaled content: %v", err)
+	}
+	if values[0].Value != "# hash" || values[1].Value != `say "hi" # there` {
+		t.Errorf("expected values to be read back, got %+v", values)
+	}
+}
@@ -0,0 +1,84 @@
+package envfile
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestConditions tests keys of #if blocks.
+func TestConditions(t *testing.T) {
+
+	// set environment variables
+	os.Setenv("CONDITION_CI", "true")
+	os.Setenv("CONDITION_STAGE", "dev")
+
+	// deferred removal of environment variables
+	defer os.Unsetenv("CONDITION_CI")
+	defer os.Unsetenv("CONDITION_STAGE")
+
+	// file with nested blocks
+	filename := createFile(t, strings.Join([]string{
+		"#if GOOS=" + runtime.GOOS,
+		"OS = current",
+		"#else",
+		"OS = other",
+		"#endif",
+		"#if ${CONDITION_CI}",
+		"#if CONDITION_STAGE != dev",
+		"MODE = ci",
+		"#else",
+		"MODE = ci-dev",
+		"#endif",
+		"#endif",
+		"#if ${CONDITION_MISSING}",
+		"MISSING = { CONDITION_MISSING }",
+		"#endif",
+		"# if is a comment without the directive",
+		"#if needed, set MODE in the environment",
+	}, "\n"))
+
+	// parse file
+	payloads, err := Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// expected values
+	expected := map[string]string{"OS": "current", "MODE": "ci-dev"}
+
+	// all keys of true branches are parsed
+	if len(payloads) != len(expected) {
+		t.Errorf("expected %d keys, got %d", len(expected), len(payloads))
+	}
+
+	// iterating over payloads
+	for _, payload := range payloads {
+
+		// value is different from expected
+		if value, ok := expected[payload.Key]; !ok || payload.Value != value {
+			t.Errorf("expected %s to be '%s', got '%s'", payload.Key, value, payload.Value)
+		}
+	}
+
+	// files with invalid blocks
+	files := map[string]string{
+		"#if CONDITION_CI\n#endif\n":     "line 2: #endif without #if",
+		"A = 1\n#endif\n":                "line 2: #endif without #if",
+		"#if ${CONDITION_CI}\nA = 1\n":   "line 1: #if is not closed with #endif",
+		"#if ${A}\n#else\n#else\n#endif": "line 3: #else repeated in #if of line 1",
+	}
+
+	// iterating over files
+	for content, message := range files {
+
+		// parse file
+		_, err := Parse(createFile(t, content))
+
+		// error is different from expected
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("expected error '%s' for %q, got %v", message, content, err)
+		}
+	}
+}
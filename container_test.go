@@ -0,0 +1,27 @@
+package envfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestEnviron tests conversion of payloads into KEY=value strings.
+func TestEnviron(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "LOCAL = local\nexport HOST = localhost\noverload URL = http://{ HOST }/?a=b\nPORT ?= 80\n")
+
+	// parse file
+	payloads, err := Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// expected environment
+	expected := []string{"HOST=localhost", "URL=http://localhost/?a=b", "PORT=80"}
+
+	// environment is different from expected
+	if environ := payloads.Environ(); !reflect.DeepEqual(environ, expected) {
+		t.Errorf("expected %q, got %q", expected, environ)
+	}
+}
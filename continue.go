@@ -0,0 +1,13 @@
+package envfile
+
+// WithContinueOnError makes parsing go on after a line with a problem, so every invalid line, invalid
+// or duplicate key of the file is reported at once, e.g. when files are validated in CI. The error
+// joins the errors of the lines in order, they are listed by its Unwrap() []error method. References
+// are resolved only in files without such problems.
+func WithContinueOnError() Option {
+	return func(l *Loader) {
+
+		// collect all errors
+		l.continueOnError = true
+	}
+}
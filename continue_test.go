@@ -0,0 +1,52 @@
+package envfile
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestContinueOnError tests reporting of all problems of the file.
+func TestContinueOnError(t *testing.T) {
+
+	// file with several problems
+	filename := createFile(t, "KEY = value\ninvalid line\nKEY = again\nBAD-KEY = value\nNEXT = { KEY }\n")
+
+	// first problem only
+	if _, err := Parse(filename); err == nil || err.Error() != "["+filename+"] line 2: can't split line into key and value" {
+		t.Errorf("expected error of line 2, got %v", err)
+	}
+
+	// all problems
+	_, err := NewLoader(WithContinueOnError()).Parse(filename)
+
+	// joined errors
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) {
+		t.Fatalf("expected joined errors, got %v", err)
+	}
+
+	// expected problems
+	expected := []string{
+		"[" + filename + "] line 2: can't split line into key and value",
+		"[" + filename + "] line 3: duplicate key 'KEY'",
+		"[" + filename + "] line 4: invalid key name 'BAD-KEY'",
+	}
+
+	// number of problems
+	if errs := joined.Unwrap(); len(errs) != len(expected) {
+		t.Errorf("expected %d errors, got %v", len(expected), errs)
+	} else {
+
+		// iterating over problems
+		for i := range expected {
+			if errs[i].Error() != expected[i] {
+				t.Errorf("expected %q, got %q", expected[i], errs[i])
+			}
+		}
+	}
+
+	// valid file is parsed as usual
+	if _, err := NewLoader(WithContinueOnError()).Parse(createFile(t, "KEY = value\n")); err != nil {
+		t.Errorf("error parsing valid file: %v", err)
+	}
+}
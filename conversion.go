@@ -0,0 +1,7 @@
+package envfile
+
+import "github.com/afonichev/envfile/parser"
+
+// ConversionError is a value that can't be converted to the requested type, returned by Get
+// and the As methods of payloads; use errors.As to build messages for users.
+type ConversionError = parser.ConversionError
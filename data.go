@@ -0,0 +1,12 @@
+package envfile
+
+// WithData makes values of the map available to references as { data.name }, nested maps
+// and slices as { data.name.field.0 }, without setting them as environment variables.
+// They are resolved after secret providers and before environment variables.
+func WithData(data map[string]interface{}) Option {
+	return func(l *Loader) {
+
+		// set data
+		l.data = data
+	}
+}
@@ -0,0 +1,64 @@
+package envfile
+
+import (
+	"testing"
+)
+
+// TestWithData tests references to values of the data map.
+func TestWithData(t *testing.T) {
+
+	// data map
+	data := map[string]interface{}{
+		"cluster":  "prod-eu",
+		"replicas": 3,
+		"regions":  []string{"eu-west-1", "eu-central-1"},
+		"template": "{{ .Name }}",
+	}
+
+	// file content
+	filename := createFile(t, `
+NAMESPACE = app-{ data.cluster }
+REPLICAS = { data.replicas }
+REGION = { data.regions.1 }
+TEMPLATE = { data.template }
+`)
+
+	// parse file
+	payloads, err := NewLoader(WithData(data)).Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// expected values
+	expected := map[string]string{
+		"NAMESPACE": "app-prod-eu",
+		"REPLICAS":  "3",
+		"REGION":    "eu-central-1",
+		"TEMPLATE":  "{{ .Name }}",
+	}
+
+	// iterating over expected values
+	for key, value := range expected {
+
+		// value is different from expected
+		if payload, _ := payloads.Lookup(key); payload.Value != value {
+			t.Errorf("expected %s = %s, got %s", key, value, payload.Value)
+		}
+	}
+
+	// same values with custom delimiters
+	payloads, err = NewLoader(WithData(data), WithDelimiters("%", "%")).Parse(createFile(t, "REGION = %data.regions.0%\n"))
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// value is different from expected
+	if payloads[0].Value != "eu-west-1" {
+		t.Errorf("expected REGION = eu-west-1, got %s", payloads[0].Value)
+	}
+
+	// missing field of the data map
+	if _, err := NewLoader(WithData(data)).Parse(createFile(t, "KEY = { data.missing }\n")); err == nil {
+		t.Error("missing field of the data map but parse didn't return an error")
+	}
+}
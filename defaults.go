@@ -0,0 +1,54 @@
+package envfile
+
+import "os"
+
+// defaultFilename is the file loaded when no file names are given.
+const defaultFilename = ".envfile"
+
+// WithAppName sets the application name used to find the default file in per-user
+// directories, e.g. %APPDATA%\<app>\.envfile on Windows.
+func WithAppName(name string) Option {
+	return func(l *Loader) {
+
+		// set application name
+		l.app = name
+	}
+}
+
+// SetAppName sets the application name of the loader used by the package-level functions and Autoload,
+// see WithAppName. Call it before loading, e.g. in main, since the autoload package loads on import.
+func SetAppName(name string) {
+
+	// lock loader
+	std.mu.Lock()
+
+	// deferred unlock of loader
+	defer std.mu.Unlock()
+
+	// set application name
+	std.app = name
+}
+
+// defaultFile returns the first existing default file or .envfile if none exists.
+func (l *Loader) defaultFile() string {
+
+	// lock loader
+	l.mu.Lock()
+
+	// application name
+	app := l.app
+
+	// unlock loader
+	l.mu.Unlock()
+
+	// iterating over default files of the platform
+	for _, filename := range defaultFiles(app) {
+
+		// file exists
+		if _, err := os.Stat(filename); err == nil {
+			return filename
+		}
+	}
+
+	return defaultFilename
+}
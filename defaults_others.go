@@ -0,0 +1,8 @@
+//go:build !windows
+
+package envfile
+
+// defaultFiles returns the default files in order of search.
+func defaultFiles(app string) []string {
+	return []string{defaultFilename}
+}
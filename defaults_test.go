@@ -0,0 +1,28 @@
+package envfile
+
+import (
+	"runtime"
+	"testing"
+)
+
+// TestDefaultFile tests the default file when none of the candidates exists.
+func TestDefaultFile(t *testing.T) {
+
+	// default files of the platform
+	files := defaultFiles("app")
+
+	// .envfile is not searched first
+	if files[0] != defaultFilename {
+		t.Errorf("expected %s to be searched first, got %v", defaultFilename, files)
+	}
+
+	// other platforms have only .envfile
+	if runtime.GOOS != "windows" && len(files) != 1 {
+		t.Errorf("unexpected default files: %v", files)
+	}
+
+	// missing default file
+	if filename := NewLoader(WithAppName("app")).defaultFile(); filename != defaultFilename {
+		t.Errorf("expected %s, got %s", defaultFilename, filename)
+	}
+}
@@ -0,0 +1,21 @@
+package envfile
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// defaultFiles returns the default files in order of search: dotfiles are awkward
+// in Explorer, so envfile.txt and the file in the application data directory are also searched.
+func defaultFiles(app string) []string {
+
+	// files of the working directory
+	files := []string{defaultFilename, "envfile.txt"}
+
+	// file of the application data directory
+	if appData := os.Getenv("APPDATA"); len(app) > 0 && len(appData) > 0 {
+		files = append(files, filepath.Join(appData, app, defaultFilename))
+	}
+
+	return files
+}
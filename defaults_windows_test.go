@@ -0,0 +1,65 @@
+package envfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestDefaultFileWindows tests search of the default file on Windows.
+func TestDefaultFileWindows(t *testing.T) {
+
+	// working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("error getting working directory: %v", err)
+	}
+
+	// temporary directory
+	dir, err := ioutil.TempDir("", "defaults")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v", err)
+	}
+
+	// deferred removal of the directory
+	defer os.RemoveAll(dir)
+
+	// change working directory
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("error changing working directory: %v", err)
+	}
+
+	// deferred return to the working directory
+	defer os.Chdir(wd)
+
+	// application data directory
+	appData := os.Getenv("APPDATA")
+	os.Setenv("APPDATA", dir)
+	defer os.Setenv("APPDATA", appData)
+
+	// file in the application data directory
+	user := filepath.Join(dir, "app", defaultFilename)
+	os.MkdirAll(filepath.Dir(user), 0755)
+	ioutil.WriteFile(user, []byte("KEY = value\n"), 0644)
+
+	// file of the application data directory is found
+	if filename := NewLoader(WithAppName("app")).defaultFile(); filename != user {
+		t.Errorf("expected %s, got %s", user, filename)
+	}
+
+	// application name of the package-level functions
+	SetAppName("app")
+	defer SetAppName("")
+
+	// file of the application data directory is found by Load without file names
+	if filename := std.defaultFile(); filename != user {
+		t.Errorf("expected %s for the default loader, got %s", user, filename)
+	}
+
+	// file of the working directory takes precedence
+	ioutil.WriteFile("envfile.txt", []byte("KEY = value\n"), 0644)
+	if filename := NewLoader(WithAppName("app")).defaultFile(); filename != "envfile.txt" {
+		t.Errorf("expected envfile.txt, got %s", filename)
+	}
+}
@@ -0,0 +1,123 @@
+package envfile
+
+import (
+	"os"
+	"testing"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// TestParseDelimiters tests file parsing with custom delimiters of references.
+func TestParseDelimiters(t *testing.T) {
+
+	// set environment variable for the test
+	os.Setenv("ENVFILE_TEST_HOST", "localhost")
+
+	// deferred removal of the environment variable
+	defer os.Unsetenv("ENVFILE_TEST_HOST")
+
+	// file contents by delimiters
+	files := map[[2]string]string{
+		{"%", "%"}: `
+KEY_1 = value
+KEY_2 = %KEY_1% and %ENVFILE_TEST_HOST%
+KEY_3 = %%KEY_1%% is escaped, { KEY_1 } is literal
+KEY_4 = %KEY_5%is defined later
+KEY_5 = {"host": "%KEY_1%"}\n
+KEY_6 = %KEY_5.host%
+raw KEY_7 = %KEY_1%
+KEY_8 = %KEY_7%
+`,
+		{"@{", "}"}: `
+KEY_1 = value
+KEY_2 = @{KEY_1} and @{ ENVFILE_TEST_HOST }
+KEY_3 = @{@{KEY_1} is escaped, { KEY_1 } is literal
+KEY_4 = @{KEY_5}is defined later
+KEY_5 = {"host": "@{KEY_1}"}\n
+KEY_6 = @{KEY_5.host}
+raw KEY_7 = @{KEY_1}
+KEY_8 = @{KEY_7}
+`,
+	}
+
+	// iterating over files
+	for delimiters, content := range files {
+
+		// reference to KEY_1 as it is written
+		reference := delimiters[0] + "KEY_1" + delimiters[1]
+
+		// expected key/value pairs
+		pairs := map[string]string{
+			"KEY_2": "value and localhost",
+			"KEY_3": reference + " is escaped, { KEY_1 } is literal",
+			"KEY_4": "{\"host\": \"value\"}\nis defined later",
+			"KEY_5": "{\"host\": \"value\"}\n",
+			"KEY_6": "value",
+			"KEY_7": reference,
+			"KEY_8": reference,
+		}
+
+		// parse file
+		payloads, err := NewLoader(WithDelimiters(delimiters[0], delimiters[1])).Parse(createFile(t, content))
+		if err != nil {
+			t.Fatalf("error parsing env file: %v", err)
+		}
+
+		// iteration over payloads
+		for _, payload := range payloads {
+
+			// value from payload is different from expected
+			if value, ok := pairs[payload.Key]; ok && payload.Value != value {
+				t.Errorf("%s: expected %s to be %q, got %q", delimiters[0], payload.Key, value, payload.Value)
+			}
+		}
+
+		// references with custom delimiters
+		refs := parser.Syntax{Open: delimiters[0], Close: delimiters[1]}.References(payloads)["KEY_2"]
+
+		// KEY_1 is not found as a reference
+		if len(refs) != 2 || refs[0].Name != "KEY_1" || !refs[0].Internal || refs[1].Internal {
+			t.Errorf("%s: unexpected references: %v", delimiters[0], refs)
+		}
+	}
+}
+
+// TestParseDelimitersErrors tests errors of references with custom delimiters.
+func TestParseDelimitersErrors(t *testing.T) {
+
+	// expected errors by file content
+	cases := map[string]string{
+		"KEY = %KEY%\n":                      "line 1: key 'KEY' is used recursively",
+		"KEY_1 = %KEY_2%\nKEY_2 = %KEY_1%\n": "line 1: key 'KEY_1' is used recursively",
+		"KEY = %ENVFILE_UNDEFINED\n":         "line 1: can't find the closing delimiter '%'",
+		"KEY = a%%b%  %\n":                   "line 1: variable name is empty",
+		"KEY = %ENVFILE_UNDEFINED%\n":        "line 1: variable 'ENVFILE_UNDEFINED' does not exist",
+	}
+
+	// iterating over cases
+	for content, message := range cases {
+
+		// file content
+		filename := createFile(t, content)
+
+		// parse file
+		_, err := NewLoader(WithDelimiters("%", "%")).Parse(filename)
+
+		// error is different from expected
+		if err == nil || err.Error() != "["+filename+"] "+message {
+			t.Errorf("%q: expected error %q, got %v", content, message, err)
+		}
+	}
+
+	// undefined variable is left as it is written
+	payloads, err := NewLoader(WithDelimiters("%", "%"), WithUndefinedPolicy(UndefinedLiteral)).
+		Parse(createFile(t, "KEY = a%ENVFILE_UNDEFINED%b\n"))
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// value is different from expected
+	if payloads[0].Value != "a%ENVFILE_UNDEFINED%b" {
+		t.Errorf("expected KEY to be a%%ENVFILE_UNDEFINED%%b, got %s", payloads[0].Value)
+	}
+}
@@ -0,0 +1,40 @@
+package envfile
+
+import "github.com/afonichev/envfile/parser"
+
+// Dialect is a syntax of files with environment variables.
+type Dialect = parser.Dialect
+
+const (
+
+	// DialectDefault is the native syntax with { KEY } references and backslash escapes.
+	DialectDefault = parser.DialectDefault
+
+	// DialectKubernetes is the syntax of the container environment in Kubernetes:
+	// $(KEY) references, $$ escapes, unresolvable references are left literal.
+	DialectKubernetes = parser.DialectKubernetes
+
+	// DialectDocker is the strict syntax of docker and compose env files: KEY=value
+	// without spaces around the equal sign, values are taken as they are written,
+	// every key is exported.
+	DialectDocker = parser.DialectDocker
+
+	// DialectDotenvExpand is the syntax of dotenv-expand for files shared with Node.js services:
+	// $KEY and ${KEY:-default} references expanded from right to left, \$ escapes,
+	// missing variables become empty.
+	DialectDotenvExpand = parser.DialectDotenvExpand
+)
+
+// DialectRules are the rules of a custom dialect, see parser.DialectRules.
+type DialectRules = parser.DialectRules
+
+// RegisterDialect adds a custom dialect to the registry and returns it for WithDialect,
+// the name must be unique.
+func RegisterDialect(rules DialectRules) (Dialect, error) {
+	return parser.RegisterDialect(rules)
+}
+
+// LookupDialect returns the registered dialect by name, e.g. "docker".
+func LookupDialect(name string) (Dialect, bool) {
+	return parser.LookupDialect(name)
+}
@@ -0,0 +1,212 @@
+package envfile
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestParseDocker tests file parsing with the docker dialect.
+func TestParseDocker(t *testing.T) {
+
+	// set environment variable for the test
+	os.Setenv("ENVFILE_DOCKER_HOST", "localhost")
+
+	// deferred removal of the environment variable
+	defer os.Unsetenv("ENVFILE_DOCKER_HOST")
+
+	// file content
+	filename := createFile(t, "# comment\n  KEY_1=value with {braces} and \\n \nKEY_2=\nENVFILE_DOCKER_HOST\nENVFILE_DOCKER_MISSING\n")
+
+	// parse file
+	payloads, err := NewLoader(WithDialect(DialectDocker)).Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// expected payloads
+	expected := []Payload{
+		{Line: 2, Export: true, Key: "KEY_1", Value: "value with {braces} and \\n ", Raw: "value with {braces} and \\n "},
+		{Line: 3, Export: true, Key: "KEY_2", Value: "", Raw: ""},
+		{Line: 4, Export: true, Key: "ENVFILE_DOCKER_HOST", Value: "localhost"},
+	}
+
+	// number of payloads is different from expected
+	if len(payloads) != len(expected) {
+		t.Fatalf("expected %d payloads, got %d", len(expected), len(payloads))
+	}
+
+	// iteration over payloads
+	for i, payload := range payloads {
+
+		// payload is different from expected
+		if payload != expected[i] {
+			t.Errorf("expected payload %+v, got %+v", expected[i], payload)
+		}
+	}
+
+	// spaces around the equal sign
+	filename = createFile(t, "KEY = value\n")
+
+	// parse file
+	if _, err := NewLoader(WithDialect(DialectDocker)).Parse(filename); err == nil {
+		t.Error("spaces around the equal sign but parse didn't return an error")
+	}
+
+	// linter with the spacing rule
+	linter := NewLinter()
+	linter.Enable("spacing")
+
+	// check file
+	findings, err := linter.Lint(filename)
+	if err != nil {
+		t.Fatalf("error linting env file: %v", err)
+	}
+
+	// iterating over a list of findings
+	for _, finding := range findings {
+
+		// spacing is flagged
+		if finding.Rule == "spacing" {
+			return
+		}
+	}
+
+	t.Error("spaces around the equal sign were not flagged")
+}
+
+// percentRules are the rules of a test dialect with KEY=value lines and %KEY% references.
+type percentRules struct{}
+
+// Name returns the name of the dialect.
+func (percentRules) Name() string {
+	return "percent"
+}
+
+// ParseLine parses the line of the document.
+func (percentRules) ParseLine(line int, text string) Node {
+
+	// line without a key
+	position := strings.Index(text, "=")
+	if position < 0 {
+		return Node{Kind: NodeBlank}
+	}
+
+	// line with a key and a value
+	return Node{
+		Kind:       NodeEntry,
+		Export:     true,
+		Key:        text[:position],
+		KeySpan:    Span{Start: 0, End: position},
+		Value:      text[position+1:],
+		ValueSpan:  Span{Start: position + 1, End: len(text)},
+		References: percentRules{}.References(text[position+1:], position+1),
+	}
+}
+
+// References returns references to variables in the value starting at the column.
+func (percentRules) References(value string, column int) []Reference {
+
+	// references list
+	var references []Reference
+
+	// parts of the value between percent signs
+	parts := strings.Split(value, "%")
+
+	// position of the part
+	position := 0
+
+	// iterating over parts
+	for i, part := range parts {
+
+		// part between percent signs
+		if i%2 == 1 && i < len(parts)-1 {
+			references = append(references, Reference{Name: part, Span: Span{Start: column + position - 1, End: column + position + len(part) + 1}})
+		}
+
+		// skip part and percent sign
+		position += len(part) + 1
+	}
+
+	return references
+}
+
+// Expand replaces references with values.
+func (percentRules) Expand(filename string, payloads []Payload, lookup func(string) (string, bool)) ([]Payload, error) {
+
+	// iterating over a list of payloads
+	for i, payload := range payloads {
+
+		// iterating over references
+		for _, reference := range (percentRules{}).References(payload.Value, 0) {
+
+			// variable value
+			value, ok := lookup(reference.Name)
+			if !ok {
+				return nil, fmt.Errorf("[%s] line %d: variable '%s' is missing", filename, payload.Line, reference.Name)
+			}
+
+			// replace reference
+			payloads[i].Value = strings.Replace(payloads[i].Value, "%"+reference.Name+"%", value, 1)
+		}
+	}
+
+	return payloads, nil
+}
+
+// TestRegisterDialect tests parsing with a custom dialect.
+func TestRegisterDialect(t *testing.T) {
+
+	// register dialect
+	dialect, err := RegisterDialect(percentRules{})
+	if err != nil {
+		t.Fatalf("error registering dialect: %v", err)
+	}
+
+	// name is already used
+	if _, err := RegisterDialect(percentRules{}); err == nil {
+		t.Error("dialect is registered twice but RegisterDialect didn't return an error")
+	}
+
+	// dialect by name
+	if found, ok := LookupDialect("percent"); !ok || found != dialect || dialect.String() != "percent" {
+		t.Errorf("expected dialect %d named percent, got %d, %v", dialect, found, ok)
+	}
+
+	// set environment variable for the test
+	os.Setenv("ENVFILE_PERCENT_HOME", "/home")
+
+	// deferred removal of the environment variable
+	defer os.Unsetenv("ENVFILE_PERCENT_HOME")
+
+	// file content
+	filename := createFile(t, "PATH=%ENVFILE_PERCENT_HOME%/bin\n")
+
+	// parse file
+	payloads, err := NewLoader(WithDialect(dialect)).Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// value with the replaced reference
+	if len(payloads) != 1 || payloads[0].Value != "/home/bin" {
+		t.Errorf("expected PATH = /home/bin, got %+v", payloads)
+	}
+
+	// document references
+	doc, err := NewLoader(WithDialect(dialect)).ParseDocument(filename)
+	if err != nil {
+		t.Fatalf("error parsing document: %v", err)
+	}
+
+	// reference of the document
+	if refs := doc.Nodes[0].References; len(refs) != 1 || refs[0].Name != "ENVFILE_PERCENT_HOME" || refs[0].Span != (Span{Start: 5, End: 27}) {
+		t.Errorf("unexpected references: %+v", refs)
+	}
+
+	// built-in dialect by name
+	if found, ok := LookupDialect("docker"); !ok || found != DialectDocker {
+		t.Errorf("expected docker dialect, got %d, %v", found, ok)
+	}
+}
@@ -0,0 +1,85 @@
+package envfile
+
+import (
+	"io"
+	"os"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// NodeKind is a kind of line in the document.
+type NodeKind = parser.NodeKind
+
+const (
+
+	// NodeBlank is an empty line or a line of spaces.
+	NodeBlank = parser.NodeBlank
+
+	// NodeComment is a line starting with the number sign.
+	NodeComment = parser.NodeComment
+
+	// NodeEntry is a line with a key and a value.
+	NodeEntry = parser.NodeEntry
+
+	// NodeInvalid is a line that can't be split into key and value.
+	NodeInvalid = parser.NodeInvalid
+
+	// NodeContinuation is a line of a multi-line value, including the closing one.
+	NodeContinuation = parser.NodeContinuation
+
+	// NodeInclude is a line including another file, the path is the value.
+	NodeInclude = parser.NodeInclude
+
+	// NodeSection is a line starting the keys of a profile, the profile name is the value.
+	NodeSection = parser.NodeSection
+)
+
+// Span is a range of columns in the line, counted in bytes from zero, the end is exclusive.
+type Span = parser.Span
+
+// Reference is a reference to a variable in the value.
+type Reference = parser.Reference
+
+// Node is a line of the document.
+type Node = parser.Node
+
+// Document is a parsed file with environment variables, keeping every line as it is written.
+type Document = parser.Document
+
+// ParseDocument parses file with environment variables into a document.
+func ParseDocument(filename string) (*Document, error) {
+	return NewLoader().ParseDocument(filename)
+}
+
+// ParseDocument parses file with environment variables into a document.
+func (l *Loader) ParseDocument(filename string) (*Document, error) {
+
+	// open file with environment variables
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// deferred file close
+	defer file.Close()
+
+	return l.readDocument(filename, file)
+}
+
+// EncodeAST encodes the document with comments, directives, references and positions as JSON,
+// nodes are in the order of lines.
+func EncodeAST(doc *Document) ([]byte, error) {
+	return parser.EncodeAST(doc)
+}
+
+// readDocument reads the document from the reader, the name is used in error messages.
+func (l *Loader) readDocument(filename string, reader io.Reader) (*Document, error) {
+
+	// read document
+	doc, err := parser.Read(filename, reader, l.syntax())
+
+	// record number of lines
+	l.count(filename, Stats{Lines: len(doc.Nodes)})
+
+	return doc, err
+}
@@ -0,0 +1,110 @@
+package envfile
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// TestParseDocument tests parsing of file into a document.
+func TestParseDocument(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "# comment\n\n  export KEY_1 = { KEY_2 } and {{ escaped }}\nKEY_2?=value\ninvalid line\n")
+
+	// parse document
+	doc, err := ParseDocument(filename)
+	if err != nil {
+		t.Fatalf("error parsing document: %v", err)
+	}
+
+	// expected kinds of nodes
+	kinds := []NodeKind{NodeComment, NodeBlank, NodeEntry, NodeEntry, NodeInvalid}
+
+	// number of nodes is different from expected
+	if len(doc.Nodes) != len(kinds) {
+		t.Fatalf("expected %d nodes, got %d", len(kinds), len(doc.Nodes))
+	}
+
+	// iterating over a list of nodes
+	for i, node := range doc.Nodes {
+
+		// kind of node is different from expected
+		if node.Kind != kinds[i] {
+			t.Errorf("expected line %d to be %s, got %s", node.Line, kinds[i], node.Kind)
+		}
+	}
+
+	// entry with a reference
+	entry := doc.Nodes[2]
+
+	// key, value or their positions are different from expected
+	if !entry.Export || entry.Key != "KEY_1" || entry.KeySpan != (Span{Start: 9, End: 14}) ||
+		entry.Value != "{ KEY_2 } and {{ escaped }}" || entry.ValueSpan != (Span{Start: 17, End: 44}) {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+
+	// reference is different from expected
+	if len(entry.References) != 1 || entry.References[0] != (Reference{Name: "KEY_2", Span: Span{Start: 17, End: 26}}) {
+		t.Errorf("unexpected references: %+v", entry.References)
+	}
+
+	// conditional entry
+	if entry := doc.Nodes[3]; !entry.Conditional || entry.Key != "KEY_2" || entry.Value != "value" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+
+	// invalid line is not reported
+	if doc.Err() == nil {
+		t.Error("document has an invalid line but no error")
+	}
+
+	// encode document
+	data, err := EncodeAST(doc)
+	if err != nil {
+		t.Fatalf("error encoding document: %v", err)
+	}
+
+	// decoded document
+	var decoded Document
+
+	// decode document
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("error decoding document: %v", err)
+	}
+
+	// reference is lost
+	if decoded.Nodes[2].References[0].Name != "KEY_2" {
+		t.Errorf("reference is missing in encoded document: %s", data)
+	}
+}
+
+// TestQuotedValues tests values enclosed in double and single quotes.
+func TestQuotedValues(t *testing.T) {
+
+	// variable of the environment
+	os.Setenv("QUOTED_NAME", "world")
+	defer os.Unsetenv("QUOTED_NAME")
+
+	// file with quoted values
+	payloads, err := Parse(createFile(t, "DOUBLE = \"  hello # { QUOTED_NAME } \\\"quoted\\\" \"\nSINGLE = ' { QUOTED_NAME } \\n '\nPARTIAL = \"a\" b\nESCAPED = \"a\\\"\nraw RAW = \"kept\"\n"))
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// expected values
+	expected := map[string]string{
+		"DOUBLE":  "  hello # world \"quoted\" ",
+		"SINGLE":  " { QUOTED_NAME } \\n ",
+		"PARTIAL": "\"a\" b",
+		"ESCAPED": "\"a\"",
+		"RAW":     "\"kept\"",
+	}
+
+	// iterating over expected values
+	for key, value := range expected {
+		if payload, _ := payloads.Lookup(key); payload.Value != value {
+			t.Errorf("expected %s to be %q, got %q", key, value, payload.Value)
+		}
+	}
+}
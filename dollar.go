@@ -0,0 +1,14 @@
+package envfile
+
+// WithDollarReferences makes $KEY and ${KEY} references of the default dialect work alongside
+// { KEY } ones, so files written for shells and other dotenv libraries are read as they are.
+// They are resolved like { KEY }: keys of the file, secrets, data and variables of the environment,
+// undefined variables follow the policy. \$ is a literal dollar sign, a dollar sign not followed
+// by a variable name is kept as it is written.
+func WithDollarReferences(enabled bool) Option {
+	return func(l *Loader) {
+
+		// set dollar references status
+		l.dollar = enabled
+	}
+}
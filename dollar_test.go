@@ -0,0 +1,72 @@
+package envfile
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// TestDollarReferences tests resolving of $KEY and ${KEY} references.
+func TestDollarReferences(t *testing.T) {
+
+	// set environment variable
+	os.Setenv("DOLLAR_USER", "admin")
+
+	// deferred removal of the environment variable
+	defer os.Unsetenv("DOLLAR_USER")
+
+	// content with references of every kind
+	content := "HOST = localhost\n" +
+		"PORT = 5432\n" +
+		"URL = postgres://$DOLLAR_USER@${HOST}:{ PORT }/db\n" +
+		"PRICE = \\$5 and $ 10\n" +
+		"RAW = '$HOST'\n"
+
+	// references are resolved
+	payloads, err := NewLoader(WithDollarReferences(true)).ParseString(content)
+	if err != nil {
+		t.Fatalf("error parsing content: %v", err)
+	}
+
+	// expected values
+	expected := map[string]string{"HOST": "localhost", "PORT": "5432", "URL": "postgres://admin@localhost:5432/db", "PRICE": "$5 and $ 10", "RAW": "$HOST"}
+
+	// iterating over payloads
+	for _, payload := range payloads {
+		if value := expected[payload.Key]; payload.Value != value {
+			t.Errorf("expected %s = %q, got %q", payload.Key, value, payload.Value)
+		}
+	}
+
+	// dollar signs are literal by default
+	if payloads, _ := ParseString("HOST = localhost\nURL = $HOST\n"); payloads[1].Value != "$HOST" {
+		t.Errorf("expected literal dollar sign, got %q", payloads[1].Value)
+	}
+
+	// custom delimiters
+	payloads, err = NewLoader(WithDollarReferences(true), WithDelimiters("<<", ">>")).ParseString("HOST = localhost\nURL = http://${HOST}/<< HOST >>\n")
+	if err != nil {
+		t.Fatalf("error parsing content: %v", err)
+	}
+	if payloads[1].Value != "http://localhost/localhost" {
+		t.Errorf("expected http://localhost/localhost, got %q", payloads[1].Value)
+	}
+
+	// undefined variable follows the policy
+	if _, err := NewLoader(WithDollarReferences(true)).ParseString("URL = $DOLLAR_MISSING\n"); err == nil {
+		t.Error("variable does not exist but parse didn't return an error")
+	}
+
+	// references of the document in order of positions
+	node := parser.ParseLine(parser.Syntax{Dollar: true}, 1, "URL = $USER@{ HOST }:${PORT}")
+	references := []Reference{
+		{Name: "USER", Span: Span{Start: 6, End: 11}},
+		{Name: "HOST", Span: Span{Start: 12, End: 20}},
+		{Name: "PORT", Span: Span{Start: 21, End: 28}},
+	}
+	if !reflect.DeepEqual(node.References, references) {
+		t.Errorf("expected %+v, got %+v", references, node.References)
+	}
+}
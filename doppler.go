@@ -0,0 +1,75 @@
+package envfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Doppler fetches configurations from the Doppler secrets API,
+// register it with WithRemote("doppler", &Doppler{Token: token}) and load doppler://project/config.
+type Doppler struct {
+
+	// service or personal token
+	Token string
+
+	// API address, https://api.doppler.com by default
+	Host string
+
+	// HTTP client, http.DefaultClient by default
+	Client *http.Client
+}
+
+// Fetch returns the secrets of the configuration of the project.
+func (d *Doppler) Fetch(project, config string) (map[string]string, error) {
+
+	// API address
+	host := d.Host
+	if len(host) == 0 {
+		host = "https://api.doppler.com"
+	}
+
+	// query of the download request
+	query := url.Values{"project": {project}, "config": {config}, "format": {"json"}}
+
+	// request
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(host, "/")+"/v3/configs/config/secrets/download?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// access token
+	req.SetBasicAuth(d.Token, "")
+
+	// HTTP client
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	// send request
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// deferred body close
+	defer resp.Body.Close()
+
+	// request failed
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("doppler API returned %s", resp.Status)
+	}
+
+	// secrets by name
+	secrets := make(map[string]string)
+
+	// decode secrets
+	if err := json.NewDecoder(resp.Body).Decode(&secrets); err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
@@ -0,0 +1,15 @@
+package envfile
+
+import (
+	"io"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// EncodeDOT writes the dependency graph of the payloads in Graphviz DOT format:
+// an edge goes from the referencing key to the referenced variable,
+// variables taken from environment variables are drawn as dashed ellipses.
+// Nodes and edges follow the order of the payloads, so the output is the same on every run.
+func EncodeDOT(payloads []Payload, w io.Writer) error {
+	return parser.EncodeDOT(payloads, w)
+}
@@ -0,0 +1,41 @@
+package envfile
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestEncodeDOT tests the dependency graph output.
+func TestEncodeDOT(t *testing.T) {
+
+	// payloads as they are written
+	payloads := []Payload{
+		{Key: "HOST", Raw: "localhost"},
+		{Key: "URL", Raw: "http://{ HOST }:{ PORT }/{ HOST }"},
+	}
+
+	// graph output
+	var buffer bytes.Buffer
+
+	// encode graph
+	if err := EncodeDOT(payloads, &buffer); err != nil {
+		t.Fatalf("error encoding graph: %v", err)
+	}
+
+	// expected graph
+	expected := `digraph envfile {
+	rankdir=LR;
+	node [shape=box];
+	"HOST";
+	"URL";
+	"URL" -> "HOST";
+	"PORT" [shape=ellipse, style=dashed];
+	"URL" -> "PORT";
+}
+`
+
+	// graph is different from expected
+	if buffer.String() != expected {
+		t.Errorf("expected graph:\n%s\ngot:\n%s", expected, buffer.String())
+	}
+}
@@ -0,0 +1,81 @@
+package envfile
+
+import (
+	"os"
+	"testing"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// TestParseDotenvExpand tests file parsing with the dotenv-expand dialect.
+func TestParseDotenvExpand(t *testing.T) {
+
+	// set environment variable for the test
+	os.Setenv("ENVFILE_TEST_HOST", "localhost")
+
+	// deferred removal of the environment variable
+	defer os.Unsetenv("ENVFILE_TEST_HOST")
+
+	// file content
+	filename := createFile(t, `
+BASIC = basic
+EXPANDED = $BASIC
+BRACED = ${BASIC}x
+ESCAPED = \$BASIC and \${BASIC}
+MISSING = a${ENVFILE_MISSING}b$ENVFILE_MISSING
+DEFAULT = ${ENVFILE_MISSING:-fallback}
+NESTED = ${ENVFILE_MISSING:-${BASIC}}
+ENVFILE_TEST_HOST = file
+HOST = $ENVFILE_TEST_HOST
+EMPTY =
+EMPTY_DEFAULT = ${EMPTY:-default}
+PRICE = 5$ and $BASIC
+FORWARD = $LATER
+LATER = later $BASIC
+raw RAW = $BASIC
+`)
+
+	// expected key/value pairs
+	pairs := map[string]string{
+		"EXPANDED":      "basic",
+		"BRACED":        "basicx",
+		"ESCAPED":       "$BASIC and ${BASIC}",
+		"MISSING":       "ab",
+		"DEFAULT":       "fallback",
+		"NESTED":        "basic",
+		"HOST":          "localhost",
+		"EMPTY_DEFAULT": "default",
+		"PRICE":         "5$ and basic",
+		"FORWARD":       "later basic",
+		"LATER":         "later basic",
+		"RAW":           "$BASIC",
+	}
+
+	// parse file
+	payloads, err := NewLoader(WithDialect(DialectDotenvExpand)).Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// iteration over payloads
+	for _, payload := range payloads {
+
+		// value from payload is different from expected
+		if value, ok := pairs[payload.Key]; ok && payload.Value != value {
+			t.Errorf("expected %s to be %s, got %s", payload.Key, value, payload.Value)
+		}
+	}
+
+	// references of the nested default
+	refs := parser.Syntax{Dialect: DialectDotenvExpand}.References(payloads)["NESTED"]
+
+	// references are different from expected
+	if len(refs) != 2 || refs[0].Name != "ENVFILE_MISSING" || refs[1].Name != "BASIC" || !refs[1].Internal {
+		t.Errorf("unexpected references: %v", refs)
+	}
+
+	// key referencing itself
+	if _, err := NewLoader(WithDialect(DialectDotenvExpand)).Parse(createFile(t, "KEY = $KEY\n")); err == nil {
+		t.Error("key references itself but parse didn't return an error")
+	}
+}
@@ -0,0 +1,178 @@
+package envfile
+
+import "slices"
+
+// DriftKind is a kind of difference between files and the process environment.
+type DriftKind string
+
+const (
+
+	// DriftMissing means an exported key is missing in the environment.
+	DriftMissing DriftKind = "missing"
+
+	// DriftExtra means a key defined as local in the files is present in the environment.
+	DriftExtra DriftKind = "extra"
+
+	// DriftMismatch means an overloaded key has a different value in the environment.
+	DriftMismatch DriftKind = "mismatch"
+)
+
+// Drift is a difference between files and the process environment.
+type Drift struct {
+
+	// kind of difference
+	Kind DriftKind `json:"kind"`
+
+	// key
+	Key string `json:"key"`
+
+	// file name of the effective definition
+	File string `json:"file"`
+
+	// line number of the effective definition
+	Line int `json:"line"`
+
+	// value according to the files
+	Expected string `json:"expected,omitempty"`
+
+	// value in the environment
+	Actual string `json:"actual,omitempty"`
+}
+
+// DriftCheck compares what the files say against the current process environment.
+func DriftCheck(filenames ...string) ([]Drift, error) {
+	return NewLoader().DriftCheck(filenames...)
+}
+
+// DriftCheck compares what the files say against the current process environment.
+func (l *Loader) DriftCheck(filenames ...string) ([]Drift, error) {
+
+	// file name list is empty
+	if len(filenames) == 0 {
+
+		// default file and drop-in fragments
+		filenames = l.defaultNames()
+	}
+
+	// effective definitions of the files
+	definitions, _, err := l.effectiveDefinitions(filenames)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.drifts(definitions), nil
+}
+
+// effectiveDefinitions parses the files and returns the effective definitions of their keys
+// in order of the first definition and the files included by each file.
+func (l *Loader) effectiveDefinitions(filenames []string) ([]definition, map[string][]string, error) {
+
+	// key names in order of first definition
+	var keys []string
+
+	// effective definitions by key name
+	definitions := make(map[string]definition)
+
+	// included files by file name
+	included := make(map[string][]string)
+
+	// iterating over a list of filenames
+	for _, filename := range filenames {
+
+		// parse file
+		payloads, err := l.Parse(filename)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// iteration over payloads
+		for _, payload := range payloads {
+
+			// payload of a file included by the file
+			if len(payload.File) > 0 && !slices.Contains(included[filename], payload.File) {
+				included[filename] = append(included[filename], payload.File)
+			}
+
+			// key name in the index
+			key := l.indexKey(payload.Key)
+
+			// previous definition
+			previous, ok := definitions[key]
+
+			// first definition of the key
+			if !ok {
+				keys = append(keys, key)
+			}
+
+			// overload wins over anything, export wins over local definitions only
+			if !ok || payload.Overload || (payload.Export && !previous.payload.Export && !previous.payload.Overload) {
+				definitions[key] = definition{file: filename, payload: payload}
+			}
+		}
+	}
+
+	// definitions list
+	list := make([]definition, 0, len(keys))
+
+	// iterating over a list of keys
+	for _, key := range keys {
+
+		// add effective definition
+		list = append(list, definitions[key])
+	}
+
+	return list, included, nil
+}
+
+// drifts compares the effective definitions against the current process environment.
+func (l *Loader) drifts(definitions []definition) []Drift {
+
+	// differences list
+	var drifts []Drift
+
+	// iterating over effective definitions
+	for _, def := range definitions {
+
+		// value in the environment
+		actual, ok := l.lookupEnv(def.payload.Key)
+
+		// difference
+		drift := Drift{
+			Key:    def.payload.Key,
+			File:   def.file,
+			Line:   def.payload.Line,
+			Actual: actual,
+		}
+
+		switch {
+
+		// overloaded key has a different value
+		case def.payload.Overload && (!ok || actual != def.payload.Value):
+			drift.Kind = DriftMismatch
+			drift.Expected = def.payload.Value
+
+			// overloaded key is missing
+			if !ok {
+				drift.Kind = DriftMissing
+			}
+
+		// exported key is missing
+		case def.payload.Export && !ok:
+			drift.Kind = DriftMissing
+			drift.Expected = def.payload.Value
+
+		// local key is present
+		case !def.payload.Export && !def.payload.Overload && ok:
+			drift.Kind = DriftExtra
+
+		// no difference
+		default:
+			continue
+		}
+
+		// add difference to list
+		drifts = append(drifts, drift)
+	}
+
+	return drifts
+}
@@ -0,0 +1,62 @@
+package envfile
+
+import (
+	"os"
+	"testing"
+)
+
+// TestDriftCheck tests comparison of files against the process environment.
+func TestDriftCheck(t *testing.T) {
+
+	// environment variables for the test
+	env := map[string]string{
+		"ENVFILE_DRIFT_EXPORTED":   "other",
+		"ENVFILE_DRIFT_OVERLOADED": "other",
+		"ENVFILE_DRIFT_LOCAL":      "value",
+	}
+
+	// iterating over environment variables
+	for key, value := range env {
+
+		// set environment variable
+		os.Setenv(key, value)
+
+		// deferred removal of the environment variable
+		defer os.Unsetenv(key)
+	}
+
+	// file content
+	filename := createFile(t, `
+export ENVFILE_DRIFT_EXPORTED = value
+overload ENVFILE_DRIFT_OVERLOADED = value
+ENVFILE_DRIFT_LOCAL = value
+export ENVFILE_DRIFT_MISSING = value
+`)
+
+	// compare file against the environment
+	drifts, err := DriftCheck(filename)
+	if err != nil {
+		t.Fatalf("error checking drift: %v", err)
+	}
+
+	// expected kinds of differences by key
+	expected := map[string]DriftKind{
+		"ENVFILE_DRIFT_OVERLOADED": DriftMismatch,
+		"ENVFILE_DRIFT_LOCAL":      DriftExtra,
+		"ENVFILE_DRIFT_MISSING":    DriftMissing,
+	}
+
+	// number of differences is different from expected
+	if len(drifts) != len(expected) {
+		t.Errorf("expected %d differences, got %d: %v", len(expected), len(drifts), drifts)
+	}
+
+	// iterating over a list of differences
+	for _, drift := range drifts {
+
+		// kind of difference is different from expected
+		if expected[drift.Key] != drift.Kind {
+			t.Errorf("expected %s to be %s, got %s", drift.Key, expected[drift.Key], drift.Kind)
+		}
+	}
+}
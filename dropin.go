@@ -0,0 +1,150 @@
+package envfile
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// dropInDir is the directory of fragments loaded after the default file.
+const dropInDir = ".envfile.d"
+
+// dropInExtension is the extension of fragments in the drop-in directory.
+const dropInExtension = ".envfile"
+
+// DropInFiles returns the fragments of the directory, files with the .envfile extension,
+// in numeric order of their prefixes: 9-tls.envfile goes before 10-base.envfile. Files without
+// a numeric prefix go last, files with the same prefix are ordered by name. A missing directory has no fragments.
+func DropInFiles(dir string) ([]string, error) {
+
+	// entries of the directory
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// fragments
+	var names []string
+
+	// iterating over entries
+	for _, entry := range entries {
+
+		// fragment file
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), dropInExtension) {
+			names = append(names, entry.Name())
+		}
+	}
+
+	// numeric order
+	sort.SliceStable(names, func(i, j int) bool {
+
+		// prefixes of the names
+		a, aok := dropInOrder(names[i])
+		b, bok := dropInOrder(names[j])
+
+		switch {
+
+		// both are numbered
+		case aok && bok && a != b:
+			return a < b
+
+		// numbered name goes first
+		case aok != bok:
+			return aok
+		}
+
+		return names[i] < names[j]
+	})
+
+	// paths of the fragments
+	files := make([]string, len(names))
+	for i, name := range names {
+		files[i] = filepath.Join(dir, name)
+	}
+
+	return files, nil
+}
+
+// DropInCollisions returns groups of fragments that share the numeric prefix and are ordered
+// by name only, their order is likely accidental: 20-db.envfile and 20-cache.envfile.
+func DropInCollisions(files []string) [][]string {
+
+	// fragments by prefix in order of files
+	groups := make(map[int][]string)
+	var prefixes []int
+
+	// iterating over fragments
+	for _, file := range files {
+
+		// prefix of the fragment
+		prefix, ok := dropInOrder(filepath.Base(file))
+		if !ok {
+			continue
+		}
+
+		// prefix is found for the first time
+		if _, exists := groups[prefix]; !exists {
+			prefixes = append(prefixes, prefix)
+		}
+
+		// add fragment to the group
+		groups[prefix] = append(groups[prefix], file)
+	}
+
+	// collisions list
+	var collisions [][]string
+
+	// iterating over prefixes
+	for _, prefix := range prefixes {
+
+		// more than one fragment has the prefix
+		if len(groups[prefix]) > 1 {
+			collisions = append(collisions, groups[prefix])
+		}
+	}
+
+	return collisions
+}
+
+// dropInOrder returns the numeric prefix of the fragment name and whether it has one.
+func dropInOrder(name string) (int, bool) {
+
+	// length of the prefix
+	end := strings.IndexFunc(name, func(r rune) bool {
+		return r < '0' || r > '9'
+	})
+	if end <= 0 {
+		return 0, false
+	}
+
+	// prefix as a number
+	order, err := strconv.Atoi(name[:end])
+
+	return order, err == nil
+}
+
+// defaultNames returns the files loaded when no file names are given: the default file followed
+// by the fragments of the .envfile.d directory, the default file is required only without fragments.
+func (l *Loader) defaultNames() []string {
+
+	// default file
+	filename := l.defaultFile()
+
+	// fragments of the drop-in directory, an unreadable directory is left to the default file
+	fragments, err := DropInFiles(dropInDir)
+	if err != nil || len(fragments) == 0 {
+		return []string{filename}
+	}
+
+	// default file is missing
+	if _, err := os.Stat(filename); err != nil {
+		return fragments
+	}
+
+	return append([]string{filename}, fragments...)
+}
@@ -0,0 +1,87 @@
+package envfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestDropInFiles tests numeric ordering of fragments and loading of them by default.
+func TestDropInFiles(t *testing.T) {
+
+	// temporary directory
+	dir, err := ioutil.TempDir("", "dropin")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v", err)
+	}
+
+	// deferred removal of the directory
+	defer os.RemoveAll(dir)
+
+	// fragments
+	fragments := filepath.Join(dir, dropInDir)
+	os.MkdirAll(fragments, 0755)
+	for name, content := range map[string]string{
+		"10-base.envfile":  "export DROPIN_HOST = base\nexport DROPIN_PORT = 80\n",
+		"9-tls.envfile":    "export DROPIN_TLS = on\n",
+		"20-db.envfile":    "overload DROPIN_HOST = db\n",
+		"20-cache.envfile": "export DROPIN_CACHE = on\n",
+		"local.envfile":    "export DROPIN_LOCAL = on\n",
+		"README.md":        "fragments",
+	} {
+		ioutil.WriteFile(filepath.Join(fragments, name), []byte(content), 0644)
+	}
+
+	// ordered fragments
+	files, err := DropInFiles(fragments)
+	if err != nil {
+		t.Fatalf("error listing fragments: %v", err)
+	}
+
+	// expected order
+	expected := []string{"9-tls.envfile", "10-base.envfile", "20-cache.envfile", "20-db.envfile", "local.envfile"}
+	for i := range expected {
+		expected[i] = filepath.Join(fragments, expected[i])
+	}
+
+	// order is different from expected
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+
+	// fragments with the same prefix
+	if collisions := DropInCollisions(files); !reflect.DeepEqual(collisions, [][]string{expected[2:4]}) {
+		t.Errorf("expected collision of 20-cache and 20-db, got %v", collisions)
+	}
+
+	// current working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("error getting working directory: %v", err)
+	}
+
+	// change working directory
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("error changing working directory: %v", err)
+	}
+
+	// deferred restore of working directory
+	defer os.Chdir(wd)
+
+	// deferred unset of the variables
+	for _, key := range []string{"DROPIN_HOST", "DROPIN_PORT", "DROPIN_TLS", "DROPIN_CACHE", "DROPIN_LOCAL"} {
+		defer os.Unsetenv(key)
+	}
+
+	// load fragments without the default file
+	if err := NewLoader().Load(); err != nil {
+		t.Fatalf("error loading fragments: %v", err)
+	}
+
+	// fragment loaded later overloads the value
+	if value := os.Getenv("DROPIN_HOST"); value != "db" {
+		t.Errorf("expected DROPIN_HOST to be db, got %s", value)
+	}
+}
@@ -0,0 +1,21 @@
+package envfile
+
+import "github.com/afonichev/envfile/parser"
+
+// Position is a place in the document: line number from one and column in bytes from zero.
+type Position = parser.Position
+
+// Range is a part of the document between two positions, the end is exclusive.
+type Range = parser.Range
+
+// Resolve converts the document into payloads, replacing variables with their values.
+func (l *Loader) Resolve(doc *Document) (Payloads, error) {
+
+	// convert document into payloads
+	payloads, err := l.payloads(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.resolve(doc.Name, payloads)
+}
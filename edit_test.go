@@ -0,0 +1,70 @@
+package envfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestDocumentApplyEdit tests incremental editing of the document.
+func TestDocumentApplyEdit(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "KEY_1 = value\nKEY_2 = { KEY_1 }\nKEY_3 = { KEY_2 }\nKEY_4 = other\n")
+
+	// parse document
+	doc, err := ParseDocument(filename)
+	if err != nil {
+		t.Fatalf("error parsing document: %v", err)
+	}
+
+	// replace the value of the first key with two lines
+	changed, err := doc.ApplyEdit(Range{Start: Position{Line: 1, Column: 8}, End: Position{Line: 1, Column: 13}}, "new\n# comment")
+	if err != nil {
+		t.Fatalf("error applying edit: %v", err)
+	}
+
+	// changed keys are different from expected
+	if expected := []string{"KEY_1", "KEY_2", "KEY_3"}; !reflect.DeepEqual(changed, expected) {
+		t.Errorf("expected changed keys %v, got %v", expected, changed)
+	}
+
+	// number of nodes is different from expected
+	if len(doc.Nodes) != 5 {
+		t.Fatalf("expected 5 nodes, got %d", len(doc.Nodes))
+	}
+
+	// iterating over a list of nodes
+	for i, node := range doc.Nodes {
+
+		// line number is not updated
+		if node.Line != i+1 {
+			t.Errorf("expected node %d to be on line %d, got %d", i, i+1, node.Line)
+		}
+	}
+
+	// resolve document
+	payloads, err := NewLoader().Resolve(doc)
+	if err != nil {
+		t.Fatalf("error resolving document: %v", err)
+	}
+
+	// value is not updated
+	if payloads[2].Key != "KEY_3" || payloads[2].Value != "new" {
+		t.Errorf("expected KEY_3 to be new, got %+v", payloads[2])
+	}
+
+	// append a line after the last one
+	if _, err := doc.ApplyEdit(Range{Start: Position{Line: 6, Column: 0}, End: Position{Line: 6, Column: 0}}, "KEY_5 = appended"); err != nil {
+		t.Fatalf("error applying edit: %v", err)
+	}
+
+	// line is not appended
+	if node := doc.Nodes[len(doc.Nodes)-1]; node.Line != 6 || node.Key != "KEY_5" {
+		t.Errorf("unexpected appended node: %+v", node)
+	}
+
+	// range outside of the document
+	if _, err := doc.ApplyEdit(Range{Start: Position{Line: 1, Column: 0}, End: Position{Line: 1, Column: 100}}, ""); err == nil {
+		t.Error("range is outside of the line but edit didn't return an error")
+	}
+}
@@ -0,0 +1,62 @@
+package envfile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// encodedName is used in error messages instead of the file name.
+const encodedName = "encoded"
+
+// Encode packs content of the env file into a single base64(gzip) string,
+// e.g. to pass it through one environment variable or cloud metadata field.
+// The gzip header has no name or modification time, so the same content gives the same string.
+func Encode(content []byte) (string, error) {
+
+	// compressed content
+	var buffer bytes.Buffer
+
+	// compressor
+	writer := gzip.NewWriter(&buffer)
+
+	// compress content
+	if _, err := writer.Write(content); err != nil {
+		return "", err
+	}
+
+	// flush compressed content
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+}
+
+// ParseEncoded parses the env file packed into a single base64(gzip) string.
+func ParseEncoded(data string) (Payloads, error) {
+	return NewLoader().ParseEncoded(data)
+}
+
+// ParseEncoded parses the env file packed into a single base64(gzip) string.
+func (l *Loader) ParseEncoded(data string) (Payloads, error) {
+
+	// decode data ignoring line breaks and spaces
+	compressed, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(data), ""))
+	if err != nil {
+		return nil, fmt.Errorf("[%s] can't decode base64: %s", encodedName, err)
+	}
+
+	// decompressor
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("[%s] can't decompress gzip: %s", encodedName, err)
+	}
+
+	// deferred decompressor close
+	defer reader.Close()
+
+	return l.parseFrom(encodedName, reader)
+}
@@ -0,0 +1,34 @@
+package envfile
+
+import "testing"
+
+// TestParseEncoded tests parsing of the packed env file.
+func TestParseEncoded(t *testing.T) {
+
+	// pack content
+	data, err := Encode([]byte("KEY_1 = value\nexport KEY_2 = { KEY_1 }\n"))
+	if err != nil {
+		t.Fatalf("error encoding env file: %v", err)
+	}
+
+	// parse packed content
+	payloads, err := ParseEncoded(data)
+	if err != nil {
+		t.Fatalf("error parsing encoded env file: %v", err)
+	}
+
+	// number of payloads is different from expected
+	if len(payloads) != 2 {
+		t.Fatalf("expected 2 payloads, got %d", len(payloads))
+	}
+
+	// value is different from expected
+	if payloads[1].Value != "value" {
+		t.Errorf("expected KEY_2 to be value, got %s", payloads[1].Value)
+	}
+
+	// parse invalid content
+	if _, err := ParseEncoded("not base64"); err == nil {
+		t.Error("content is not encoded but parse didn't return an error")
+	}
+}
@@ -1,477 +1,380 @@
 package envfile
 
 import (
-	"bufio"
+	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
-	"regexp"
-	"strings"
+	"time"
+
+	"github.com/afonichev/envfile/parser"
 )
 
 // Payload structure.
-type Payload struct {
-
-	// line number in file
-	Line int
-
-	// export status
-	Export bool
+type Payload = parser.Payload
 
-	// overload status
-	Overload bool
-
-	// key
-	Key string
-
-	// value
-	Value string
+// Load will load files with environment variables for this process.
+func Load(filenames ...string) error {
+	return std.Load(filenames...)
 }
 
-var (
-
-	// key name validation
-	validation = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
-
-	// unescape special characters
-	unescape = regexp.MustCompile(`\\.`)
-)
+// Parse parses file with environment variables, payloads are in the order they are written in the file.
+func Parse(filename string) (Payloads, error) {
+	return NewLoader().Parse(filename)
+}
 
 // Load will load files with environment variables for this process.
-func Load(filenames ...string) error {
+func (l *Loader) Load(filenames ...string) error {
 
 	// file name list is empty
 	if len(filenames) == 0 {
 
-		// add the default filename to the list
-		filenames = append(filenames, ".envfile")
+		// default file and drop-in fragments
+		filenames = l.defaultNames()
 	}
 
-	// iterating over a list of filenames
-	for _, filename := range filenames {
-
-		// parse file
-		payloads, err := Parse(filename)
-		if err != nil {
-			return err
-		}
-
-		// iteration over payloads
-		for _, payload := range payloads {
-
-			// key is exported or overloaded
-			if payload.Export || payload.Overload {
-
-				// key does not exist in environment variables or is overloaded
-				if value, ok := os.LookupEnv(payload.Key); !ok || payload.Overload {
-
-					// ignore overload on the same value
-					if payload.Value == value {
-						continue
-					}
-
-					// set key and value to environment variable
-					if err := os.Setenv(payload.Key, payload.Value); err != nil {
-						return fmt.Errorf("[%s] %s", filename, err)
-					}
-				}
-			}
-		}
-	}
-
-	return nil
+	return l.load(filenames, l.Parse, true)
 }
 
-// Parse parses file with environment variables.
-func Parse(filename string) ([]Payload, error) {
-
-	// open file with environment variables
-	file, err := os.Open(filename)
-	if err != nil {
-		return nil, err
-	}
-
-	// deferred file close
-	defer file.Close()
+// load sets keys of the files parsed by the function, states of local files are stored if stamp is set.
+func (l *Loader) load(filenames []string, parse func(filename string) (Payloads, error), stamp bool) error {
 
-	// line number
-	var line int
+	// start of loading
+	start := time.Now()
 
-	// payload list
-	var payloads []Payload
+	// result of loading
+	result := &Result{Files: filenames}
 
-	// line by line file reading
-	scanner := bufio.NewScanner(file)
+	// definitions of exported and overloaded keys
+	definitions := make(map[string][]Definition)
 
-	// iterate through the lines of the file
-	for scanner.Scan() {
+	// keys in order of the first definition
+	var keys []string
 
-		// increase line number
-		line++
+	// states of the loaded files, taken before parsing, so changes made meanwhile are detected
+	stamps := make(map[string]fileStamp)
 
-		// current line
-		current := strings.TrimSpace(scanner.Text())
-
-		// ignore blank lines and comments
-		if len(current) == 0 || strings.HasPrefix(current, "#") {
-			continue
-		}
-
-		// split current line with equal sign
-		pair := strings.SplitN(current, "=", 2)
-
-		// could not split current line
-		if len(pair) != 2 {
-			return nil, fmt.Errorf("[%s] line %d: can't split line into key and value", filename, line)
-		}
-
-		// payload
-		var payload Payload
-
-		// set line number
-		payload.Line = line
-
-		// set key name
-		payload.Key = strings.TrimSpace(pair[0])
-
-		// export directive
-		if strings.HasPrefix(strings.ToLower(payload.Key), "export") {
-
-			// update key name
-			payload.Key = strings.TrimSpace(payload.Key[6:])
+	// iterating over a list of filenames
+	for _, filename := range filenames {
 
-			// set export status
-			payload.Export = true
+		// state of the local file, errors are reported by parsing
+		if _, _, _, ok := l.remote(filename); stamp && !ok {
+			if stamp, err := stampFile(filename); err == nil {
+				stamps[filename] = stamp
+			}
 		}
 
-		// overload directive
-		if strings.HasPrefix(strings.ToLower(payload.Key), "overload") {
-
-			// update key name
-			payload.Key = strings.TrimSpace(payload.Key[8:])
+		// forget statistics of earlier parsing
+		l.takeStats(filename)
 
-			// set overload status
-			payload.Overload = true
+		// parse file
+		payloads, err := parse(filename)
+		if err != nil {
+			return err
 		}
 
-		// empty key name
-		if len(payload.Key) == 0 {
-			return nil, fmt.Errorf("[%s] line %d: key name is empty", filename, line)
-		}
+		// leases of the secrets by file and line
+		leases := map[string]map[int]time.Duration{filename: l.takeLeases(filename)}
 
-		// invalid key name
-		if !validation.MatchString(payload.Key) {
-			return nil, fmt.Errorf("[%s] line %d: invalid key name '%s'", filename, line, payload.Key)
-		}
+		// add statistics of the file
+		result.Stats.add(l.takeStats(filename))
+		result.Stats.Keys += len(payloads)
 
-		// iterating over a list of payloads
-		for _, pld := range payloads {
+		// iterating over payloads of included files
+		for _, payload := range payloads {
 
-			// key already exists in the payload list
-			if pld.Key == payload.Key {
-				return nil, fmt.Errorf("[%s] line %d: duplicate key '%s'", filename, line, payload.Key)
+			// leases and statistics of the included file are already taken
+			if _, ok := leases[parser.PayloadFile(payload, filename)]; ok {
+				continue
 			}
-		}
-
-		// set value
-		payload.Value = strings.TrimSpace(pair[1])
 
-		// add payload to list
-		payloads = append(payloads, payload)
-	}
-
-	// cycle of changing variables to their values
-	for {
-
-		// temporary storage of variable names with their positions
-		temp := make(map[string][][2]int)
+			// take leases and statistics of the included file
+			leases[payload.File] = l.takeLeases(payload.File)
+			result.Stats.add(l.takeStats(payload.File))
+		}
 
-		// iterating over a list of payloads
+		// iteration over payloads
 		for _, payload := range payloads {
 
-			// previous character
-			var previous rune
-
-			// parts list
-			var parts []string
-
-			// character list
-			var chars []rune
-
-			// iteration over value
-			for _, current := range payload.Value {
-
-				// start of variable
-				if previous != '{' && current == '{' {
+			// key is exported or overloaded
+			if payload.Export || payload.Overload {
 
-					// list of characters is not empty
-					if len(chars) > 0 {
+				// key as it is written for the first time
+				key := payload.Key
 
-						// combine characters and add to parts list
-						parts = append(parts, string(chars))
+				// iterating over keys
+				for _, name := range keys {
 
-						// clear the list of characters
-						chars = nil
+					// key is already defined
+					if l.sameKey(name, key) {
+						key = name
+						break
 					}
 				}
 
-				// end of variable
-				if previous == '}' && current != '}' {
-
-					// list of characters is not empty
-					if len(chars) > 0 {
-
-						// combine characters and add to parts list
-						parts = append(parts, string(chars))
-
-						// clear the list of characters
-						chars = nil
-					}
+				// key is defined for the first time
+				if _, ok := definitions[key]; !ok {
+					keys = append(keys, key)
 				}
 
-				// add the current character to the character list
-				chars = append(chars, current)
-
-				// change the previous character to the current one
-				previous = current
-			}
-
-			// list of characters is not empty
-			if len(chars) > 0 {
-
-				// combine characters and add to parts list
-				parts = append(parts, string(chars))
-			}
-
-			// offset to get the position of the variable in the original value
-			var offset int
-
-			// iteration by parts
-			for i, part := range parts {
-
-				// number of opening curly braces
-				opening := strings.Count(part, "{")
-
-				// number of closing curly braces
-				closing := strings.Count(part, "}")
-
-				// an even number of opening curly braces status
-				evenOpening := (opening % 2) == 0
-
-				// an even number of closing curly braces status
-				evenClosing := (closing % 2) == 0
+				// add definition of the key
+				definitions[key] = append(definitions[key], Definition{
+					File: parser.PayloadFile(payload, filename),
+					Line: payload.Line,
+					Hash: hashValue(payload.Value),
+				})
+
+				// loaded key
+				entry := Entry{
+					Key:    payload.Key,
+					Value:  payload.Value,
+					File:   parser.PayloadFile(payload, filename),
+					Line:   payload.Line,
+					Status: StatusKept,
+					TTL:    leases[parser.PayloadFile(payload, filename)][payload.Line],
+				}
 
-				// only open curly braces were found and their number is odd
-				if !evenOpening && evenClosing {
+				// key does not exist in environment variables or is overloaded
+				if value, ok := l.lookupEnv(payload.Key); !ok || payload.Overload {
 
-					// there are more opening curly braces than closing curly braces
-					if opening > closing {
+					// ignore overload on the same value
+					if payload.Value == value {
+						entry.Status = StatusUnchanged
+					} else {
 
-						// current part is the last and is equal to the opening curly brace
-						if (i == len(parts)-1) && (part == "{") {
-							return nil, fmt.Errorf("[%s] line %d: excess opening curly brace '{' in at the end",
-								filename, payload.Line)
+						// value is rejected by the policy
+						if l.policy != nil {
+							if err := l.policy(payload.Key, payload.Value); err != nil {
+								return fmt.Errorf("[%s] line %d: key '%s': %s", parser.PayloadFile(payload, filename), payload.Line, payload.Key, err)
+							}
 						}
 
-						return nil, fmt.Errorf("[%s] line %d: can't find the closing curly brace '}'",
-							filename, payload.Line)
-					}
-
-					// there are fewer opening curly braces than closing curly braces
-					if opening < closing {
-						return nil, fmt.Errorf("[%s] line %d: excess closing curly brace '}'", filename, payload.Line)
-					}
-				}
-
-				// only close curly braces were found and their number is odd
-				if evenOpening && !evenClosing {
-
-					// there are more opening curly braces than closing curly braces
-					if opening > closing {
-						return nil, fmt.Errorf("[%s] line %d: excess opening curly brace '{'", filename, payload.Line)
-					}
+						// set key and value to environment variable
+						if err := l.setenv(payload.Key, payload.Value); err != nil {
+							return fmt.Errorf("[%s] %s", filename, err)
+						}
 
-					// there are fewer opening curly braces than closing curly braces
-					if opening < closing {
+						// update status
+						entry.Status = StatusSet
 
-						// current part is the first and is equal to the closing curly brace
-						if (i == 0) && (part == "}") {
-							return nil, fmt.Errorf("[%s] line %d: excess closing curly brace '}' at the beginning",
-								filename, payload.Line)
+						// write audit record
+						if err := l.writeAudit(entry); err != nil {
+							return fmt.Errorf("[%s] can't write audit record: %s", filename, err)
 						}
-
-						return nil, fmt.Errorf("[%s] line %d: can't find the opening curly brace '{'",
-							filename, payload.Line)
 					}
 				}
 
-				// found open and close curly braces and their number is odd
-				if !evenOpening && !evenClosing {
-
-					// start of variable
-					start := offset + opening
+				// add entry to result
+				result.Entries = append(result.Entries, entry)
+			}
+		}
+	}
 
-					// end of variable
-					end := offset + (len(part) - closing)
+	// keys defined in more than one file
+	result.Duplicates = duplicates(keys, definitions)
 
-					// variable
-					variable := strings.TrimSpace(payload.Value[start:end])
+	// duration of loading
+	result.Stats.Duration = time.Since(start)
 
-					// empty variable name
-					if len(variable) == 0 {
-						return nil, fmt.Errorf("[%s] line %d: variable name is empty", filename, payload.Line)
-					}
+	// store result
+	l.setResult(result)
 
-					// variable name is the same as the name of the current key
-					if payload.Key == variable {
-						return nil, fmt.Errorf("[%s] line %d: key '%s' is used recursively",
-							filename, payload.Line, payload.Key)
-					}
+	// store states of the loaded files
+	l.setStamps(stamps)
 
-					// add a variable and its position to temporary storage
-					temp[payload.Key] = append(temp[payload.Key], [2]int{
+	return nil
+}
 
-						// start of variable
-						start,
+// Parse parses file with environment variables, payloads are in the order they are written in the file.
+func (l *Loader) Parse(filename string) (Payloads, error) {
 
-						// end of variable
-						end,
-					})
-				}
+	// name of the remote service configuration
+	if remote, project, config, ok := l.remote(filename); ok {
 
-				// increase offset by part length
-				offset += len(part)
-			}
+		// fetch payloads
+		payloads, err := fetch(filename, remote, project, config)
+		if err != nil {
+			return nil, err
 		}
 
-		// temporary storage is not empty
-		if len(temp) > 0 {
-
-			// iterating over temporary storage
-			for variable, positions := range temp {
+		return l.resolve(filename, payloads)
+	}
 
-				// iterating over a list of payloads
-				for i, payload := range payloads {
+	// open file with environment variables
+	file, err := l.openFile(filename)
+	if err != nil {
+		return nil, err
+	}
 
-					// key exists in the list of payloads
-					if payload.Key == variable {
+	// deferred file close
+	defer file.Close()
 
-						// current line number
-						line := payload.Line
+	return l.parseFrom(filename, file)
+}
 
-						// iterating over variable positions
-						for i := len(positions) - 1; i >= 0; i-- {
+// parseFrom parses payloads from the reader, the name is used in error messages.
+func (l *Loader) parseFrom(filename string, reader io.Reader) (Payloads, error) {
 
-							// variable position
-							position := positions[i]
+	// read payloads
+	payloads, err := l.readFrom(filename, reader)
+	if err != nil {
+		return nil, err
+	}
 
-							// start of variable
-							start := position[0]
+	return l.resolve(filename, payloads)
+}
 
-							// end of variable
-							end := position[1]
+// resolve replaces variables with their values, runs commands, resolves value sources and recognizes types.
+func (l *Loader) resolve(filename string, payloads []Payload) ([]Payload, error) {
 
-							// variable
-							variable := strings.TrimSpace(payload.Value[start:end])
+	// replace variables with their values
+	payloads, err := l.expand(filename, payloads)
+	if err != nil {
+		return nil, err
+	}
 
-							// character list
-							var chars []rune
+	// values written as $(command args) are replaced by the output of the commands
+	if l.commandTimeout > 0 && l.dialect == DialectDefault && !l.legacy {
 
-							// add everything before the variable to the character list
-							chars = append(chars, []rune(payload.Value[:start-1])...)
+		// run commands
+		payloads, err = l.substitute(filename, payloads)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-							// variable value
-							var value *string
+	// value prefixes are enabled
+	if l.sources {
 
-							// iterating over a list of payloads
-							for _, payload := range payloads {
+		// decode and read values having a source prefix
+		payloads, err = resolveSources(filename, payloads)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-								// variable exists in the list of payloads
-								if payload.Key == variable {
+	// line endings inside values are normalized
+	if l.newlines {
+		payloads = normalizeNewlines(payloads)
+	}
 
-									// update variable value
-									value = &payload.Value
+	// values are transformed
+	if len(l.transforms) > 0 {
 
-									// exit loop
-									break
-								}
-							}
+		// apply transformers
+		payloads, err = l.transform(filename, payloads)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-							// variable value is missing
-							if value == nil {
+	// iterating over a list of payloads
+	for i := range payloads {
 
-								// variable value from environment variables
-								value, ok := os.LookupEnv(variable)
+		// quoted value is a string, whatever it looks like
+		if len(payloads[i].Quote) > 0 {
+			continue
+		}
 
-								// variable does not exist
-								if !ok {
-									return nil, fmt.Errorf("[%s] line %d: variable '%s' does not exist",
-										filename, line, variable)
-								}
+		// recognize type of value
+		payloads[i].Kind = parser.KindOf(payloads[i].Value)
+	}
 
-								// add variable value to character list
-								chars = append(chars, []rune(value)...)
+	return payloads, nil
+}
 
-							} else {
+// read reads payloads from file leaving values as they are written.
+func (l *Loader) read(filename string) ([]Payload, error) {
 
-								// add variable value to character list
-								chars = append(chars, []rune(*value)...)
-							}
+	// name of the remote service configuration
+	if remote, project, config, ok := l.remote(filename); ok {
+		return fetch(filename, remote, project, config)
+	}
 
-							// add everything after the variable to the character list
-							chars = append(chars, []rune(payload.Value[end+1:])...)
+	// open file with environment variables
+	file, err := l.openFile(filename)
+	if err != nil {
+		return nil, err
+	}
 
-							// combine characters and update value
-							payload.Value = string(chars)
-						}
-					}
+	// deferred file close
+	defer file.Close()
 
-					// update payload
-					payloads[i] = payload
-				}
-			}
+	return l.readFrom(filename, file)
+}
 
-		} else {
+// openFile opens the file with environment variables, verifying its signature if verification
+// is enabled: the verified content is the content that is parsed.
+func (l *Loader) openFile(filename string) (io.ReadCloser, error) {
 
-			// exit loop
-			break
-		}
+	// verification is disabled
+	if l.publicKey == nil {
+		return os.Open(filename)
 	}
 
-	// iterating over a list of payloads
-	for i, payload := range payloads {
+	// read content
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
 
-		// unescape the open curly brace
-		payload.Value = strings.ReplaceAll(payload.Value, "{{", "{")
+	// verify signature of the content
+	if err := l.verifySignature(filename, content, ioutil.ReadFile); err != nil {
+		return nil, err
+	}
 
-		// unescape the close curly brace
-		payload.Value = strings.ReplaceAll(payload.Value, "}}", "}")
+	return ioutil.NopCloser(bytes.NewReader(content)), nil
+}
 
-		// unescape the special characters
-		payload.Value = unescape.ReplaceAllStringFunc(payload.Value, func(match string) string {
+// readFrom reads payloads from the reader leaving values as they are written,
+// the name is used in error messages.
+func (l *Loader) readFrom(filename string, reader io.Reader) ([]Payload, error) {
 
-			switch strings.TrimPrefix(match, "\\") {
+	// read document
+	doc, err := l.readDocument(filename, reader)
+	if err != nil {
+		return nil, err
+	}
 
-			// new line
-			case "n":
-				return "\n"
+	return l.payloads(doc)
+}
 
-			// horizontal tab
-			case "t":
-				return "\t"
+// resolver returns the resolver of payloads of the file with the options of the loader,
+// variables, included files and secrets are taken through the loader.
+func (l *Loader) resolver(filename string) *parser.Resolver {
+	return &parser.Resolver{
+		Profile:         l.profile,
+		ContinueOnError: l.continueOnError,
+		Join:            l.join,
+		Separator:       l.separator,
+		CaseInsensitive: l.caseInsensitive,
+		Data:            l.data,
+		Lookup:          l.lookupEnv,
+		Include: func(doc *Document, path string) (*Document, error) {
+			return l.includeDocument(filename, doc, path)
+		},
+		Secret:    l.provide,
+		Undefined: l.undefined,
+	}
+}
 
-			// backslash
-			case "\\":
-				return "\\"
+// payloads converts the document into payloads leaving values as they are written.
+func (l *Loader) payloads(doc *Document) ([]Payload, error) {
+	return l.resolver(doc.Name).Payloads(doc)
+}
 
-			// any
-			default:
-				return match
-			}
-		})
+// expand replaces variables with their values and unescapes special characters.
+func (l *Loader) expand(filename string, payloads []Payload) ([]Payload, error) {
 
-		// update payload
-		payloads[i] = payload
+	// replace references
+	payloads, expansions, err := l.resolver(filename).Expand(l.syntax(), filename, payloads)
+	if err != nil {
+		return nil, err
 	}
 
+	// record number of replaced references
+	l.count(filename, Stats{Expansions: expansions})
+
 	return payloads, nil
 }
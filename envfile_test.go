@@ -1,10 +1,34 @@
 package envfile
 
 import (
+	"io/ioutil"
 	"os"
 	"testing"
 )
 
+// createFile creates a temporary file with the given content.
+func createFile(t *testing.T, content string) string {
+
+	// create temporary file
+	file, err := ioutil.TempFile("", "*.envfile")
+	if err != nil {
+		t.Fatalf("error creating temporary file: %v", err)
+	}
+
+	// deferred file close
+	defer file.Close()
+
+	// remove file when the test is over
+	t.Cleanup(func() { os.Remove(file.Name()) })
+
+	// write content to file
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatalf("error writing temporary file: %v", err)
+	}
+
+	return file.Name()
+}
+
 // TestLoadDefaultFile tests the default file loading.
 func TestLoadDefaultFile(t *testing.T) {
 
@@ -40,6 +64,8 @@ func TestParseFile(t *testing.T) {
 		"KEY_5": "{ KEY_1 } of another variable",
 		"KEY_6": "Title:\n\t1. value\n\t2. value\n\t3. value",
 		"KEY_7": "Title:\\n\\t1. value\\n\\t2. value\\n\\t3. value",
+		"KEY_8": "value",
+		"KEY_9": `^\d{4}-\d{2}$`,
 	}
 
 	// parse file
@@ -68,3 +94,176 @@ func TestParseFile(t *testing.T) {
 		}
 	}
 }
+
+// TestLoadConditional tests the conditional assignment operator.
+func TestLoadConditional(t *testing.T) {
+
+	// set environment variable for the test
+	os.Setenv("ENVFILE_CONDITIONAL_1", "environment")
+
+	// deferred removal of environment variables
+	defer os.Unsetenv("ENVFILE_CONDITIONAL_1")
+	defer os.Unsetenv("ENVFILE_CONDITIONAL_2")
+
+	// file defining the second key
+	first := createFile(t, "export ENVFILE_CONDITIONAL_2 = first\n")
+
+	// file with default values
+	second := createFile(t, "ENVFILE_CONDITIONAL_1 ?= default\nENVFILE_CONDITIONAL_2?=default\n")
+
+	// load files
+	if err := Load(first, second); err != nil {
+		t.Fatalf("error loading env files: %v", err)
+	}
+
+	// expected key/value pairs
+	pairs := map[string]string{
+		"ENVFILE_CONDITIONAL_1": "environment",
+		"ENVFILE_CONDITIONAL_2": "first",
+	}
+
+	// iterating over expected key/value pairs
+	for key, value := range pairs {
+
+		// value from environment is different from expected
+		if actual := os.Getenv(key); actual != value {
+			t.Errorf("expected %s to be %s, got %s", key, value, actual)
+		}
+	}
+
+	// conditional assignment with overload directive
+	if _, err := Parse(createFile(t, "overload KEY ?= value\n")); err == nil {
+		t.Error("conditional assignment is overloaded but parse didn't return an error")
+	}
+}
+
+// TestLoadDuplicates tests recording of keys defined in more than one file.
+func TestLoadDuplicates(t *testing.T) {
+
+	// deferred removal of environment variables
+	defer os.Unsetenv("ENVFILE_DUPLICATE_1")
+	defer os.Unsetenv("ENVFILE_DUPLICATE_2")
+
+	// first file
+	first := createFile(t, "export ENVFILE_DUPLICATE_1 = first\nexport ENVFILE_DUPLICATE_2 = value\n")
+
+	// second file
+	second := createFile(t, "overload ENVFILE_DUPLICATE_2 = value\nENVFILE_DUPLICATE_3 = local\n")
+
+	// loader
+	loader := NewLoader()
+
+	// load files
+	if err := loader.Load(first, second); err != nil {
+		t.Fatalf("error loading env files: %v", err)
+	}
+
+	// keys defined in more than one file
+	duplicates := loader.Result().Duplicates
+
+	// number of duplicates is different from expected
+	if len(duplicates) != 1 || duplicates[0].Key != "ENVFILE_DUPLICATE_2" {
+		t.Fatalf("expected ENVFILE_DUPLICATE_2 to be the only duplicate, got %+v", duplicates)
+	}
+
+	// definitions of the duplicate
+	defs := duplicates[0].Definitions
+
+	// definitions are different from expected
+	if len(defs) != 2 || defs[0].File != first || defs[0].Line != 2 || defs[1].File != second || defs[1].Line != 1 {
+		t.Fatalf("unexpected definitions: %+v", defs)
+	}
+
+	// same values have different hashes
+	if defs[0].Hash != defs[1].Hash || defs[0].Hash != hashValue("value") {
+		t.Errorf("unexpected hashes: %s and %s", defs[0].Hash, defs[1].Hash)
+	}
+}
+
+// TestParseList tests accumulation of repeated list items.
+func TestParseList(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "export LISTEN[] = :8080\nexport LISTEN[] = :8081\nHOSTS [] = a\nHOSTS[] = b\n")
+
+	// expected key/value pairs with indexed keys
+	indexed := map[string]string{
+		"LISTEN_0": ":8080",
+		"LISTEN_1": ":8081",
+		"HOSTS_0":  "a",
+		"HOSTS_1":  "b",
+	}
+
+	// expected key/value pairs with joined values
+	joined := map[string]string{
+		"LISTEN": ":8080,:8081",
+		"HOSTS":  "a,b",
+	}
+
+	// iterating over loaders and expected pairs
+	for loader, pairs := range map[*Loader]map[string]string{
+		NewLoader():                       indexed,
+		NewLoader(WithListSeparator(",")): joined,
+	} {
+
+		// parse file
+		payloads, err := loader.Parse(filename)
+		if err != nil {
+			t.Fatalf("error parsing env file: %v", err)
+		}
+
+		// number of payloads is different from expected
+		if len(payloads) != len(pairs) {
+			t.Errorf("expected %d payloads, got %d", len(pairs), len(payloads))
+		}
+
+		// iteration over payloads
+		for _, payload := range payloads {
+
+			// value from payload is different from expected
+			if payload.Value != pairs[payload.Key] {
+				t.Errorf("expected %s to be %s, got %s", payload.Key, pairs[payload.Key], payload.Value)
+			}
+		}
+	}
+}
+
+// TestParseRaw tests the raw directive.
+func TestParseRaw(t *testing.T) {
+
+	// file content
+	filename := createFile(t, `
+raw REGEX = ^\d{4}-\d{2}$
+export raw PATH_VALUE = C:\new\{ dir }
+RAW_KEY = value
+export PATTERN = "{ REGEX }" in { PATH_VALUE }\t{ RAW_KEY }
+`)
+
+	// expected key/value pairs
+	pairs := map[string]string{
+		"REGEX":      `^\d{4}-\d{2}$`,
+		"PATH_VALUE": `C:\new\{ dir }`,
+		"RAW_KEY":    "value",
+		"PATTERN":    `"^\d{4}-\d{2}$" in C:\new\{ dir }` + "\tvalue",
+	}
+
+	// parse file
+	payloads, err := Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// iteration over payloads
+	for _, payload := range payloads {
+
+		// value from payload is different from expected
+		if payload.Value != pairs[payload.Key] {
+			t.Errorf("expected %s to be %s, got %s", payload.Key, pairs[payload.Key], payload.Value)
+		}
+	}
+
+	// raw status is not set
+	if !payloads[0].Literal || !payloads[1].Literal || !payloads[1].Export || payloads[2].Literal {
+		t.Errorf("unexpected raw status: %+v", payloads)
+	}
+}
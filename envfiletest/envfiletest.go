@@ -0,0 +1,197 @@
+// Package envfiletest checks parsing of env files against a corpus of syntax edge cases,
+// so integrators can verify that their loader options handle every known form.
+//
+// A corpus is a directory of cases: every NAME.envfile is parsed and compared with
+// NAME.golden, a JSON object with either the expected "values" by key or the expected
+// "error" without the file name.
+package envfiletest
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/afonichev/envfile"
+)
+
+// corpus is the shipped corpus with a directory per dialect.
+//
+//go:embed testdata
+var corpus embed.FS
+
+// Case is a case of the corpus.
+type Case struct {
+
+	// case name, the file name without extension
+	Name string
+
+	// env file name in the corpus
+	File string
+
+	// expected values by key
+	Values map[string]string `json:"values"`
+
+	// expected error without the file name
+	Error string `json:"error"`
+}
+
+// Corpus returns the shipped corpus of the dialect.
+func Corpus(dialect envfile.Dialect) fs.FS {
+
+	// corpus of the dialect, the directory exists for every dialect
+	fsys, _ := fs.Sub(corpus, path.Join("testdata", dialect.String()))
+
+	return fsys
+}
+
+// Cases returns the cases of the corpus in alphabetical order.
+func Cases(fsys fs.FS) ([]Case, error) {
+
+	// env files of the corpus
+	files, err := fs.Glob(fsys, "*.envfile")
+	if err != nil {
+		return nil, err
+	}
+
+	// sort files
+	sort.Strings(files)
+
+	// cases list
+	cases := make([]Case, 0, len(files))
+
+	// iterating over env files
+	for _, file := range files {
+
+		// case name
+		name := strings.TrimSuffix(file, ".envfile")
+
+		// read expected result
+		data, err := fs.ReadFile(fsys, name+".golden")
+		if err != nil {
+			return nil, err
+		}
+
+		// case
+		c := Case{Name: name, File: file}
+
+		// decode expected result
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("[%s.golden] %s", name, err)
+		}
+
+		// add case to list
+		cases = append(cases, c)
+	}
+
+	return cases, nil
+}
+
+// Check parses the case with the loader and reports differences from the expected result.
+func (c Case) Check(fsys fs.FS, loader *envfile.Loader) error {
+
+	// parse file
+	payloads, err := loader.ParseFS(fsys, c.File)
+
+	// error is expected
+	if len(c.Error) > 0 {
+
+		// error is different from expected
+		if err == nil || err.Error() != "["+c.File+"] "+c.Error {
+			return fmt.Errorf("expected error %q, got %v", c.Error, err)
+		}
+
+		return nil
+	}
+
+	// unexpected error
+	if err != nil {
+		return err
+	}
+
+	// differences list
+	var differences []string
+
+	// values by key
+	index := payloads.Index()
+
+	// iterating over keys of the payloads
+	for _, key := range payloads.Keys() {
+
+		// key is not expected
+		if _, ok := c.Values[key]; !ok {
+			differences = append(differences, fmt.Sprintf("unexpected key %s", key))
+		}
+	}
+
+	// expected keys
+	keys := make([]string, 0, len(c.Values))
+	for key := range c.Values {
+		keys = append(keys, key)
+	}
+
+	// sort keys
+	sort.Strings(keys)
+
+	// iterating over expected keys
+	for _, key := range keys {
+
+		// payload of the key
+		payload, ok := index[key]
+
+		// key is missing
+		if !ok {
+			differences = append(differences, fmt.Sprintf("missing key %s", key))
+			continue
+		}
+
+		// value is different from expected
+		if payload.Value != c.Values[key] {
+			differences = append(differences, fmt.Sprintf("expected %s to be %q, got %q", key, c.Values[key], payload.Value))
+		}
+	}
+
+	// result is different from expected
+	if len(differences) > 0 {
+		return fmt.Errorf("%s", strings.Join(differences, "; "))
+	}
+
+	return nil
+}
+
+// Run parses every case of the corpus with a loader created with the options,
+// each case is a subtest named after its file.
+func Run(t *testing.T, fsys fs.FS, options ...envfile.Option) {
+
+	// mark as helper
+	t.Helper()
+
+	// cases of the corpus
+	cases, err := Cases(fsys)
+	if err != nil {
+		t.Fatalf("error reading corpus: %v", err)
+	}
+
+	// loader
+	loader := envfile.NewLoader(options...)
+
+	// iterating over cases
+	for _, c := range cases {
+
+		// case for the closure
+		c := c
+
+		// run case
+		t.Run(c.Name, func(t *testing.T) {
+
+			// result is different from expected
+			if err := c.Check(fsys, loader); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package envfiletest
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/afonichev/envfile"
+)
+
+// TestCorpus tests the shipped corpus with the default options of every dialect.
+func TestCorpus(t *testing.T) {
+
+	// iterating over dialects
+	for _, dialect := range []envfile.Dialect{
+		envfile.DialectDefault,
+		envfile.DialectKubernetes,
+		envfile.DialectDocker,
+		envfile.DialectDotenvExpand,
+	} {
+
+		// run corpus of the dialect
+		t.Run(dialect.String(), func(t *testing.T) {
+			Run(t, Corpus(dialect), envfile.WithDialect(dialect))
+		})
+	}
+}
+
+// TestCheck tests reporting of differences from the expected result.
+func TestCheck(t *testing.T) {
+
+	// corpus with a wrong expectation
+	fsys := fstest.MapFS{
+		"case.envfile": {Data: []byte("KEY_1 = value\nKEY_2 = extra\n")},
+		"case.golden":  {Data: []byte(`{"values": {"KEY_1": "other", "KEY_3": ""}}`)},
+	}
+
+	// cases of the corpus
+	cases, err := Cases(fsys)
+	if err != nil {
+		t.Fatalf("error reading corpus: %v", err)
+	}
+
+	// check case
+	err = cases[0].Check(fsys, envfile.NewLoader())
+
+	// differences are different from expected
+	if err == nil || err.Error() != `unexpected key KEY_2; expected KEY_1 to be "other", got "value"; missing key KEY_3` {
+		t.Errorf("unexpected differences: %v", err)
+	}
+}
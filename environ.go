@@ -0,0 +1,174 @@
+package envfile
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// environName is used in error messages instead of the file name.
+const environName = "environ"
+
+// ApplyEnviron returns the environment of the process as it would be after the package-level Load,
+// see Loader.ApplyEnviron.
+func ApplyEnviron(filenames ...string) ([]string, error) {
+	return std.ApplyEnviron(filenames...)
+}
+
+// ApplyEnviron returns the environment as it would be after Load in the form "key=value",
+// computed once without changing it: variables keep their positions, new keys are added in order
+// of the files. It is meant for processes that re-execute themselves with syscall.Exec, where setting
+// keys one by one is costly or races with other goroutines. The value policy is checked as by Load.
+func (l *Loader) ApplyEnviron(filenames ...string) ([]string, error) {
+
+	// values of the files
+	values, err := l.ParseValues(filenames...)
+	if err != nil {
+		return nil, err
+	}
+
+	// variables of the environment
+	environ, err := l.environ()
+	if err != nil {
+		return nil, err
+	}
+
+	// positions of variables by name
+	positions := make(map[string]int, len(environ))
+
+	// iterating over variables
+	for i, variable := range environ {
+
+		// remember position of the variable
+		positions[l.indexKey(strings.SplitN(variable, "=", 2)[0])] = i
+	}
+
+	// iterating over keys of the files
+	for _, key := range values.keys {
+
+		// effective value of the key
+		current := values.values[values.indexKey(key)]
+
+		// value comes from the environment
+		if len(current.file) == 0 {
+			continue
+		}
+
+		// value is rejected by the policy
+		if l.policy != nil {
+			if err := l.policy(key, current.value); err != nil {
+				return nil, fmt.Errorf("[%s] line %d: key '%s': %s", current.file, current.line, key, err)
+			}
+		}
+
+		// variable already exists
+		if i, ok := positions[l.indexKey(key)]; ok {
+
+			// replace variable, keeping the name it has
+			environ[i] = strings.SplitN(environ[i], "=", 2)[0] + "=" + current.value
+
+			continue
+		}
+
+		// add variable
+		environ = append(environ, key+"="+current.value)
+	}
+
+	return environ, nil
+}
+
+// ParseEnviron parses a null-delimited stream of variables, see Loader.ParseEnviron.
+func ParseEnviron(r io.Reader) (Payloads, error) {
+	return NewLoader().ParseEnviron(r)
+}
+
+// ParseEnviron parses a null-delimited stream of variables in the form "key=value", as written
+// by env -0 or found in /proc/<pid>/environ, so the environment of a process can be validated,
+// compared and encoded like a file. Every key is exported and values are taken as they are,
+// without references or escape sequences. Records with keys a file can't define, such as
+// BASH_FUNC_name%% of exported bash functions, are skipped. Errors are reported for the "environ" file,
+// by the number of the record.
+func (l *Loader) ParseEnviron(r io.Reader) (Payloads, error) {
+	return l.parseEnviron(environName, r)
+}
+
+// parseEnviron parses a null-delimited stream of variables, the name is used in error messages.
+func (l *Loader) parseEnviron(name string, r io.Reader) (Payloads, error) {
+
+	// content of the stream
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %s", name, err)
+	}
+
+	// payload list
+	var payloads Payloads
+
+	// errors of the records
+	var errs []error
+
+	// iterating over records, the last delimiter is optional
+	for i, text := range strings.Split(strings.TrimSuffix(string(content), "\x00"), "\x00") {
+
+		// record number
+		record := i + 1
+
+		// empty stream
+		if len(text) == 0 && len(content) == 0 {
+			break
+		}
+
+		// key and value of the record
+		key, value, ok := strings.Cut(text, "=")
+
+		// keys a file can't define, e.g. exported bash functions, are skipped
+		if ok && !parser.ValidKey(key) {
+			continue
+		}
+
+		// problem with the record
+		var problem error
+
+		switch {
+
+		// could not split record
+		case !ok:
+			problem = fmt.Errorf("[%s] record %d: can't split record into key and value", name, record)
+		}
+
+		// iterating over a list of payloads
+		for _, payload := range payloads {
+
+			// key already exists in the payload list
+			if problem == nil && l.sameKey(payload.Key, key) {
+				problem = fmt.Errorf("[%s] record %d: duplicate key '%s'", name, record, key)
+			}
+		}
+
+		// record can't be added
+		if problem != nil {
+
+			// add error of the record
+			errs = append(errs, problem)
+
+			// next record is checked when collecting all errors
+			if l.continueOnError {
+				continue
+			}
+
+			break
+		}
+
+		// add payload to list
+		payloads = append(payloads, Payload{Line: record, Export: true, Literal: true, Key: key, Value: value, Raw: value, Kind: parser.KindOf(value)})
+	}
+
+	// errors are found
+	if len(errs) > 0 {
+		return nil, parser.JoinErrors(errs)
+	}
+
+	return payloads, nil
+}
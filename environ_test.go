@@ -0,0 +1,99 @@
+package envfile
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// TestApplyEnviron tests computing the environment after loading without changing it.
+func TestApplyEnviron(t *testing.T) {
+
+	// file content
+	filename := createFile(t, `
+export KEPT = file
+overload REPLACED = file
+export ADDED = file
+LOCAL = file
+`)
+
+	// environment
+	env := MapEnvironment{"KEPT": "env", "REPLACED": "env", "OTHER": "env"}
+
+	// compute environment
+	environ, err := NewLoader(WithEnvironment(env)).ApplyEnviron(filename)
+	if err != nil {
+		t.Fatalf("error computing environment: %v", err)
+	}
+
+	// variables keep their positions, new keys are added at the end
+	if expected := []string{"KEPT=env", "OTHER=env", "REPLACED=file", "ADDED=file"}; !reflect.DeepEqual(environ, expected) {
+		t.Errorf("expected %v, got %v", expected, environ)
+	}
+
+	// environment is not changed
+	if env["REPLACED"] != "env" || len(env) != 3 {
+		t.Errorf("environment is changed: %v", env)
+	}
+
+	// value rejected by the policy
+	_, err = NewLoader(WithEnvironment(env), WithValuePolicy(func(key, value string) error {
+		return errors.New("rejected")
+	})).ApplyEnviron(filename)
+	if err == nil || !strings.Contains(err.Error(), "key 'REPLACED'") {
+		t.Errorf("expected policy error, got %v", err)
+	}
+}
+
+// TestParseEnviron tests parsing of null-delimited streams of variables.
+func TestParseEnviron(t *testing.T) {
+
+	// stream as written by env -0
+	payloads, err := ParseEnviron(strings.NewReader("HOME=/root\x00URL=http://{ HOST }/a=b\x00EMPTY=\x00PORT=8080\x00"))
+	if err != nil {
+		t.Fatalf("error parsing stream: %v", err)
+	}
+
+	// expected payloads
+	expected := Payloads{
+		{Line: 1, Export: true, Literal: true, Key: "HOME", Value: "/root", Raw: "/root", Kind: parser.KindOf("/root")},
+		{Line: 2, Export: true, Literal: true, Key: "URL", Value: "http://{ HOST }/a=b", Raw: "http://{ HOST }/a=b", Kind: parser.KindOf("http://{ HOST }/a=b")},
+		{Line: 3, Export: true, Literal: true, Key: "EMPTY", Kind: parser.KindOf("")},
+		{Line: 4, Export: true, Literal: true, Key: "PORT", Value: "8080", Raw: "8080", Kind: parser.KindOf("8080")},
+	}
+
+	// payloads are different from expected
+	if !reflect.DeepEqual(payloads, expected) {
+		t.Errorf("expected %+v, got %+v", expected, payloads)
+	}
+
+	// keys a file can't define are skipped
+	payloads, err = ParseEnviron(strings.NewReader("A=1\x00BASH_FUNC_greet%%=() {  echo hello\n}\x00B=2\x00"))
+	if err != nil || len(payloads) != 2 || payloads[0].Key != "A" || payloads[1].Key != "B" || payloads[1].Line != 3 {
+		t.Errorf("expected payloads A and B, got %+v, %v", payloads, err)
+	}
+
+	// last delimiter is optional, empty stream has no payloads
+	if payloads, err := ParseEnviron(strings.NewReader("A=1")); err != nil || len(payloads) != 1 {
+		t.Errorf("expected one payload, got %+v, %v", payloads, err)
+	}
+	if payloads, err := ParseEnviron(strings.NewReader("")); err != nil || len(payloads) != 0 {
+		t.Errorf("expected no payloads, got %+v, %v", payloads, err)
+	}
+
+	// first problem only
+	stream := "A=1\x00invalid\x00BAD-KEY=1\x00A=2\x00"
+	if _, err := ParseEnviron(strings.NewReader(stream)); err == nil || err.Error() != "[environ] record 2: can't split record into key and value" {
+		t.Errorf("expected error of record 2, got %v", err)
+	}
+
+	// all problems
+	_, err = NewLoader(WithContinueOnError()).ParseEnviron(strings.NewReader(stream))
+	if expected := "[environ] record 2: can't split record into key and value\n" +
+		"[environ] record 4: duplicate key 'A'"; err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
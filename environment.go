@@ -0,0 +1,126 @@
+package envfile
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Environment is the backend of environment variables used for references, docker keys
+// without values, drift checks and loading. The environment of the process is used by default,
+// MapEnvironment keeps variables in memory for platforms like js/wasm and wasip1, where the
+// environment of the process is emulated or not shared with the host, e.g. in browser-based editors.
+type Environment interface {
+
+	// LookupEnv returns the value of the variable and whether it exists.
+	LookupEnv(key string) (string, bool)
+
+	// Setenv sets the value of the variable.
+	Setenv(key, value string) error
+}
+
+// ListableEnvironment is an environment whose variables can be listed, ApplyEnviron and Capture fail
+// with other environments and case-insensitive keys match variables with exactly the same name only.
+type ListableEnvironment interface {
+	Environment
+
+	// Environ returns the variables in the form "key=value".
+	Environ() []string
+}
+
+// MapEnvironment is an environment of variables kept in the map, the map is changed by loading.
+type MapEnvironment map[string]string
+
+// LookupEnv returns the value of the variable from the map.
+func (m MapEnvironment) LookupEnv(key string) (string, bool) {
+
+	// value of the variable
+	value, ok := m[key]
+
+	return value, ok
+}
+
+// Setenv sets the value of the variable in the map.
+func (m MapEnvironment) Setenv(key, value string) error {
+
+	// set value
+	m[key] = value
+
+	return nil
+}
+
+// Environ returns the variables of the map in the form "key=value" sorted by name.
+func (m MapEnvironment) Environ() []string {
+
+	// variables list
+	environ := make([]string, 0, len(m))
+
+	// iterating over variables of the map
+	for name, value := range m {
+
+		// add variable
+		environ = append(environ, name+"="+value)
+	}
+
+	// sort variables
+	sort.Strings(environ)
+
+	return environ
+}
+
+// WithEnvironment sets the backend of environment variables instead of the environment of the process.
+func WithEnvironment(env Environment) Option {
+	return func(l *Loader) {
+
+		// set environment
+		l.env = env
+	}
+}
+
+// lookupEnv returns the value of the variable from the environment of the loader.
+func (l *Loader) lookupEnv(key string) (string, bool) {
+	return l.lookupExact(l.envKey(key))
+}
+
+// lookupExact returns the value of the variable with exactly the same name.
+func (l *Loader) lookupExact(key string) (string, bool) {
+
+	// environment of the process
+	if l.env == nil {
+		return os.LookupEnv(key)
+	}
+
+	return l.env.LookupEnv(key)
+}
+
+// environ returns the variables of the environment of the loader in the form "key=value",
+// environments that are not a ListableEnvironment can't be listed.
+func (l *Loader) environ() ([]string, error) {
+
+	switch env := l.env.(type) {
+
+	// environment of the process
+	case nil:
+		return os.Environ(), nil
+
+	// environment whose variables can be listed
+	case ListableEnvironment:
+		return env.Environ(), nil
+	}
+
+	return nil, fmt.Errorf("variables of the environment %T can't be listed, it doesn't implement Environ", l.env)
+}
+
+// setenv sets the value of the variable in the environment of the loader.
+func (l *Loader) setenv(key, value string) error {
+
+	// name of the existing variable
+	key = l.envKey(key)
+
+	// environment of the process
+	if l.env == nil {
+		return os.Setenv(key, value)
+	}
+
+	return l.env.Setenv(key, value)
+}
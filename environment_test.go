@@ -0,0 +1,102 @@
+package envfile
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestMapEnvironment tests loading into an environment kept in the map.
+func TestMapEnvironment(t *testing.T) {
+
+	// file content
+	filename := createFile(t, `
+export ENVFILE_MAP_URL = http://{ ENVFILE_MAP_HOST }:{ ENVFILE_MAP_PORT }
+export ENVFILE_MAP_PORT = 8080
+export ENVFILE_MAP_NAME = value
+`)
+
+	// environment with a variable referenced by the file and a variable that is not overloaded
+	env := MapEnvironment{"ENVFILE_MAP_HOST": "localhost", "ENVFILE_MAP_NAME": "other"}
+
+	// load file into the map
+	if err := NewLoader(WithEnvironment(env)).Load(filename); err != nil {
+		t.Fatalf("error loading file: %v", err)
+	}
+
+	// expected variables
+	expected := map[string]string{
+		"ENVFILE_MAP_HOST": "localhost",
+		"ENVFILE_MAP_PORT": "8080",
+		"ENVFILE_MAP_URL":  "http://localhost:8080",
+		"ENVFILE_MAP_NAME": "other",
+	}
+
+	// iterating over expected variables
+	for key, value := range expected {
+
+		// variable of the map
+		if env[key] != value {
+			t.Errorf("expected %s = %s, got %s", key, value, env[key])
+		}
+
+		// environment of the process is not changed
+		if _, ok := os.LookupEnv(key); ok {
+			t.Errorf("%s is set in the environment of the process", key)
+		}
+	}
+}
+
+// lookupEnvironment is an environment whose variables can't be listed.
+type lookupEnvironment struct {
+
+	// variables
+	vars MapEnvironment
+}
+
+// LookupEnv returns the value of the variable.
+func (e lookupEnvironment) LookupEnv(key string) (string, bool) {
+	return e.vars.LookupEnv(key)
+}
+
+// Setenv sets the value of the variable.
+func (e lookupEnvironment) Setenv(key, value string) error {
+	return e.vars.Setenv(key, value)
+}
+
+// listedEnvironment is a custom environment whose variables can be listed.
+type listedEnvironment struct {
+	lookupEnvironment
+}
+
+// Environ returns the variables.
+func (e listedEnvironment) Environ() []string {
+	return e.vars.Environ()
+}
+
+// TestListableEnvironment tests listing of variables of custom environments.
+func TestListableEnvironment(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "export ENVFILE_LISTED_PORT = 8080\n")
+
+	// environment that can't be listed
+	env := lookupEnvironment{MapEnvironment{"ENVFILE_LISTED_HOST": "localhost"}}
+
+	// environment can't be applied
+	if _, err := NewLoader(WithEnvironment(env)).ApplyEnviron(filename); err == nil || !strings.Contains(err.Error(), "can't be listed") {
+		t.Errorf("expected error of the environment that can't be listed, got %v", err)
+	}
+
+	// environment is applied with the variables it lists
+	environ, err := NewLoader(WithEnvironment(listedEnvironment{env})).ApplyEnviron(filename)
+	if err != nil {
+		t.Fatalf("error applying environment: %v", err)
+	}
+
+	// variables are different from expected
+	if expected := []string{"ENVFILE_LISTED_HOST=localhost", "ENVFILE_LISTED_PORT=8080"}; !reflect.DeepEqual(environ, expected) {
+		t.Errorf("expected %v, got %v", expected, environ)
+	}
+}
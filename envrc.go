@@ -0,0 +1,234 @@
+package envfile
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// envrcLiteral escapes text of a shell word for the default dialect.
+var envrcLiteral = strings.NewReplacer(`\`, `\\`, "{", "{{", "}", "}}", "\n", `\n`, "\t", `\t`)
+
+// envrcSources are direnv commands reading other files.
+var envrcSources = map[string]bool{
+	"source_env":           true,
+	"source_env_if_exists": true,
+	"source_up":            true,
+	"dotenv":               true,
+	"dotenv_if_exists":     true,
+}
+
+// EncodeEnvrc writes payloads as a direnv .envrc: exported and overloaded keys are exported,
+// conditional keys are exported only if they are not set yet, other keys stay shell variables.
+// Values are single-quoted, so the shell takes them as they are.
+func EncodeEnvrc(payloads []Payload, w io.Writer) error {
+	return parser.EncodeEnvrc(payloads, w)
+}
+
+// ConvertEnvrc converts a direnv .envrc into an env file of the default dialect:
+// KEY=value and export KEY=value lines become keys, $VAR and ${VAR} become { VAR } references,
+// comments are kept. Files read with source_env, source_up or dotenv are written as comments
+// and returned in order, so they can be loaded along with the converted file.
+// Other shell commands can't be converted and are reported as errors.
+func ConvertEnvrc(r io.Reader, w io.Writer) ([]string, error) {
+
+	// files read by the .envrc
+	var sources []string
+
+	// buffered writer
+	writer := bufio.NewWriter(w)
+
+	// line by line reading
+	scanner := bufio.NewScanner(r)
+
+	// split lines regardless of the line ending
+	scanner.Split(parser.ScanLines)
+
+	// iterate through the lines
+	for line := 1; scanner.Scan(); line++ {
+
+		// current line without spaces around
+		current := strings.TrimSpace(scanner.Text())
+
+		// blank line or comment
+		if len(current) == 0 || strings.HasPrefix(current, "#") {
+			fmt.Fprintln(writer, current)
+			continue
+		}
+
+		// command name
+		command := strings.FieldsFunc(current, unicode.IsSpace)[0]
+
+		// command reading another file
+		if envrcSources[command] {
+
+			// arguments of the command
+			words, err := SplitCommand(current)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", line, err)
+			}
+
+			// add files to list
+			sources = append(sources, words[1:]...)
+
+			// keep command as a comment
+			fmt.Fprintf(writer, "# %s\n", current)
+
+			continue
+		}
+
+		// export directive
+		export := command == "export"
+		if export {
+			current = strings.TrimLeftFunc(current[len(command):], unicode.IsSpace)
+		}
+
+		// position of the equal sign
+		position := strings.IndexByte(current, '=')
+
+		// key name
+		var key string
+		if position > 0 {
+			key = current[:position]
+		}
+
+		// line is not an assignment
+		if len(key) == 0 || !parser.ValidKey(key) {
+			return nil, fmt.Errorf("line %d: unsupported command '%s'", line, command)
+		}
+
+		// convert value
+		value, err := envrcValue(current[position+1:])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", line, err)
+		}
+
+		// exported key
+		if export {
+			fmt.Fprintf(writer, "export %s = %s\n", key, value)
+		} else {
+			fmt.Fprintf(writer, "%s = %s\n", key, value)
+		}
+	}
+
+	// reading error
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return sources, writer.Flush()
+}
+
+// envrcValue converts the shell word into a value of the default dialect,
+// only a comment may follow the word.
+func envrcValue(word string) (string, error) {
+
+	// converted value
+	var builder strings.Builder
+
+	// inside double quotes
+	var quoted bool
+
+	// iteration over word
+	for i := 0; i < len(word); i++ {
+
+		// current character
+		c := word[i]
+
+		switch {
+
+		// end of word
+		case !quoted && unicode.IsSpace(rune(c)):
+
+			// rest of the line
+			rest := strings.TrimSpace(word[i:])
+
+			// anything but a comment follows the word
+			if len(rest) > 0 && !strings.HasPrefix(rest, "#") {
+				return "", fmt.Errorf("unsupported text after the value '%s'", rest)
+			}
+
+			return envrcTrimmed(builder.String())
+
+		// single-quoted text
+		case !quoted && c == '\'':
+
+			// closing quote
+			end := strings.IndexByte(word[i+1:], '\'')
+			if end < 0 {
+				return "", errors.New("unterminated single quote")
+			}
+
+			// add quoted text
+			builder.WriteString(envrcLiteral.Replace(word[i+1 : i+1+end]))
+
+			// skip quoted text
+			i += end + 1
+
+		// double quote
+		case c == '"':
+			quoted = !quoted
+
+		// escaped character
+		case c == '\\' && i+1 < len(word):
+
+			// backslash is kept within double quotes unless it escapes a special character
+			if quoted && strings.IndexByte("\\\"$`", word[i+1]) < 0 {
+				builder.WriteString(envrcLiteral.Replace(word[i : i+2]))
+			} else {
+				builder.WriteString(envrcLiteral.Replace(word[i+1 : i+2]))
+			}
+
+			// skip escaped character
+			i++
+
+		// variable
+		case c == '$' && i+1 < len(word) && (word[i+1] == '{' || parser.IsWordByte(word[i+1])):
+
+			// variable name and position after the reference
+			variable, fallback, end := parser.ParseDotenvReference(word, i)
+
+			// expansion other than a plain reference
+			if len(variable) == 0 || len(fallback) > 0 || (word[i+1] == '{' && word[end-1] != '}') {
+				return "", fmt.Errorf("unsupported expansion '%s'", word[i:])
+			}
+
+			// add reference
+			builder.WriteString("{ " + variable + " }")
+
+			// skip reference
+			i = end - 1
+
+		// command substitution
+		case c == '`' || (c == '$' && i+1 < len(word) && word[i+1] == '('):
+			return "", fmt.Errorf("unsupported command substitution '%s'", word[i:])
+
+		// any
+		default:
+			builder.WriteString(envrcLiteral.Replace(word[i : i+1]))
+		}
+	}
+
+	// closing double quote is missing
+	if quoted {
+		return "", errors.New("unterminated double quote")
+	}
+
+	return envrcTrimmed(builder.String())
+}
+
+// envrcTrimmed returns the value if it has no spaces around, the default dialect trims them.
+func envrcTrimmed(value string) (string, error) {
+
+	// spaces around the value would be lost
+	if strings.TrimSpace(value) != value {
+		return "", errors.New("spaces around the value can't be kept")
+	}
+
+	return value, nil
+}
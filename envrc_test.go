@@ -0,0 +1,123 @@
+package envfile
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestEncodeEnvrc tests the direnv .envrc output.
+func TestEncodeEnvrc(t *testing.T) {
+
+	// payloads
+	payloads := []Payload{
+		{Key: "LOCAL", Value: "it's local"},
+		{Key: "HOST", Value: "localhost", Export: true},
+		{Key: "PORT", Value: "80", Export: true, Conditional: true},
+	}
+
+	// .envrc output
+	var buffer bytes.Buffer
+
+	// encode .envrc
+	if err := EncodeEnvrc(payloads, &buffer); err != nil {
+		t.Fatalf("error encoding .envrc: %v", err)
+	}
+
+	// expected .envrc
+	expected := `LOCAL='it'\''s local'
+export HOST='localhost'
+[ -n "${PORT+x}" ] || export PORT='80'
+`
+
+	// .envrc is different from expected
+	if buffer.String() != expected {
+		t.Errorf("expected .envrc:\n%s\ngot:\n%s", expected, buffer.String())
+	}
+}
+
+// TestConvertEnvrc tests conversion of a direnv .envrc into an env file.
+func TestConvertEnvrc(t *testing.T) {
+
+	// .envrc content
+	envrc := `# shared settings
+source_env ../.envrc
+dotenv .env.local
+
+export HOST=localhost
+PORT="8080" # comment
+export URL="http://$HOST:${PORT}/{path}"
+export QUOTED='it''s "$HOST" \n'
+export ESCAPED=a\ b\$HOST
+`
+
+	// converted env file
+	var buffer bytes.Buffer
+
+	// convert .envrc
+	sources, err := ConvertEnvrc(strings.NewReader(envrc), &buffer)
+	if err != nil {
+		t.Fatalf("error converting .envrc: %v", err)
+	}
+
+	// expected env file
+	expected := `# shared settings
+# source_env ../.envrc
+# dotenv .env.local
+
+export HOST = localhost
+PORT = 8080
+export URL = http://{ HOST }:{ PORT }/{{path}}
+export QUOTED = its "$HOST" \\n
+export ESCAPED = a b$HOST
+`
+
+	// env file is different from expected
+	if buffer.String() != expected {
+		t.Errorf("expected env file:\n%s\ngot:\n%s", expected, buffer.String())
+	}
+
+	// sourced files are different from expected
+	if !reflect.DeepEqual(sources, []string{"../.envrc", ".env.local"}) {
+		t.Errorf("unexpected sources: %v", sources)
+	}
+
+	// parse converted env file
+	payloads, err := Parse(createFile(t, buffer.String()))
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// expected values
+	values := map[string]string{
+		"URL":     "http://localhost:8080/{path}",
+		"QUOTED":  `its "$HOST" \n`,
+		"ESCAPED": "a b$HOST",
+	}
+
+	// iterating over expected values
+	for key, value := range values {
+
+		// value is different from expected
+		if payload, _ := payloads.Lookup(key); payload.Value != value {
+			t.Errorf("expected %s to be %s, got %s", key, value, payload.Value)
+		}
+	}
+
+	// iterating over unsupported lines
+	for _, line := range []string{
+		"PATH_add bin",
+		"export A=1 B=2",
+		"export A=${B:-default}",
+		"export A=$(date)",
+		"export A='open",
+		"export A=' spaces '",
+	} {
+
+		// unsupported line is converted
+		if _, err := ConvertEnvrc(strings.NewReader(line), &buffer); err == nil {
+			t.Errorf("%s: line is unsupported but convert didn't return an error", line)
+		}
+	}
+}
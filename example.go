@@ -0,0 +1,161 @@
+package envfile
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// CheckExample verifies that every key of the real file exists in the example file, ignoring values.
+func CheckExample(real, example string) error {
+	return checkExample(real, example, false)
+}
+
+// CheckExampleStrict verifies that the real and example files have the same keys, ignoring values.
+func CheckExampleStrict(real, example string) error {
+	return checkExample(real, example, true)
+}
+
+// checkExample compares keys of the real and example files.
+func checkExample(real, example string, strict bool) error {
+
+	// loader that leaves values as they are written
+	loader := NewLoader()
+
+	// make sure the real file is valid
+	if _, err := loader.read(real); err != nil {
+		return err
+	}
+
+	// make sure the example file is valid
+	if _, err := loader.read(example); err != nil {
+		return err
+	}
+
+	// keys of the real file
+	realPayloads, err := loader.exampleKeys(real, make(map[string]bool))
+	if err != nil {
+		return err
+	}
+
+	// keys of the example file
+	examplePayloads, err := loader.exampleKeys(example, make(map[string]bool))
+	if err != nil {
+		return err
+	}
+
+	// keys of the real file missing in the example file
+	if missing := missingKeys(realPayloads, examplePayloads); len(missing) > 0 {
+		return fmt.Errorf("[%s] keys missing in '%s': %s", real, example, joinKeys(missing))
+	}
+
+	// keys of the example file missing in the real file
+	if missing := missingKeys(examplePayloads, realPayloads); strict && len(missing) > 0 {
+		return fmt.Errorf("[%s] keys missing in '%s': %s", example, real, joinKeys(missing))
+	}
+
+	return nil
+}
+
+// exampleKeys returns the keys written in the file and in the files it includes, in every section and #if
+// branch whatever profile or platform is active, list items by the name of the list. Files already
+// seen are skipped.
+func (l *Loader) exampleKeys(filename string, seen map[string]bool) ([]Payload, error) {
+
+	// file is already seen
+	if seen[filepath.Clean(filename)] {
+		return nil, nil
+	}
+
+	// remember file
+	seen[filepath.Clean(filename)] = true
+
+	// document of the file
+	doc, err := l.ParseDocument(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// keys list
+	var payloads []Payload
+
+	// iterating over document lines
+	for _, node := range doc.Nodes {
+
+		switch node.Kind {
+
+		// keys of the included file
+		case NodeInclude:
+
+			// keys of the included file
+			included, err := l.exampleKeys(doc.IncludedPath(node), seen)
+			if err != nil {
+				return nil, err
+			}
+
+			// add keys to list
+			payloads = append(payloads, included...)
+
+		// line with a key and a value
+		case NodeEntry:
+
+			// key name, list items by the name of the list
+			key := strings.TrimSpace(strings.TrimSuffix(node.Key, "[]"))
+
+			// key written more than once, e.g. in several sections, is listed once
+			if slices.ContainsFunc(payloads, func(payload Payload) bool { return payload.Key == key }) {
+				continue
+			}
+
+			// add key to list
+			payloads = append(payloads, Payload{File: filename, Line: node.Line, Key: key})
+		}
+	}
+
+	return payloads, nil
+}
+
+// missingKeys returns payloads whose keys are missing in the other payload list.
+func missingKeys(payloads, other []Payload) []Payload {
+
+	// keys of the other payload list
+	keys := make(map[string]bool)
+
+	// iterating over the other payload list
+	for _, payload := range other {
+
+		// add key to list
+		keys[payload.Key] = true
+	}
+
+	// missing payloads
+	var missing []Payload
+
+	// iterating over a list of payloads
+	for _, payload := range payloads {
+
+		// key is missing in the other payload list
+		if !keys[payload.Key] {
+			missing = append(missing, payload)
+		}
+	}
+
+	return missing
+}
+
+// joinKeys joins key names of payloads with commas.
+func joinKeys(payloads []Payload) string {
+
+	// key names
+	keys := make([]string, len(payloads))
+
+	// iterating over a list of payloads
+	for i, payload := range payloads {
+
+		// add key name
+		keys[i] = payload.Key
+	}
+
+	return strings.Join(keys, ", ")
+}
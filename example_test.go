@@ -0,0 +1,54 @@
+package envfile
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestCheckExample tests verification of keys against the example file.
+func TestCheckExample(t *testing.T) {
+
+	// real file
+	real := createFile(t, "export KEY_1 = value\nexport KEY_2 = { MISSING }\n")
+
+	// example file with the same keys and an extra one
+	example := createFile(t, "export KEY_1 =\nexport KEY_2 =\nexport KEY_3 =\n")
+
+	// all keys of the real file are documented
+	if err := CheckExample(real, example); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// extra key in the example file
+	if err := CheckExampleStrict(real, example); err == nil {
+		t.Error("example file has an extra key but strict check didn't return an error")
+	}
+
+	// undocumented keys of the real file
+	if err := CheckExample(example, real); err == nil {
+		t.Error("real file has an undocumented key but check didn't return an error")
+	}
+}
+
+// TestCheckExampleSections tests that keys of every section and #if branch are compared.
+func TestCheckExampleSections(t *testing.T) {
+
+	// real file with keys of sections and branches that are not active
+	real := createFile(t, "HOST = localhost\n[prod]\nHOST = db\nREPLICA = replica\n#if ${ENVFILE_EXAMPLE_UNSET}\nDEBUG = true\n#endif\n")
+
+	// example file with the same keys
+	example := createFile(t, "HOST =\n#if ${ENVFILE_EXAMPLE_UNSET}\nDEBUG =\n#endif\n[prod]\nREPLICA =\n")
+
+	// keys of sections and branches are documented
+	if err := CheckExampleStrict(real, example); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// example file without the key of the section and the key of the branch
+	example = createFile(t, "HOST =\n")
+
+	// undocumented keys of the section and the branch
+	if err := CheckExample(real, example); err == nil || !strings.Contains(err.Error(), "REPLICA, DEBUG") {
+		t.Errorf("expected keys REPLICA and DEBUG to be missing, got %v", err)
+	}
+}
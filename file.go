@@ -0,0 +1,185 @@
+package envfile
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// File is a file with environment variables opened for editing: keys are changed in place
+// and comments, blank lines and the order of lines are kept when it is saved.
+type File struct {
+
+	// document of the file
+	doc *Document
+}
+
+// Open opens the file with environment variables for editing.
+func Open(filename string) (*File, error) {
+	return NewLoader().Open(filename)
+}
+
+// Open opens the file with environment variables for editing, lines are parsed with the syntax of the loader.
+func (l *Loader) Open(filename string) (*File, error) {
+
+	// parse document
+	doc, err := l.ParseDocument(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{doc: doc}, nil
+}
+
+// Get returns the value of the key as it is written, references are not resolved.
+// Multi-line values are returned without the delimiter lines.
+func (f *File) Get(key string) (string, bool) {
+
+	// line of the key
+	i := f.index(key)
+	if i < 0 {
+		return "", false
+	}
+
+	// multi-line value
+	if len(f.doc.Nodes[i].Heredoc) > 0 {
+		return f.doc.HeredocValue(i), true
+	}
+
+	return f.doc.Nodes[i].Value, true
+}
+
+// Set changes the value of the key keeping the rest of its line, the value is written as it is,
+// so it can reference other keys. A new key is added as an exported one at the end of the file.
+func (f *File) Set(key, value string) error {
+
+	// key is not valid
+	if !parser.ValidKey(key) {
+		return fmt.Errorf("key '%s' is not valid", key)
+	}
+
+	// value can't be written on one line
+	if strings.ContainsAny(value, "\r\n") || value != strings.TrimSpace(value) {
+		return fmt.Errorf("value of key '%s' has line breaks or leading or trailing spaces", key)
+	}
+
+	// line of the key
+	i := f.index(key)
+
+	// key is defined
+	if i >= 0 {
+
+		// lines of the multi-line value are replaced by the value
+		f.removeContinuation(i)
+
+		// current node
+		node := f.doc.Nodes[i]
+
+		// text before the value, separated from it by a space
+		before := node.Text[:node.ValueSpan.Start]
+		if len(node.Value) == 0 && len(value) > 0 && !strings.HasSuffix(before, " ") {
+			before += " "
+		}
+
+		// parse changed line
+		f.doc.Nodes[i] = parser.ParseLine(f.doc.Syntax, node.Line, before+value+node.Text[node.ValueSpan.End:])
+
+		return nil
+	}
+
+	// add line of the key
+	f.doc.Append(strings.TrimSpace("export " + key + " = " + value))
+
+	return nil
+}
+
+// Unset removes the lines of the key and reports whether the key was defined.
+func (f *File) Unset(key string) bool {
+
+	// remaining lines
+	nodes := f.doc.Nodes[:0]
+
+	// line of the key is removed, with the lines of its multi-line value
+	removing := false
+
+	// iterating over a list of nodes
+	for _, node := range f.doc.Nodes {
+
+		// line of the key
+		if node.Kind == NodeEntry && node.Key == key {
+			removing = true
+			continue
+		}
+
+		// line of the multi-line value of the removed key
+		if removing && node.Kind == NodeContinuation {
+			continue
+		}
+		removing = false
+
+		// renumber line
+		node.Line = len(nodes) + 1
+
+		// keep line
+		nodes = append(nodes, node)
+	}
+
+	// key was defined
+	removed := len(nodes) < len(f.doc.Nodes)
+
+	// update lines
+	f.doc.Nodes = nodes
+
+	return removed
+}
+
+// Save writes the file back, lines that were not changed are written as they were read.
+func (f *File) Save() error {
+
+	// mode of the file
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(f.doc.Name); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	return ioutil.WriteFile(f.doc.Name, f.doc.Bytes(), mode)
+}
+
+// removeContinuation removes the lines of the multi-line value of the key on the line.
+func (f *File) removeContinuation(i int) {
+
+	// end of the continuation lines
+	end := i + 1
+	for end < len(f.doc.Nodes) && f.doc.Nodes[end].Kind == NodeContinuation {
+		end++
+	}
+
+	// key has no multi-line value
+	if end == i+1 {
+		return
+	}
+
+	// remove lines and renumber the following ones
+	f.doc.Nodes = append(f.doc.Nodes[:i+1], f.doc.Nodes[end:]...)
+	for j := i + 1; j < len(f.doc.Nodes); j++ {
+		f.doc.Nodes[j].Line = j + 1
+	}
+}
+
+// index returns the position of the first line of the key, -1 if it is not defined.
+func (f *File) index(key string) int {
+
+	// iterating over a list of nodes
+	for i, node := range f.doc.Nodes {
+
+		// line of the key
+		if node.Kind == NodeEntry && node.Key == key {
+			return i
+		}
+	}
+
+	return -1
+}
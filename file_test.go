@@ -0,0 +1,68 @@
+package envfile
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+// TestFile tests editing of the file keeping comments and layout.
+func TestFile(t *testing.T) {
+
+	// file with comments and blank lines
+	filename := createFile(t, "# database\nexport DB_HOST = localhost  \n\n# removed\nDB_DEBUG = true\nDB_NAME =\n")
+
+	// open file
+	file, err := Open(filename)
+	if err != nil {
+		t.Fatalf("error opening env file: %v", err)
+	}
+
+	// value as it is written
+	if value, ok := file.Get("DB_HOST"); !ok || value != "localhost" {
+		t.Errorf("expected DB_HOST to be localhost, got %q", value)
+	}
+
+	// change values
+	if err := file.Set("DB_HOST", "db.internal"); err != nil {
+		t.Fatalf("error setting DB_HOST: %v", err)
+	}
+	if err := file.Set("DB_NAME", "app"); err != nil {
+		t.Fatalf("error setting DB_NAME: %v", err)
+	}
+	if err := file.Set("DB_URL", "postgres://{ DB_HOST }/{ DB_NAME }"); err != nil {
+		t.Fatalf("error setting DB_URL: %v", err)
+	}
+
+	// remove key
+	if !file.Unset("DB_DEBUG") || file.Unset("DB_MISSING") {
+		t.Error("expected only DB_DEBUG to be removed")
+	}
+
+	// value with a line break
+	if err := file.Set("DB_HOST", "a\nb"); err == nil {
+		t.Error("value has a line break but set didn't return an error")
+	}
+
+	// save file
+	if err := file.Save(); err != nil {
+		t.Fatalf("error saving env file: %v", err)
+	}
+
+	// content of the file
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("error reading env file: %v", err)
+	}
+
+	// comments, blank lines and spacing are kept
+	if expected := "# database\nexport DB_HOST = db.internal  \n\n# removed\nDB_NAME = app\nexport DB_URL = postgres://{ DB_HOST }/{ DB_NAME }\n"; string(content) != expected {
+		t.Errorf("expected %q, got %q", expected, content)
+	}
+
+	// saved file is valid
+	if payloads, err := Parse(filename); err != nil {
+		t.Errorf("error parsing saved file: %v", err)
+	} else if payload, _ := payloads.Lookup("DB_URL"); payload.Value != "postgres://db.internal/app" {
+		t.Errorf("expected DB_URL to be postgres://db.internal/app, got %s", payload.Value)
+	}
+}
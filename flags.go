@@ -0,0 +1,157 @@
+package envfile
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FlagSet is a set of feature flags: boolean values of keys with a common prefix, e.g. FLAG_SEARCH.
+// Flags are named without the prefix.
+type FlagSet struct {
+
+	// prefix of keys
+	prefix string
+
+	// current values
+	values Values
+
+	// functions called when flags change
+	callbacks []func(name string, enabled bool)
+
+	// values and callbacks access synchronization
+	mu sync.RWMutex
+}
+
+// Flags returns the feature flags of keys starting with the prefix.
+func (v Values) Flags(prefix string) *FlagSet {
+	return &FlagSet{prefix: prefix, values: v}
+}
+
+// Enabled reports whether the flag is on: true, 1, yes and on are enabled, false, 0, no and off
+// are disabled, case-insensitively. The fallback is returned if the flag is missing or has another value.
+func (f *FlagSet) Enabled(name string, fallback bool) bool {
+
+	// lock values
+	f.mu.RLock()
+
+	// deferred unlock of values
+	defer f.mu.RUnlock()
+
+	// value of the flag
+	enabled, ok := f.lookup(f.values, name)
+	if !ok {
+		return fallback
+	}
+
+	return enabled
+}
+
+// OnChange adds the function called by Update for every flag that is changed,
+// removed and invalid flags are reported as disabled.
+func (f *FlagSet) OnChange(callback func(name string, enabled bool)) {
+
+	// lock callbacks
+	f.mu.Lock()
+
+	// deferred unlock of callbacks
+	defer f.mu.Unlock()
+
+	// add callback
+	f.callbacks = append(f.callbacks, callback)
+}
+
+// Update replaces the values of the flags, e.g. after the files are changed and parsed again,
+// and calls the OnChange functions for changed flags in order of the new values.
+func (f *FlagSet) Update(values Values) {
+
+	// lock values
+	f.mu.Lock()
+
+	// previous values
+	previous := f.values
+
+	// update values
+	f.values = values
+
+	// functions to call
+	callbacks := f.callbacks
+
+	// unlock values
+	f.mu.Unlock()
+
+	// names of changed flags
+	var changed []string
+
+	// flags of the new values, then removed ones
+	for _, set := range []Values{values, previous} {
+
+		// iterating over keys with the prefix
+		for key := range set.WithPrefix(f.prefix) {
+
+			// flag name
+			name := strings.TrimPrefix(key, f.prefix)
+
+			// flag is already reported
+			if slices.Contains(changed, name) {
+				continue
+			}
+
+			// states of the flag
+			before, existed := f.lookup(previous, name)
+			after, exists := f.lookup(values, name)
+
+			// flag is changed
+			if before != after || existed != exists {
+				changed = append(changed, name)
+			}
+		}
+	}
+
+	// iterating over changed flags
+	for _, name := range changed {
+
+		// new state of the flag
+		enabled, _ := f.lookup(values, name)
+
+		// iterating over callbacks
+		for _, callback := range callbacks {
+
+			// report change
+			callback(name, enabled)
+		}
+	}
+}
+
+// lookup returns the state of the flag in the values and whether it is valid.
+func (f *FlagSet) lookup(values Values, name string) (bool, bool) {
+
+	// value of the flag
+	value, ok := values.Lookup(f.prefix + name)
+	if !ok {
+		return false, false
+	}
+
+	return parseFlag(value)
+}
+
+// parseFlag returns the state of the flag value and whether it is valid.
+func parseFlag(value string) (bool, bool) {
+
+	switch strings.ToLower(strings.TrimSpace(value)) {
+
+	// enabled
+	case "yes", "on":
+		return true, true
+
+	// disabled
+	case "no", "off":
+		return false, true
+	}
+
+	// boolean literal
+	enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+
+	return enabled, err == nil
+}
@@ -0,0 +1,85 @@
+package envfile
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+// TestFlags tests feature flags and their change callbacks.
+func TestFlags(t *testing.T) {
+
+	// file content
+	filename := createFile(t, `
+export FLAG_SEARCH = on
+export FLAG_BETA = false
+export FLAG_BROKEN = maybe
+export FLAG_OLD = 1
+`)
+
+	// parse values
+	values, err := ParseValues(filename)
+	if err != nil {
+		t.Fatalf("error parsing values: %v", err)
+	}
+
+	// feature flags
+	flags := values.Flags("FLAG_")
+
+	// expected states with fallbacks
+	expected := map[string][2]bool{
+		"SEARCH":  {false, true},
+		"BETA":    {true, false},
+		"BROKEN":  {true, true},
+		"MISSING": {false, false},
+	}
+
+	// iterating over flags
+	for name, states := range expected {
+
+		// state is different from expected
+		if enabled := flags.Enabled(name, states[0]); enabled != states[1] {
+			t.Errorf("expected %s to be %v, got %v", name, states[1], enabled)
+		}
+	}
+
+	// changes reported by callbacks
+	var changes []string
+
+	// callback
+	flags.OnChange(func(name string, enabled bool) {
+
+		// state of the flag
+		state := "off"
+		if enabled {
+			state = "on"
+		}
+
+		// add change
+		changes = append(changes, name+"="+state)
+	})
+
+	// change file
+	if err := ioutil.WriteFile(filename, []byte("export FLAG_SEARCH = true\nexport FLAG_BETA = yes\nexport FLAG_NEW = on\nexport FLAG_BROKEN = maybe\n"), 0644); err != nil {
+		t.Fatalf("error writing env file: %v", err)
+	}
+
+	// parse values again
+	values, err = ParseValues(filename)
+	if err != nil {
+		t.Fatalf("error parsing values: %v", err)
+	}
+
+	// update flags
+	flags.Update(values)
+
+	// changes are different from expected
+	if expected := []string{"BETA=on", "NEW=on", "OLD=off"}; !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected changes %v, got %v", expected, changes)
+	}
+
+	// new state of the flag
+	if !flags.Enabled("BETA", false) {
+		t.Error("expected BETA to be enabled after update")
+	}
+}
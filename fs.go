@@ -0,0 +1,89 @@
+package envfile
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"io/ioutil"
+)
+
+// LoadFS will load files with environment variables from the file system, e.g. embed.FS or fstest.MapFS,
+// for this process. The .envfile is loaded if no file names are given.
+func LoadFS(fsys fs.FS, filenames ...string) error {
+	return std.LoadFS(fsys, filenames...)
+}
+
+// ParseFS parses file with environment variables from the file system, e.g. embed.FS or fstest.MapFS.
+func ParseFS(fsys fs.FS, name string) (Payloads, error) {
+	return NewLoader().ParseFS(fsys, name)
+}
+
+// ParseFS parses file with environment variables from the file system, e.g. embed.FS or fstest.MapFS.
+// Included files are read from the same file system, values with the file: prefix are still read from the disk.
+func (l *Loader) ParseFS(fsys fs.FS, name string) (Payloads, error) {
+
+	// open file with environment variables
+	file, err := l.openFS(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// deferred file close
+	defer file.Close()
+
+	// read document
+	doc, err := l.readDocument(name, file)
+	if err != nil {
+		return nil, err
+	}
+
+	// included files are read from the same file system
+	doc.FS = fsys
+
+	// read payloads
+	payloads, err := l.payloads(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.resolve(name, payloads)
+}
+
+// openFS opens the file of the file system, verifying its signature, read from the same file system,
+// if verification is enabled: the verified content is the content that is parsed.
+func (l *Loader) openFS(fsys fs.FS, name string) (io.ReadCloser, error) {
+
+	// verification is disabled
+	if l.publicKey == nil {
+		return fsys.Open(name)
+	}
+
+	// read content
+	content, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// verify signature of the content
+	if err := l.verifySignature(name, content, func(name string) ([]byte, error) { return fs.ReadFile(fsys, name) }); err != nil {
+		return nil, err
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(content)), nil
+}
+
+// LoadFS will load files with environment variables from the file system for this process, as Load does
+// for the files of the disk. The files are not tracked by Changed.
+func (l *Loader) LoadFS(fsys fs.FS, filenames ...string) error {
+
+	// file name list is empty
+	if len(filenames) == 0 {
+
+		// add the default filename to the list
+		filenames = append(filenames, defaultFilename)
+	}
+
+	return l.load(filenames, func(filename string) (Payloads, error) {
+		return l.ParseFS(fsys, filename)
+	}, false)
+}
@@ -0,0 +1,98 @@
+package envfile
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+// TestParseFS tests file parsing from a file system.
+func TestParseFS(t *testing.T) {
+
+	// file system
+	fsys := fstest.MapFS{
+		"config/.envfile": {Data: []byte("KEY_1 = value\nKEY_2 = { KEY_1 }\n")},
+	}
+
+	// parse file
+	payloads, err := ParseFS(fsys, "config/.envfile")
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// value is different from expected
+	if payload, _ := payloads.Lookup("KEY_2"); payload.Value != "value" {
+		t.Errorf("expected KEY_2 to be value, got %s", payload.Value)
+	}
+
+	// missing file
+	if _, err := ParseFS(fsys, "missing/.envfile"); err == nil {
+		t.Error("file doesn't exist but parse didn't return an error")
+	}
+}
+
+// TestLoadFS tests loading of files from a file system.
+func TestLoadFS(t *testing.T) {
+
+	// file system
+	fsys := fstest.MapFS{
+		".envfile":            {Data: []byte("export LOAD_FS_KEY = default\n")},
+		"config/prod.envfile": {Data: []byte("overload LOAD_FS_KEY = prod\n")},
+	}
+
+	// deferred unset of the variable
+	defer os.Unsetenv("LOAD_FS_KEY")
+
+	// load default file
+	if err := LoadFS(fsys); err != nil {
+		t.Fatalf("error loading env file: %v", err)
+	}
+
+	// value of the default file
+	if value := os.Getenv("LOAD_FS_KEY"); value != "default" {
+		t.Errorf("expected LOAD_FS_KEY to be default, got %s", value)
+	}
+
+	// load overloading file
+	if err := LoadFS(fsys, "config/prod.envfile"); err != nil {
+		t.Fatalf("error loading env file: %v", err)
+	}
+
+	// overloaded value
+	if value := os.Getenv("LOAD_FS_KEY"); value != "prod" {
+		t.Errorf("expected LOAD_FS_KEY to be prod, got %s", value)
+	}
+}
+
+// TestParseFSInclude tests that included files are read from the file system of the parsed file.
+func TestParseFSInclude(t *testing.T) {
+
+	// file including files of the file system, and a file of the disk
+	fsys := fstest.MapFS{
+		"config/.envfile":            {Data: []byte("include shared/base.envfile\nURL = http://{ HOST }:{ PORT }\n")},
+		"config/shared/base.envfile": {Data: []byte("HOST = localhost\ninclude ../port.envfile\n")},
+		"config/port.envfile":        {Data: []byte("PORT = 8080\n")},
+		"disk.envfile":               {Data: []byte("include " + createFile(t, "DISK = value\n") + "\n")},
+	}
+
+	// parse file
+	payloads, err := ParseFS(fsys, "config/.envfile")
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// value is different from expected
+	if payload, _ := payloads.Lookup("URL"); payload.Value != "http://localhost:8080" {
+		t.Errorf("expected URL to be http://localhost:8080, got %s", payload.Value)
+	}
+
+	// key is reported with the file of the file system
+	if payload, _ := payloads.Lookup("PORT"); payload.File != "config/port.envfile" {
+		t.Errorf("expected PORT of config/port.envfile, got %s", payload.File)
+	}
+
+	// file of the disk is not read
+	if _, err := ParseFS(fsys, "disk.envfile"); err == nil {
+		t.Error("file of the disk is included from the file system")
+	}
+}
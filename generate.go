@@ -0,0 +1,112 @@
+package envfile
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+)
+
+// Generator generates example files from real ones.
+type Generator struct {
+
+	// value written in place of removed values, empty by default
+	Placeholder string
+
+	// keep values of keys that don't hold secrets
+	KeepValues bool
+
+	// keys holding secrets in addition to the ones recognized by name
+	Sensitive []string
+}
+
+// GenerateExample writes the example file with blank values for the real file.
+func GenerateExample(real, example string) error {
+	return (&Generator{}).Generate(real, example)
+}
+
+// Generate writes the example file for the real file, preserving comments and order of keys.
+func (g *Generator) Generate(real, example string) error {
+
+	// make sure the real file is valid
+	if _, err := NewLoader().read(real); err != nil {
+		return err
+	}
+
+	// read lines of file
+	lines, err := readLines(real)
+	if err != nil {
+		return err
+	}
+
+	// document of the file, every key is blanked whatever section or branch it is written in
+	doc, err := NewLoader().ParseDocument(real)
+	if err != nil {
+		return err
+	}
+
+	// lines of removed multi-line values by line number
+	removed := make(map[int]bool)
+
+	// iterating over document lines, keys of included files are written in the example of that file
+	for i, node := range doc.Nodes {
+
+		// not a line with a key and a value
+		if node.Kind != NodeEntry {
+			continue
+		}
+
+		// current line
+		current := lines[node.Line-1]
+
+		// position of the equal sign
+		position := strings.Index(current, "=") + 1
+
+		// keep spaces after the equal sign
+		position += len(current[position:]) - len(strings.TrimLeft(current[position:], " \t"))
+
+		// keep value of key that doesn't hold a secret
+		if g.KeepValues && !g.sensitive(strings.TrimSuffix(node.Key, "[]")) {
+			continue
+		}
+
+		// replace value
+		lines[node.Line-1] = current[:position] + g.Placeholder
+
+		// lines of the multi-line value, including the closing one, are removed with it
+		for j := i + 1; j < len(doc.Nodes) && doc.Nodes[j].Kind == NodeContinuation; j++ {
+			removed[doc.Nodes[j].Line] = true
+		}
+	}
+
+	// content of the example file
+	var buffer bytes.Buffer
+
+	// iterating over a list of lines
+	for i, line := range lines {
+
+		// line of a removed multi-line value
+		if removed[i+1] {
+			continue
+		}
+
+		// add line without trailing spaces
+		buffer.WriteString(strings.TrimRight(line, " \t") + "\n")
+	}
+
+	return ioutil.WriteFile(example, buffer.Bytes(), 0644)
+}
+
+// sensitive reports whether the key holds a secret.
+func (g *Generator) sensitive(key string) bool {
+
+	// iterating over a list of sensitive keys
+	for _, sensitive := range g.Sensitive {
+
+		// key is in the list of sensitive keys
+		if sensitive == key {
+			return true
+		}
+	}
+
+	return isSensitive(key)
+}
@@ -0,0 +1,204 @@
+package envfile
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// converters are custom conversions of values by target type.
+var converters sync.Map
+
+// RegisterConverter adds the conversion of values to the type T used by Get,
+// it takes precedence over the built-in ones.
+func RegisterConverter[T any](convert func(value string) (T, error)) {
+
+	// add converter
+	converters.Store(reflect.TypeOf((*T)(nil)).Elem(), func(value string) (interface{}, error) {
+		return convert(value)
+	})
+}
+
+// Get returns the value of the key converted to the type T: strings, booleans, integers,
+// floating point numbers, time.Duration, time.Time in RFC 3339, *url.URL, types implementing
+// encoding.TextUnmarshaler, slices of them written as comma-separated lists and types of
+// RegisterConverter. It fails if the key does not exist, a value that can't be converted
+// is reported as *ConversionError.
+func Get[T any](values Values, key string) (T, error) {
+
+	// converted value
+	var result T
+
+	// value of the key with its source
+	raw, source, ok := values.GetWithSource(key)
+	if !ok {
+		return result, fmt.Errorf("key '%s' is not set", key)
+	}
+
+	// converted value
+	target := reflect.ValueOf(&result).Elem()
+
+	// convert value
+	if err := convert(raw, target); err != nil {
+		return result, &ConversionError{
+			Key:    key,
+			Raw:    raw,
+			Target: target.Type().String(),
+			File:   source.File,
+			Line:   source.Line,
+			Err:    err,
+		}
+	}
+
+	return result, nil
+}
+
+// convert sets the target to the value converted to its type.
+func convert(value string, target reflect.Value) error {
+
+	// custom converter
+	if converter, ok := converters.Load(target.Type()); ok {
+
+		// convert value
+		converted, err := converter.(func(string) (interface{}, error))(value)
+		if err != nil {
+			return err
+		}
+
+		// set target
+		target.Set(reflect.ValueOf(converted))
+
+		return nil
+	}
+
+	// type parses text itself
+	if unmarshaler, ok := target.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(value))
+	}
+
+	switch target.Interface().(type) {
+
+	// duration
+	case time.Duration:
+
+		// parse duration
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+
+		// set target
+		target.SetInt(int64(duration))
+
+		return nil
+
+	// URL
+	case *url.URL:
+
+		// parse URL
+		parsed, err := parser.ParseURL(value)
+		if err != nil {
+			return err
+		}
+
+		// set target
+		target.Set(reflect.ValueOf(parsed))
+
+		return nil
+	}
+
+	switch target.Kind() {
+
+	// string
+	case reflect.String:
+		target.SetString(value)
+
+	// boolean
+	case reflect.Bool:
+
+		// parse boolean
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return parser.NumberError(err)
+		}
+
+		// set target
+		target.SetBool(parsed)
+
+	// signed integer
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		// parse integer
+		parsed, err := strconv.ParseInt(value, 10, target.Type().Bits())
+		if err != nil {
+			return parser.NumberError(err)
+		}
+
+		// set target
+		target.SetInt(parsed)
+
+	// unsigned integer
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+
+		// parse integer
+		parsed, err := strconv.ParseUint(value, 10, target.Type().Bits())
+		if err != nil {
+			return parser.NumberError(err)
+		}
+
+		// set target
+		target.SetUint(parsed)
+
+	// floating point
+	case reflect.Float32, reflect.Float64:
+
+		// parse floating point number
+		parsed, err := strconv.ParseFloat(value, target.Type().Bits())
+		if err != nil {
+			return parser.NumberError(err)
+		}
+
+		// set target
+		target.SetFloat(parsed)
+
+	// comma-separated list
+	case reflect.Slice:
+
+		// empty list
+		if len(strings.TrimSpace(value)) == 0 {
+			target.Set(reflect.MakeSlice(target.Type(), 0, 0))
+			return nil
+		}
+
+		// list items
+		items := strings.Split(value, ",")
+
+		// list
+		list := reflect.MakeSlice(target.Type(), len(items), len(items))
+
+		// iterating over items
+		for i, item := range items {
+
+			// convert item
+			if err := convert(strings.TrimSpace(item), list.Index(i)); err != nil {
+				return fmt.Errorf("item %d '%s': %s", i, strings.TrimSpace(item), err)
+			}
+		}
+
+		// set target
+		target.Set(list)
+
+	// any
+	default:
+		return fmt.Errorf("conversion to %s is not supported", target.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package envfile
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGet tests typed access to values.
+func TestGet(t *testing.T) {
+
+	// set environment variables for the test
+	os.Setenv("ENVFILE_GET_KEPT", "environment")
+	os.Setenv("ENVFILE_GET_ONLY_ENV", "8")
+
+	// deferred removal of the environment variables
+	defer os.Unsetenv("ENVFILE_GET_KEPT")
+	defer os.Unsetenv("ENVFILE_GET_ONLY_ENV")
+
+	// file content
+	filename := createFile(t, `
+export PORT = 8080
+export DEBUG = true
+export RATIO = 0.75
+export TIMEOUT = 1m30s
+export HOSTS = a.example.com, b.example.com
+export PORTS = 80,443
+export URL = https://example.com/path
+export STARTED = 2024-05-01T10:00:00Z
+export ADDRESS = 10.0.0.1
+export ENVFILE_GET_KEPT = file
+LOCAL = local
+`)
+
+	// parse values
+	values, err := ParseValues(filename)
+	if err != nil {
+		t.Fatalf("error parsing values: %v", err)
+	}
+
+	// integer
+	if port, err := Get[int](values, "PORT"); err != nil || port != 8080 {
+		t.Errorf("expected PORT = 8080, got %v, %v", port, err)
+	}
+
+	// boolean
+	if debug, err := Get[bool](values, "DEBUG"); err != nil || !debug {
+		t.Errorf("expected DEBUG = true, got %v, %v", debug, err)
+	}
+
+	// floating point
+	if ratio, err := Get[float32](values, "RATIO"); err != nil || ratio != 0.75 {
+		t.Errorf("expected RATIO = 0.75, got %v, %v", ratio, err)
+	}
+
+	// duration
+	if timeout, err := Get[time.Duration](values, "TIMEOUT"); err != nil || timeout != 90*time.Second {
+		t.Errorf("expected TIMEOUT = 1m30s, got %v, %v", timeout, err)
+	}
+
+	// list of strings
+	if hosts, err := Get[[]string](values, "HOSTS"); err != nil || !reflect.DeepEqual(hosts, []string{"a.example.com", "b.example.com"}) {
+		t.Errorf("expected HOSTS list, got %v, %v", hosts, err)
+	}
+
+	// list of integers
+	if ports, err := Get[[]uint16](values, "PORTS"); err != nil || !reflect.DeepEqual(ports, []uint16{80, 443}) {
+		t.Errorf("expected PORTS list, got %v, %v", ports, err)
+	}
+
+	// URL
+	if parsed, err := Get[*url.URL](values, "URL"); err != nil || parsed.Host != "example.com" {
+		t.Errorf("expected URL host example.com, got %v, %v", parsed, err)
+	}
+
+	// text unmarshaler
+	if started, err := Get[time.Time](values, "STARTED"); err != nil || started.Year() != 2024 {
+		t.Errorf("expected STARTED in 2024, got %v, %v", started, err)
+	}
+
+	// value already set in the environment is kept
+	if kept, err := Get[string](values, "ENVFILE_GET_KEPT"); err != nil || kept != "environment" {
+		t.Errorf("expected ENVFILE_GET_KEPT = environment, got %v, %v", kept, err)
+	}
+
+	// key missing in files is taken from the environment
+	if value, err := Get[int8](values, "ENVFILE_GET_ONLY_ENV"); err != nil || value != 8 {
+		t.Errorf("expected ENVFILE_GET_ONLY_ENV = 8, got %v, %v", value, err)
+	}
+
+	// local key is not a value
+	if _, err := Get[string](values, "LOCAL"); err == nil || err.Error() != "key 'LOCAL' is not set" {
+		t.Errorf("expected error for local key, got %v", err)
+	}
+
+	// value of another type
+	if _, err := Get[int](values, "DEBUG"); err == nil || !strings.Contains(err.Error(), "key 'DEBUG'") {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+
+	// custom converter
+	RegisterConverter(func(value string) (net.IP, error) {
+		return net.ParseIP(value), nil
+	})
+
+	// value converted by the custom converter
+	if address, err := Get[net.IP](values, "ADDRESS"); err != nil || !address.Equal(net.IPv4(10, 0, 0, 1)) {
+		t.Errorf("expected ADDRESS = 10.0.0.1, got %v, %v", address, err)
+	}
+}
+
+// TestGetConversionError tests errors of values that can't be converted.
+func TestGetConversionError(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "export PORT = abc\nexport PORTS = 80, x\n")
+
+	// parse values
+	values, err := ParseValues(filename)
+	if err != nil {
+		t.Fatalf("error parsing values: %v", err)
+	}
+
+	// convert value
+	_, err = Get[int](values, "PORT")
+
+	// conversion error
+	var conversion *ConversionError
+	if !errors.As(err, &conversion) {
+		t.Fatalf("expected conversion error, got %v", err)
+	}
+
+	// error fields are different from expected
+	if expected := (ConversionError{Key: "PORT", Raw: "abc", Target: "int", File: filename, Line: 1, Err: strconv.ErrSyntax}); *conversion != expected {
+		t.Errorf("expected %+v, got %+v", expected, *conversion)
+	}
+
+	// message is different from expected
+	if expected := "[" + filename + "] line 1: value 'abc' of key 'PORT' is not a valid int: invalid syntax"; err.Error() != expected {
+		t.Errorf("expected %s, got %s", expected, err)
+	}
+
+	// error of list item
+	if _, err := Get[[]int](values, "PORTS"); err == nil || !strings.HasSuffix(err.Error(), "is not a valid []int: item 1 'x': invalid syntax") {
+		t.Errorf("expected item error, got %v", err)
+	}
+
+	// error of payload method
+	payload := Payload{Line: 3, Key: "DEBUG", Value: "maybe"}
+	if _, err := payload.AsBool(); !errors.As(err, &conversion) || err.Error() != "line 3: value 'maybe' of key 'DEBUG' is not a valid bool" {
+		t.Errorf("expected conversion error of payload, got %v", err)
+	}
+}
@@ -1,3 +1,7 @@
 module github.com/afonichev/envfile
 
-go 1.12
\ No newline at end of file
+go 1.23
+
+require golang.org/x/crypto v0.21.0
+
+require golang.org/x/sys v0.18.0 // indirect
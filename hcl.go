@@ -0,0 +1,13 @@
+package envfile
+
+import (
+	"io"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// EncodeHCL writes exported and overloaded keys as the env stanza of a Nomad job file:
+// env { KEY = "value" }, with equal signs aligned the way hclfmt does.
+func EncodeHCL(payloads []Payload, w io.Writer) error {
+	return parser.EncodeHCL(payloads, w)
+}
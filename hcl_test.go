@@ -0,0 +1,42 @@
+package envfile
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestEncodeHCL tests the env stanza output.
+func TestEncodeHCL(t *testing.T) {
+
+	// payloads
+	payloads := []Payload{
+		{Key: "LOCAL", Value: "local"},
+		{Key: "HOST", Value: "localhost", Export: true},
+		{Key: "TEMPLATE", Value: "${node.unique.name} %{ if } \"quoted\"\n\\", Overload: true},
+	}
+
+	// stanza output
+	var buffer bytes.Buffer
+
+	// encode stanza
+	if err := EncodeHCL(payloads, &buffer); err != nil {
+		t.Fatalf("error encoding stanza: %v", err)
+	}
+
+	// expected stanza
+	expected := `env {
+  HOST     = "localhost"
+  TEMPLATE = "$${node.unique.name} %%{ if } \"quoted\"\n\\"
+}
+`
+
+	// stanza is different from expected
+	if buffer.String() != expected {
+		t.Errorf("expected stanza:\n%s\ngot:\n%s", expected, buffer.String())
+	}
+
+	// key starting with a digit
+	if err := EncodeHCL([]Payload{{Key: "1KEY", Export: true}}, &buffer); err == nil {
+		t.Error("key is not an HCL identifier but encode didn't return an error")
+	}
+}
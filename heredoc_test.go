@@ -0,0 +1,70 @@
+package envfile
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestHeredoc tests multi-line values.
+func TestHeredoc(t *testing.T) {
+
+	// file with multi-line values
+	content := "HOST = localhost\nexport CONFIG = <<EOF\nserver {{\n  host { HOST }\n}}\nEOF\nexport TEMPLATE = <<'END'\n{ HOST }\\n\n  END\nNEXT = after\n"
+	filename := createFile(t, content)
+
+	// parse file
+	payloads, err := Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// expected values
+	expected := map[string]string{
+		"CONFIG":   "server {\n  host localhost\n}",
+		"TEMPLATE": "{ HOST }\\n",
+		"NEXT":     "after",
+	}
+
+	// iterating over expected values
+	for key, value := range expected {
+		if payload, _ := payloads.Lookup(key); payload.Value != value {
+			t.Errorf("expected %s to be %q, got %q", key, value, payload.Value)
+		}
+	}
+
+	// line of the key after the multi-line values
+	if payload, _ := payloads.Lookup("NEXT"); payload.Line != 10 {
+		t.Errorf("expected NEXT on line 10, got %d", payload.Line)
+	}
+
+	// document is written back as it is
+	if err := Roundtrip(filename); err != nil {
+		t.Errorf("error checking roundtrip: %v", err)
+	}
+
+	// value is not closed
+	if _, err := Parse(createFile(t, "KEY = <<EOF\nvalue\n")); err == nil || !strings.Contains(err.Error(), "not closed with 'EOF'") {
+		t.Errorf("expected error of the unclosed value, got %v", err)
+	}
+
+	// file editor
+	file, err := Open(filename)
+	if err != nil {
+		t.Fatalf("error opening env file: %v", err)
+	}
+
+	// multi-line value without delimiters
+	if value, _ := file.Get("TEMPLATE"); value != "{ HOST }\\n" {
+		t.Errorf("expected TEMPLATE to be the multi-line value, got %q", value)
+	}
+
+	// replace multi-line value
+	if err := file.Set("CONFIG", "{}"); err != nil {
+		t.Fatalf("error setting CONFIG: %v", err)
+	}
+
+	// lines of the old value are removed
+	if text := string(file.doc.Bytes()); !strings.HasPrefix(text, "HOST = localhost\nexport CONFIG = {}\nexport TEMPLATE = <<'END'\n") {
+		t.Errorf("unexpected document %q", text)
+	}
+}
@@ -0,0 +1,42 @@
+package envfile
+
+import (
+	"io"
+	"path/filepath"
+)
+
+// openIncluded opens the included file from the file system of the including document.
+func (l *Loader) openIncluded(doc *Document, name string) (io.ReadCloser, error) {
+
+	// file of the file system
+	if doc.FS != nil {
+		return l.openFS(doc.FS, name)
+	}
+
+	return l.openFile(name)
+}
+
+// includeDocument reads the document of the file included by the document, the included
+// file is counted for the file being parsed.
+func (l *Loader) includeDocument(filename string, doc *Document, name string) (*Document, error) {
+
+	// open included file
+	file, err := l.openIncluded(doc, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// deferred file close
+	defer file.Close()
+
+	// read included document
+	included, err := l.readDocument(name, file)
+	if err != nil {
+		return nil, err
+	}
+
+	// record included file
+	l.count(filepath.Clean(filename), Stats{Includes: 1})
+
+	return included, nil
+}
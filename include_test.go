@@ -0,0 +1,197 @@
+package envfile
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestInclude tests the include and source directives.
+func TestInclude(t *testing.T) {
+
+	// deferred removal of environment variables
+	defer os.Unsetenv("INCLUDE_HOST")
+	defer os.Unsetenv("INCLUDE_URL")
+
+	// temporary directory with nested directories
+	dir, err := ioutil.TempDir("", "include")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v", err)
+	}
+
+	// deferred directory removal
+	defer os.RemoveAll(dir)
+
+	// shared directory
+	if err := os.Mkdir(filepath.Join(dir, "shared"), 0755); err != nil {
+		t.Fatalf("error creating directory: %v", err)
+	}
+
+	// files including each other
+	files := map[string]string{
+		"shared/base.envfile": "export INCLUDE_HOST = localhost\nsource \"port.envfile\"\n",
+		"shared/port.envfile": "PORT = 5432\n",
+		"app.envfile":         "include shared/base.envfile\nexport INCLUDE_URL = postgres://{ INCLUDE_HOST }:{ PORT }\n",
+		"cycle.envfile":       "A = 1\ninclude loop.envfile\n",
+		"loop.envfile":        "B = 2\ninclude cycle.envfile\n",
+		"missing.envfile":     "include absent.envfile\n",
+		"duplicate.envfile":   "PORT = 1\ninclude shared/port.envfile\n",
+	}
+
+	// iterating over files
+	for name, content := range files {
+
+		// write file
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("error writing file: %v", err)
+		}
+	}
+
+	// loader
+	loader := NewLoader()
+
+	// load file with includes
+	if err := loader.Load(filepath.Join(dir, "app.envfile")); err != nil {
+		t.Fatalf("error loading env file: %v", err)
+	}
+
+	// keys of the included files are referenced
+	if value := os.Getenv("INCLUDE_URL"); value != "postgres://localhost:5432" {
+		t.Errorf("expected INCLUDE_URL to be 'postgres://localhost:5432', got '%s'", value)
+	}
+
+	// result of loading
+	result := loader.Result()
+
+	// key is reported with the file it is written in
+	if entry := result.Entries[0]; entry.Key != "INCLUDE_HOST" || entry.File != filepath.Join(dir, "shared/base.envfile") || entry.Line != 1 {
+		t.Errorf("expected INCLUDE_HOST from line 1 of the base file, got %s from line %d of %s", entry.Key, entry.Line, entry.File)
+	}
+
+	// included files are counted
+	if result.Stats.Includes != 2 {
+		t.Errorf("expected 2 includes, got %d", result.Stats.Includes)
+	}
+
+	// errors of included files
+	errors := map[string]string{
+		"cycle.envfile":     "include cycle",
+		"missing.envfile":   "can't include 'absent.envfile'",
+		"duplicate.envfile": "port.envfile] line 1: duplicate key 'PORT'",
+	}
+
+	// iterating over files with errors
+	for name, expected := range errors {
+
+		// parse file
+		_, err := loader.Parse(filepath.Join(dir, name))
+
+		// error is different from expected
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error of %s to contain '%s', got %v", name, expected, err)
+		}
+	}
+}
+
+// TestLintInclude tests that keys of included files are not checked with the lines of the including file.
+func TestLintInclude(t *testing.T) {
+
+	// included file with a secret on a line the including file does not have
+	included := createFile(t, "\n\n\nexport DB_PASSWORD = qwerty\n")
+
+	// including file
+	filename := createFile(t, "include "+included+"\n")
+
+	// check file
+	findings, err := NewLinter().Lint(filename)
+	if err != nil {
+		t.Fatalf("error linting env file: %v", err)
+	}
+
+	// keys of the included file are checked
+	if len(findings) > 0 {
+		t.Errorf("expected no findings, got %v", findings)
+	}
+}
+
+// TestIncludeSourcesAndRefresh tests file sources and refreshing of keys of included files.
+func TestIncludeSourcesAndRefresh(t *testing.T) {
+
+	// deferred removal of environment variables
+	defer os.Unsetenv("INCLUDE_TOKEN")
+	defer os.Unsetenv("INCLUDE_PASSWORD")
+
+	// temporary directory with a nested directory
+	dir, err := ioutil.TempDir("", "include")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v", err)
+	}
+
+	// deferred directory removal
+	defer os.RemoveAll(dir)
+
+	// shared directory
+	if err := os.Mkdir(filepath.Join(dir, "shared"), 0755); err != nil {
+		t.Fatalf("error creating directory: %v", err)
+	}
+
+	// included file with a file source next to it and a leased secret
+	files := map[string]string{
+		"shared/token":        "token",
+		"shared/base.envfile": "\nexport INCLUDE_TOKEN = file:token\nexport INCLUDE_PASSWORD = { rotating://db }\n",
+		"app.envfile":         "include shared/base.envfile\n",
+	}
+
+	// iterating over files
+	for name, content := range files {
+
+		// write file
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("error writing file: %v", err)
+		}
+	}
+
+	// loader with value sources and a provider of expiring secrets
+	loader := NewLoader(WithValueSources(true), WithProvider(&rotatingProvider{}))
+
+	// load file
+	if err := loader.Load(filepath.Join(dir, "app.envfile")); err != nil {
+		t.Fatalf("error loading env file: %v", err)
+	}
+
+	// file source is relative to the included file
+	if value := os.Getenv("INCLUDE_TOKEN"); value != "token" {
+		t.Errorf("expected INCLUDE_TOKEN to be token, got '%s'", value)
+	}
+
+	// context of the refresher
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+
+	// deferred cancel of the refresher
+	defer cancel()
+
+	// refreshed values
+	values := make(chan string, 1)
+
+	// refresh in the background
+	go loader.Refresh(ctx, func(key, value string) {
+		select {
+		case values <- key + "=" + value:
+		default:
+		}
+	})
+
+	// rotated value of the included key
+	select {
+	case value := <-values:
+		if value != "INCLUDE_PASSWORD=password-2" {
+			t.Errorf("expected INCLUDE_PASSWORD=password-2, got %s", value)
+		}
+	case <-ctx.Done():
+		t.Fatal("value of the included key was not refreshed")
+	}
+}
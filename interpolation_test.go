@@ -0,0 +1,130 @@
+package envfile
+
+import (
+	"os"
+	"testing"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// TestDefaultValues tests default values of references to variables that do not exist.
+func TestDefaultValues(t *testing.T) {
+
+	// set environment variable
+	os.Setenv("INTERPOLATION_HOST", "db")
+
+	// deferred removal of the environment variable
+	defer os.Unsetenv("INTERPOLATION_HOST")
+
+	// content with default values
+	content := "PORT = 5432\n" +
+		"EMPTY =\n" +
+		"HOST = { INTERPOLATION_HOST:-localhost }\n" +
+		"USER = { INTERPOLATION_USER:-admin user }\n" +
+		"URL = { INTERPOLATION_SCHEME :- postgres }://{ HOST }:{ PORT:-1 }\n" +
+		"SET = { EMPTY:-not used }\n" +
+		"NOTHING = {INTERPOLATION_MISSING:-}\n" +
+		"ESCAPED = { INTERPOLATION_MISSING:-a\\tb }\n"
+
+	// expected values
+	expected := map[string]string{
+		"PORT":    "5432",
+		"EMPTY":   "",
+		"HOST":    "db",
+		"USER":    "admin user",
+		"URL":     "postgres://db:5432",
+		"SET":     "",
+		"NOTHING": "",
+		"ESCAPED": "a\tb",
+	}
+
+	// default braces, custom delimiters
+	for _, loader := range []*Loader{NewLoader(), NewLoader(WithDelimiters("{", "}"))} {
+
+		// parse content
+		payloads, err := loader.ParseString(content)
+		if err != nil {
+			t.Fatalf("error parsing content: %v", err)
+		}
+
+		// iterating over payloads
+		for _, payload := range payloads {
+			if value := expected[payload.Key]; payload.Value != value {
+				t.Errorf("expected %s = %q, got %q", payload.Key, value, payload.Value)
+			}
+		}
+	}
+
+	// shell form and lenient braces
+	tests := []struct {
+		loader   *Loader
+		content  string
+		expected string
+	}{
+		{NewLoader(WithDollarReferences(true)), "URL = ${INTERPOLATION_SCHEME:-https}://$INTERPOLATION_HOST\n", "https://db"},
+		{NewLoader(WithBraces(BracesLenient)), "URL = {INTERPOLATION_SCHEME:-https}://{{ .Host }}\n", "https://{{ .Host }}"},
+	}
+
+	// iterating over tests
+	for _, test := range tests {
+
+		// parse content
+		payloads, err := test.loader.ParseString(test.content)
+		if err != nil {
+			t.Fatalf("error parsing content: %v", err)
+		}
+
+		// reference is resolved with the default value
+		if payloads[0].Value != test.expected {
+			t.Errorf("expected %q, got %q", test.expected, payloads[0].Value)
+		}
+	}
+
+	// reference names of the document exclude default values
+	if node := parser.ParseLine(parser.Syntax{}, 1, "URL = { SCHEME:-https }"); len(node.References) != 1 || node.References[0].Name != "SCHEME" {
+		t.Errorf("expected reference to SCHEME, got %+v", node.References)
+	}
+
+	// variable without a default value still does not exist
+	if _, err := ParseString("URL = { INTERPOLATION_MISSING }\n"); err == nil {
+		t.Error("variable does not exist but parse didn't return an error")
+	}
+}
+
+// TestRequiredVariables tests error messages of references to required variables.
+func TestRequiredVariables(t *testing.T) {
+
+	// set environment variable
+	os.Setenv("INTERPOLATION_REGION", "eu")
+
+	// deferred removal of the environment variable
+	defer os.Unsetenv("INTERPOLATION_REGION")
+
+	// existing variables are resolved
+	payloads, err := ParseString("PORT = 80\nURL = http://{ INTERPOLATION_REGION:?region is required }:{ PORT:? }\n")
+	if err != nil {
+		t.Fatalf("error parsing content: %v", err)
+	}
+	if payloads[1].Value != "http://eu:80" {
+		t.Errorf("expected http://eu:80, got %q", payloads[1].Value)
+	}
+
+	// expected errors by content
+	cases := map[string]string{
+		"\nHOST = { INTERPOLATION_DB_HOST:?set it to the database address }\n": "[reader] line 2: variable 'INTERPOLATION_DB_HOST' is required: set it to the database address",
+		"HOST = { INTERPOLATION_DB_HOST:? }\n":                                 "[reader] line 1: variable 'INTERPOLATION_DB_HOST' is required",
+	}
+
+	// iterating over cases
+	for content, expected := range cases {
+
+		// default braces, custom delimiters and the policy for undefined variables
+		for _, loader := range []*Loader{NewLoader(), NewLoader(WithDelimiters("{", "}")), NewLoader(WithUndefinedPolicy(UndefinedEmpty))} {
+
+			// required variable does not exist
+			if _, err := loader.ParseString(content); err == nil || err.Error() != expected {
+				t.Errorf("expected %q, got %v", expected, err)
+			}
+		}
+	}
+}
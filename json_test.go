@@ -0,0 +1,101 @@
+package envfile
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestParseJSONReference tests references to fields of JSON values.
+func TestParseJSONReference(t *testing.T) {
+
+	// set environment variable for the test
+	os.Setenv("ENVFILE_JSON", `{"database": {"host": "db", "ports": [5432, 5433]}, "path": "C:\\{dir}"}`)
+
+	// deferred removal of the environment variable
+	defer os.Unsetenv("ENVFILE_JSON")
+
+	// file content
+	filename := createFile(t, `
+LOCAL = {{ "name": "main" }}
+export HOST = { ENVFILE_JSON.database.host }:{ ENVFILE_JSON.database.ports.1 }
+export NAME = { LOCAL.name }
+export DATABASE = { ENVFILE_JSON.database.ports }
+export PATH_VALUE = { ENVFILE_JSON.path }
+`)
+
+	// expected key/value pairs
+	pairs := map[string]string{
+		"HOST":       "db:5433",
+		"NAME":       "main",
+		"DATABASE":   "[5432,5433]",
+		"PATH_VALUE": `C:\{dir}`,
+	}
+
+	// parse file
+	payloads, err := Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// iteration over payloads
+	for _, payload := range payloads {
+
+		// value from payload is different from expected
+		if value, ok := pairs[payload.Key]; ok && payload.Value != value {
+			t.Errorf("expected %s to be %s, got %s", payload.Key, value, payload.Value)
+		}
+	}
+
+	// reference to a missing field
+	filename = createFile(t, "KEY = { ENVFILE_JSON.database.user }\n")
+
+	// parse file
+	if _, err := Parse(filename); err == nil {
+		t.Error("field doesn't exist but parse didn't return an error")
+	}
+}
+
+// TestParseJSONRecursiveReference tests JSON values referencing their own fields or each other.
+func TestParseJSONRecursiveReference(t *testing.T) {
+
+	// expected errors by file content
+	cases := map[string]string{
+		"B = {{\"x\": \"{ B.x }\"}}\n":                             "line 1: key 'B' is used recursively",
+		"A = {{\"x\": \"{ B.y }\"}}\nB = {{\"y\": \"{ A.x }\"}}\n": "line 1: key 'A' is used recursively",
+	}
+
+	// iterating over cases
+	for content, expected := range cases {
+
+		// parse file
+		_, err := Parse(createFile(t, content))
+
+		// error is different from expected
+		if err == nil || !strings.HasSuffix(err.Error(), expected) {
+			t.Errorf("expected error '%s' for %q, got %v", expected, content, err)
+		}
+	}
+}
+
+// TestParseJSONReferenceSyntax tests references to fields of JSON values having references of the loader syntax.
+func TestParseJSONReferenceSyntax(t *testing.T) {
+
+	// file with a JSON value having a $KEY reference and a literal dollar sign
+	filename := createFile(t, `
+HOST = db
+CONFIG = {"host": "$HOST", "price": "\$PRICE"}
+export URL = ${CONFIG.host}/${CONFIG.price}
+`)
+
+	// parse file with $KEY references and lenient curly braces
+	payloads, err := NewLoader(WithDollarReferences(true), WithBraces(BracesLenient)).Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// value is different from expected
+	if url, _ := payloads.Lookup("URL"); url.Value != "db/$PRICE" {
+		t.Errorf("expected URL to be db/$PRICE, got %s", url.Value)
+	}
+}
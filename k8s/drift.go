@@ -0,0 +1,105 @@
+package k8s
+
+import (
+	"strings"
+
+	"github.com/afonichev/envfile"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DriftExternal means the container takes the key from a ConfigMap, a Secret or a field
+// reference, with valueFrom or envFrom, so its value can't be compared with the file.
+const DriftExternal envfile.DriftKind = "external"
+
+// Compare reports differences between exported and overloaded keys of the file and the environment
+// of the container: keys the container lacks, keys only the container has and different values.
+// Keys of the file are reported first in the order of the file, keys of the container follow
+// in the order of the manifest. Values of the manifest are compared as Kubernetes escapes them.
+func Compare(filename string, payloads envfile.Payloads, container corev1.Container) []envfile.Drift {
+
+	// variables of the container by name, later ones take precedence as in Kubernetes
+	vars := make(map[string]corev1.EnvVar, len(container.Env))
+	for _, v := range container.Env {
+		vars[v.Name] = v
+	}
+
+	// keys of the file
+	keys := make(map[string]bool, len(payloads))
+
+	// differences list
+	var drifts []envfile.Drift
+
+	// iterating over a list of payloads
+	for _, payload := range payloads {
+
+		// key is local to the file
+		if !payload.Export && !payload.Overload {
+			continue
+		}
+
+		// remember key
+		keys[payload.Key] = true
+
+		// difference of the key
+		drift := envfile.Drift{Key: payload.Key, File: filename, Line: payload.Line, Expected: payload.Value}
+
+		// variable of the container
+		v, ok := vars[payload.Key]
+
+		switch {
+
+		// key may come from a ConfigMap or a Secret
+		case !ok && len(container.EnvFrom) > 0:
+			drift.Kind = DriftExternal
+
+		// key is missing
+		case !ok:
+			drift.Kind = envfile.DriftMissing
+
+		// value comes from a reference
+		case v.ValueFrom != nil:
+			drift.Kind = DriftExternal
+
+		// value is different
+		case v.Value != strings.Replace(payload.Value, "$", "$$", -1):
+			drift.Kind = envfile.DriftMismatch
+			drift.Actual = v.Value
+
+		// value is the same
+		default:
+			continue
+		}
+
+		// add difference to list
+		drifts = append(drifts, drift)
+	}
+
+	// iterating over variables of the container
+	for _, v := range container.Env {
+
+		// key is defined in the file or already reported
+		if keys[v.Name] {
+			continue
+		}
+
+		// report key once
+		keys[v.Name] = true
+
+		// add difference to list
+		drifts = append(drifts, envfile.Drift{Kind: envfile.DriftExtra, Key: v.Name, Actual: v.Value})
+	}
+
+	return drifts
+}
+
+// CompareFile parses the env file and compares it with the environment of the container, see Compare.
+func CompareFile(filename string, container corev1.Container, options ...envfile.Option) ([]envfile.Drift, error) {
+
+	// parse file
+	payloads, err := envfile.NewLoader(options...).Parse(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return Compare(filename, payloads, container), nil
+}
@@ -0,0 +1,51 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/afonichev/envfile"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TestCompare tests comparison of payloads with the environment of a container.
+func TestCompare(t *testing.T) {
+
+	// payloads
+	payloads := envfile.Payloads{
+		{Line: 1, Key: "LOCAL", Value: "local"},
+		{Line: 2, Key: "HOST", Value: "localhost", Export: true},
+		{Line: 3, Key: "PORT", Value: "8080", Export: true},
+		{Line: 4, Key: "PRICE", Value: "5$", Export: true},
+		{Line: 5, Key: "PASSWORD", Value: "secret", Overload: true},
+		{Line: 6, Key: "NAME", Value: "app", Export: true},
+	}
+
+	// container environment
+	container := corev1.Container{Env: []corev1.EnvVar{
+		{Name: "HOST", Value: "db.internal"},
+		{Name: "PRICE", Value: "5$$"},
+		{Name: "PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{Key: "password"}}},
+		{Name: "NAME", Value: "app"},
+		{Name: "DEBUG", Value: "true"},
+	}}
+
+	// expected differences
+	expected := []envfile.Drift{
+		{Kind: envfile.DriftMismatch, Key: "HOST", File: ".envfile", Line: 2, Expected: "localhost", Actual: "db.internal"},
+		{Kind: envfile.DriftMissing, Key: "PORT", File: ".envfile", Line: 3, Expected: "8080"},
+		{Kind: DriftExternal, Key: "PASSWORD", File: ".envfile", Line: 5, Expected: "secret"},
+		{Kind: envfile.DriftExtra, Key: "DEBUG", Actual: "true"},
+	}
+
+	// differences are different from expected
+	if drifts := Compare(".envfile", payloads, container); !reflect.DeepEqual(drifts, expected) {
+		t.Errorf("expected %+v, got %+v", expected, drifts)
+	}
+
+	// missing key may come from envFrom
+	container.EnvFrom = []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{}}}
+	if drifts := Compare(".envfile", payloads, container); drifts[1].Kind != DriftExternal {
+		t.Errorf("expected PORT to be external, got %+v", drifts[1])
+	}
+}
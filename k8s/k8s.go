@@ -0,0 +1,48 @@
+// Package k8s converts env files into the environment of Kubernetes containers,
+// it is a separate module so the main one does not depend on the Kubernetes API.
+package k8s
+
+import (
+	"strings"
+
+	"github.com/afonichev/envfile"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// EnvVars returns exported and overloaded keys as the environment of a container spec
+// in the order of the file. Dollar signs are doubled, so Kubernetes does not expand
+// $(VAR) in the values that are already resolved.
+func EnvVars(payloads envfile.Payloads) []corev1.EnvVar {
+
+	// environment list
+	vars := make([]corev1.EnvVar, 0, len(payloads))
+
+	// iterating over a list of payloads
+	for _, payload := range payloads {
+
+		// key is local to the file
+		if !payload.Export && !payload.Overload {
+			continue
+		}
+
+		// add variable to list
+		vars = append(vars, corev1.EnvVar{
+			Name:  payload.Key,
+			Value: strings.Replace(payload.Value, "$", "$$", -1),
+		})
+	}
+
+	return vars
+}
+
+// Load parses the env file and returns its environment for a container spec.
+func Load(filename string, options ...envfile.Option) ([]corev1.EnvVar, error) {
+
+	// parse file
+	payloads, err := envfile.NewLoader(options...).Parse(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return EnvVars(payloads), nil
+}
@@ -0,0 +1,31 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/afonichev/envfile"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TestEnvVars tests conversion of payloads into the environment of a container spec.
+func TestEnvVars(t *testing.T) {
+
+	// payloads
+	payloads := envfile.Payloads{
+		{Key: "LOCAL", Value: "local"},
+		{Key: "HOST", Value: "localhost", Export: true},
+		{Key: "PRICE", Value: "$(HOST) costs 5$", Overload: true},
+	}
+
+	// expected environment
+	expected := []corev1.EnvVar{
+		{Name: "HOST", Value: "localhost"},
+		{Name: "PRICE", Value: "$$(HOST) costs 5$$"},
+	}
+
+	// environment is different from expected
+	if vars := EnvVars(payloads); !reflect.DeepEqual(vars, expected) {
+		t.Errorf("expected %v, got %v", expected, vars)
+	}
+}
@@ -0,0 +1,65 @@
+package envfile
+
+import "strings"
+
+// WithCaseInsensitiveKeys makes keys differing only in case the same key, as they are
+// in the Windows environment: duplicate keys, references and variables of the environment
+// are matched regardless of case, and a variable of the environment is set under the name
+// it already has, so FOO and foo never coexist.
+func WithCaseInsensitiveKeys(enabled bool) Option {
+	return func(l *Loader) {
+
+		// set case-insensitive keys status
+		l.caseInsensitive = enabled
+	}
+}
+
+// sameKey reports whether the names refer to the same key.
+func (l *Loader) sameKey(a, b string) bool {
+
+	// case-insensitive keys
+	if l.caseInsensitive {
+		return strings.EqualFold(a, b)
+	}
+
+	return a == b
+}
+
+// indexKey returns the name of the key used in indexes: upper-cased with case-insensitive keys.
+func (l *Loader) indexKey(key string) string {
+
+	// case-insensitive keys
+	if l.caseInsensitive {
+		return strings.ToUpper(key)
+	}
+
+	return key
+}
+
+// envKey returns the name of the variable of the environment matching the key,
+// or the key itself if no variable matches it.
+func (l *Loader) envKey(key string) string {
+
+	// case-sensitive keys or the exact name exists
+	if _, ok := l.lookupExact(key); !l.caseInsensitive || ok {
+		return key
+	}
+
+	// variables of the environment in the form "key=value", the key is taken as it is written
+	// if the environment can't be listed
+	environ, _ := l.environ()
+
+	// iterating over variables of the environment
+	for _, variable := range environ {
+
+		// variable name
+		name := strings.SplitN(variable, "=", 2)[0]
+
+		// variable matches the key
+		if strings.EqualFold(name, key) {
+			return name
+		}
+	}
+
+	return key
+}
@@ -0,0 +1,91 @@
+package envfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestCaseInsensitiveKeys tests matching of keys regardless of case.
+func TestCaseInsensitiveKeys(t *testing.T) {
+
+	// file content
+	filename := createFile(t, `
+export Path = /usr/bin:{ home }/bin
+HOME = /home/user
+`)
+
+	// environment with a variable written in another case
+	env := MapEnvironment{"PATH": "/bin"}
+
+	// loader with case-insensitive keys
+	loader := NewLoader(WithCaseInsensitiveKeys(true), WithEnvironment(env))
+
+	// parse file
+	payloads, err := loader.Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// reference is resolved regardless of case
+	if payloads[0].Value != "/usr/bin:/home/user/bin" {
+		t.Errorf("expected /usr/bin:/home/user/bin, got %s", payloads[0].Value)
+	}
+
+	// existing variable is kept, the key matches it regardless of case
+	if err := loader.Load(filename); err != nil {
+		t.Fatalf("error loading env file: %v", err)
+	}
+
+	// no variable differing only in case is added
+	if len(env) != 1 || env["PATH"] != "/bin" {
+		t.Errorf("expected only PATH = /bin, got %v", env)
+	}
+
+	// keys differing only in case are duplicates
+	if _, err := loader.Parse(createFile(t, "KEY = 1\nkey = 2\n")); err == nil {
+		t.Error("duplicate key in another case but parse didn't return an error")
+	}
+
+	// keys differing only in case are different keys by default
+	if _, err := NewLoader().Parse(createFile(t, "KEY = 1\nkey = 2\n")); err != nil {
+		t.Errorf("error parsing keys differing in case: %v", err)
+	}
+}
+
+// TestCaseInsensitiveValues tests lookups of values regardless of case.
+func TestCaseInsensitiveValues(t *testing.T) {
+
+	// base and override files writing the key in different cases
+	base := createFile(t, "export Db_Host = localhost\nexport PORT = 80\n")
+	local := createFile(t, "overload DB_HOST = db\n")
+
+	// loader with case-insensitive keys and an environment with a variable written in another case
+	loader := NewLoader(WithCaseInsensitiveKeys(true), WithEnvironment(MapEnvironment{"port": "8080"}))
+
+	// parse values
+	values, err := loader.ParseValues(base, local)
+	if err != nil {
+		t.Fatalf("error parsing values: %v", err)
+	}
+
+	// overloaded key is found regardless of case
+	if value, source, ok := values.GetWithSource("db_host"); !ok || value != "db" || source.File != local {
+		t.Errorf("expected db from %s, got %s from %s", local, value, source)
+	}
+
+	// key of the environment is kept regardless of case
+	if value, ok := values.Lookup("Port"); !ok || value != "8080" {
+		t.Errorf("expected 8080, got %s", value)
+	}
+
+	// keys with values, written as they are defined for the first time
+	all := make(map[string]string)
+	for key, value := range values.All() {
+		all[key] = value
+	}
+
+	// keys are different from expected
+	if expected := map[string]string{"Db_Host": "db", "PORT": "8080"}; !reflect.DeepEqual(all, expected) {
+		t.Errorf("expected %v, got %v", expected, all)
+	}
+}
@@ -0,0 +1,21 @@
+package envfile
+
+import "github.com/afonichev/envfile/parser"
+
+// Kind is a type of value recognized from its literal.
+type Kind = parser.Kind
+
+const (
+
+	// KindString is any value that is not recognized as a typed literal.
+	KindString = parser.KindString
+
+	// KindBool is a true or false literal.
+	KindBool = parser.KindBool
+
+	// KindInt is an integer literal.
+	KindInt = parser.KindInt
+
+	// KindFloat is a floating point literal.
+	KindFloat = parser.KindFloat
+)
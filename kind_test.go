@@ -0,0 +1,95 @@
+package envfile
+
+import "testing"
+
+// TestPayloadKind tests recognition of typed literals.
+func TestPayloadKind(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "BOOL = TRUE\nINT = -42\nFLOAT = 1.5e3\nSTRING = 42 apples\nNAN = NaN\n")
+
+	// parse file
+	payloads, err := Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// expected typed values
+	expected := []interface{}{true, int64(-42), 1.5e3, "42 apples", "NaN"}
+
+	// iteration over payloads
+	for i, payload := range payloads {
+
+		// typed value is different from expected
+		if payload.Typed() != expected[i] {
+			t.Errorf("expected %s to be %v (%T), got %v (%T)",
+				payload.Key, expected[i], expected[i], payload.Typed(), payload.Typed())
+		}
+	}
+
+	// string is not a number
+	if _, err := payloads[3].AsInt(); err == nil {
+		t.Error("string value converted to int without an error")
+	}
+
+	// integer is a float too
+	if value, err := payloads[1].AsFloat(); err != nil || value != -42 {
+		t.Errorf("expected -42, got %v (%v)", value, err)
+	}
+}
+
+// TestPayloadKindQuoted tests that quoted values are strings whatever they look like.
+func TestPayloadKindQuoted(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "PORT = \"8080\"\nFLAG = 'true'\nRATE = \"1.5\"\nCOUNT = 8080\n")
+
+	// parse file
+	payloads, err := Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// expected kinds of values
+	expected := []Kind{KindString, KindString, KindString, KindInt}
+
+	// iteration over payloads
+	for i, payload := range payloads {
+
+		// kind is different from expected
+		if payload.Kind != expected[i] {
+			t.Errorf("expected %s to be %s, got %s", payload.Key, expected[i], payload.Kind)
+		}
+	}
+
+	// quoted number is kept as a string
+	if value := payloads[0].Typed(); value != "8080" {
+		t.Errorf("expected PORT to be string 8080, got %v (%T)", value, value)
+	}
+}
+
+// TestPayloadKindUnchanged tests that numbers that can't be typed without changing them are strings.
+func TestPayloadKindUnchanged(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "HUGE = 1e999\nBIG = 99999999999999999999\nCODE = 007\nMODE = -0755\nPADDED = 00.5\nZERO = 0\nHALF = 0.5\n")
+
+	// parse file
+	payloads, err := Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// expected typed values
+	expected := []interface{}{"1e999", "99999999999999999999", "007", "-0755", "00.5", int64(0), 0.5}
+
+	// iteration over payloads
+	for i, payload := range payloads {
+
+		// typed value is different from expected
+		if payload.Typed() != expected[i] {
+			t.Errorf("expected %s to be %v (%T), got %v (%T)",
+				payload.Key, expected[i], expected[i], payload.Typed(), payload.Typed())
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package envfile
+
+import (
+	"os"
+	"testing"
+)
+
+// TestParseKubernetes tests file parsing with the Kubernetes dialect.
+func TestParseKubernetes(t *testing.T) {
+
+	// set environment variable for the test
+	os.Setenv("ENVFILE_TEST_HOST", "localhost")
+
+	// deferred removal of the environment variable
+	defer os.Unsetenv("ENVFILE_TEST_HOST")
+
+	// file content
+	filename := createFile(t, `
+KEY_1 = value
+KEY_2 = $(KEY_1) and $(ENVFILE_TEST_HOST)
+KEY_3 = $(MISSING) stays literal
+KEY_4 = $$(KEY_1) is escaped, $$ is a dollar
+KEY_5 = $(KEY_6) is defined later
+KEY_6 = { KEY_1 }\n$(KEY_1
+`)
+
+	// expected key/value pairs
+	pairs := map[string]string{
+		"KEY_2": "value and localhost",
+		"KEY_3": "$(MISSING) stays literal",
+		"KEY_4": "$(KEY_1) is escaped, $ is a dollar",
+		"KEY_5": "$(KEY_6) is defined later",
+		"KEY_6": `{ KEY_1 }\n$(KEY_1`,
+	}
+
+	// parse file
+	payloads, err := NewLoader(WithDialect(DialectKubernetes)).Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// iteration over payloads
+	for _, payload := range payloads {
+
+		// value from payload is different from expected
+		if value, ok := pairs[payload.Key]; ok && payload.Value != value {
+			t.Errorf("expected %s to be %s, got %s", payload.Key, value, payload.Value)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package envfile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Layer is a source of values with a priority for Merge: a file, a map or a snapshot of an environment.
+type Layer struct {
+
+	// name shown in provenance, e.g. the file name
+	Name string
+
+	// values of a layer with the higher priority take precedence, later layers win on equal priorities
+	Priority int
+
+	// values of the layer in order
+	values func(l *Loader) ([]layerValue, error)
+}
+
+// layerValue is a value of the layer with its line.
+type layerValue struct {
+
+	// key
+	key string
+
+	// value
+	value string
+
+	// line number in file, zero for maps and environments
+	line int
+}
+
+// Provenance is the layer a merged value comes from.
+type Provenance struct {
+
+	// name of the layer
+	Layer string `json:"layer"`
+
+	// priority of the layer
+	Priority int `json:"priority"`
+
+	// line number in file, zero for maps and environments
+	Line int `json:"line,omitempty"`
+}
+
+// Merged is the value of a key after merging layers.
+type Merged struct {
+
+	// key
+	Key string `json:"key"`
+
+	// winning value
+	Value string `json:"value"`
+
+	// layer of the winning value
+	From Provenance `json:"from"`
+
+	// layers with values that lost, from the highest priority
+	Shadowed []Provenance `json:"shadowed,omitempty"`
+}
+
+// FileLayer is the file with the priority, exported and overloaded keys are taken from it.
+// Remote names like doppler://project/config are fetched as by Load.
+func FileLayer(filename string, priority int) Layer {
+	return Layer{Name: filename, Priority: priority, values: func(l *Loader) ([]layerValue, error) {
+
+		// parse file
+		payloads, err := l.Parse(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		// values of the file
+		var values []layerValue
+
+		// iterating over a list of payloads
+		for _, payload := range payloads {
+
+			// key is local to the file
+			if !payload.Export && !payload.Overload {
+				continue
+			}
+
+			// add value
+			values = append(values, layerValue{key: payload.Key, value: payload.Value, line: payload.Line})
+		}
+
+		return values, nil
+	}}
+}
+
+// MapLayer is the map of values with the name and the priority, e.g. defaults compiled into the program.
+func MapLayer(name string, values map[string]string, priority int) Layer {
+	return Layer{Name: name, Priority: priority, values: func(l *Loader) ([]layerValue, error) {
+
+		// keys in alphabetical order
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		// values of the map
+		list := make([]layerValue, len(keys))
+		for i, key := range keys {
+			list[i] = layerValue{key: key, value: values[key]}
+		}
+
+		return list, nil
+	}}
+}
+
+// EnvironLayer is the snapshot of an environment in the form "key=value", e.g. os.Environ(),
+// with the priority.
+func EnvironLayer(environ []string, priority int) Layer {
+	return Layer{Name: "environment", Priority: priority, values: func(l *Loader) ([]layerValue, error) {
+
+		// values of the environment
+		values := make([]layerValue, 0, len(environ))
+
+		// iterating over variables
+		for _, variable := range environ {
+
+			// name and value of the variable, names starting with the equal sign are Windows drive variables
+			key, value, ok := strings.Cut(variable, "=")
+			if !ok || len(key) == 0 {
+				continue
+			}
+
+			// add value
+			values = append(values, layerValue{key: key, value: value})
+		}
+
+		return values, nil
+	}}
+}
+
+// Merge merges the layers in one pass, see Loader.Merge.
+func Merge(layers ...Layer) ([]Merged, error) {
+	return NewLoader().Merge(layers...)
+}
+
+// Merge merges the layers in one pass: the value of every key comes from the layer with the highest
+// priority, the later one on equal priorities, and the layers it shadows are listed for inspection.
+// Keys are returned in order of their first appearance in the layers. The environment is not changed.
+func (l *Loader) Merge(layers ...Layer) ([]Merged, error) {
+
+	// layers in order of precedence, the highest priority and the latest first
+	order := make([]int, len(layers))
+	for i := range order {
+		order[i] = len(layers) - 1 - i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return layers[order[i]].Priority > layers[order[j]].Priority
+	})
+
+	// values of the layers
+	values := make([][]layerValue, len(layers))
+
+	// keys in order of the first appearance
+	var keys []string
+
+	// merged values by key
+	merged := make(map[string]*Merged)
+
+	// keys having the winning value
+	won := make(map[string]bool)
+
+	// iterating over layers in order they are given
+	for i, layer := range layers {
+
+		// layer has no values
+		if layer.values == nil {
+			continue
+		}
+
+		// values of the layer
+		list, err := layer.values(l)
+		if err != nil {
+			return nil, fmt.Errorf("layer '%s': %s", layer.Name, err)
+		}
+		values[i] = list
+
+		// iterating over values
+		for _, v := range list {
+
+			// key is found for the first time
+			if _, ok := merged[l.indexKey(v.key)]; !ok {
+				merged[l.indexKey(v.key)] = &Merged{Key: v.key}
+				keys = append(keys, l.indexKey(v.key))
+			}
+		}
+	}
+
+	// iterating over layers in order of precedence
+	for _, i := range order {
+
+		// iterating over values of the layer
+		for _, v := range values[i] {
+
+			// key in the index
+			key := l.indexKey(v.key)
+
+			// merged value of the key
+			current := merged[key]
+
+			// provenance of the value
+			provenance := Provenance{Layer: layers[i].Name, Priority: layers[i].Priority, Line: v.line}
+
+			// value is shadowed by a layer of higher precedence
+			if won[key] {
+				current.Shadowed = append(current.Shadowed, provenance)
+				continue
+			}
+
+			// winning value
+			current.Value, current.From = v.value, provenance
+			won[key] = true
+		}
+	}
+
+	// merged values in order of keys
+	result := make([]Merged, len(keys))
+	for i, key := range keys {
+		result[i] = *merged[key]
+	}
+
+	return result, nil
+}
+
+// LoadLayers merges the layers and sets the merged values to the environment, see Loader.LoadLayers.
+func LoadLayers(layers ...Layer) ([]Merged, error) {
+	return std.LoadLayers(layers...)
+}
+
+// LoadLayers merges the layers as Merge does and sets the values that differ from the environment,
+// the value policy is checked before. Set values are written to the audit log and the outcome is
+// stored as the result of loading, as by Load. It returns the merged values with their provenance.
+func (l *Loader) LoadLayers(layers ...Layer) ([]Merged, error) {
+
+	// start of loading
+	start := time.Now()
+
+	// result of loading
+	result := &Result{}
+
+	// iterating over layers
+	for _, layer := range layers {
+
+		// add layer to loaded ones
+		result.Files = append(result.Files, layer.Name)
+
+		// forget statistics of earlier parsing
+		l.takeStats(layer.Name)
+	}
+
+	// merge layers
+	merged, err := l.Merge(layers...)
+	if err != nil {
+		return nil, err
+	}
+
+	// iterating over layers
+	for _, layer := range layers {
+
+		// add statistics of the layer
+		result.Stats.add(l.takeStats(layer.Name))
+	}
+
+	// number of merged keys
+	result.Stats.Keys = len(merged)
+
+	// iterating over merged values
+	for _, m := range merged {
+
+		// loaded key
+		entry := Entry{Key: m.Key, Value: m.Value, File: m.From.Layer, Line: m.From.Line, Status: StatusUnchanged}
+
+		// value is not set yet
+		if value, ok := l.lookupEnv(m.Key); !ok || value != m.Value {
+
+			// value is rejected by the policy
+			if l.policy != nil {
+				if err := l.policy(m.Key, m.Value); err != nil {
+					return nil, fmt.Errorf("[%s] key '%s': %s", m.From.Layer, m.Key, err)
+				}
+			}
+
+			// set key and value to environment variable
+			if err := l.setenv(m.Key, m.Value); err != nil {
+				return nil, fmt.Errorf("[%s] %s", m.From.Layer, err)
+			}
+
+			// update status
+			entry.Status = StatusSet
+
+			// write audit record
+			if err := l.writeAudit(entry); err != nil {
+				return nil, fmt.Errorf("[%s] can't write audit record: %s", m.From.Layer, err)
+			}
+		}
+
+		// add entry to result
+		result.Entries = append(result.Entries, entry)
+	}
+
+	// duration of loading
+	result.Stats.Duration = time.Since(start)
+
+	// store result
+	l.setResult(result)
+
+	return merged, nil
+}
@@ -0,0 +1,109 @@
+package envfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestMerge tests merging of layers by priority.
+func TestMerge(t *testing.T) {
+
+	// file layer
+	filename := createFile(t, "export HOST = file\nexport PORT = 8080\nLOCAL = local\n")
+
+	// merge layers
+	merged, err := Merge(
+		MapLayer("defaults", map[string]string{"HOST": "default", "NAME": "app"}, 0),
+		FileLayer(filename, 10),
+		EnvironLayer([]string{"HOST=env", "=C:=C:\\"}, 20),
+		MapLayer("flags", map[string]string{"PORT": "9090"}, 10),
+	)
+	if err != nil {
+		t.Fatalf("error merging layers: %v", err)
+	}
+
+	// expected values
+	expected := []Merged{
+		{Key: "HOST", Value: "env", From: Provenance{Layer: "environment", Priority: 20}, Shadowed: []Provenance{
+			{Layer: filename, Priority: 10, Line: 1},
+			{Layer: "defaults"},
+		}},
+		{Key: "NAME", Value: "app", From: Provenance{Layer: "defaults"}},
+		{Key: "PORT", Value: "9090", From: Provenance{Layer: "flags", Priority: 10}, Shadowed: []Provenance{
+			{Layer: filename, Priority: 10, Line: 2},
+		}},
+	}
+
+	// merged values are different from expected
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("expected %+v, got %+v", expected, merged)
+	}
+
+	// missing file
+	if _, err := Merge(FileLayer("missing.envfile", 0)); err == nil {
+		t.Error("file doesn't exist but merge didn't return an error")
+	}
+}
+
+// TestLoadLayers tests setting of merged values.
+func TestLoadLayers(t *testing.T) {
+
+	// environment of the loader
+	env := MapEnvironment{"HOST": "env"}
+
+	// load layers
+	if _, err := NewLoader(WithEnvironment(env)).LoadLayers(
+		EnvironLayer([]string{"HOST=env"}, 0),
+		MapLayer("overrides", map[string]string{"HOST": "override", "PORT": "80"}, 1),
+	); err != nil {
+		t.Fatalf("error loading layers: %v", err)
+	}
+
+	// values of the highest priority are set
+	if !reflect.DeepEqual(env, MapEnvironment{"HOST": "override", "PORT": "80"}) {
+		t.Errorf("unexpected environment %v", env)
+	}
+}
+
+// TestLoadLayersResult tests the result and the audit records of loading layers.
+func TestLoadLayersResult(t *testing.T) {
+
+	// audit log
+	var buffer bytes.Buffer
+
+	// loader with a separate environment
+	loader := NewLoader(WithEnvironment(MapEnvironment{"HOST": "env"}), WithAuditLog(&buffer))
+
+	// load layers
+	if _, err := loader.LoadLayers(
+		EnvironLayer([]string{"HOST=env"}, 0),
+		MapLayer("overrides", map[string]string{"PORT": "80"}, 1),
+	); err != nil {
+		t.Fatalf("error loading layers: %v", err)
+	}
+
+	// result of loading
+	result := loader.Result()
+	if result == nil {
+		t.Fatal("expected result of loading layers")
+	}
+
+	// expected entries
+	expected := []Entry{
+		{Key: "HOST", Value: "env", File: "environment", Status: StatusUnchanged},
+		{Key: "PORT", Value: "80", File: "overrides", Status: StatusSet},
+	}
+
+	// entries are different from expected
+	if !reflect.DeepEqual(result.Entries, expected) || !reflect.DeepEqual(result.Files, []string{"environment", "overrides"}) {
+		t.Errorf("expected entries %+v of environment and overrides, got %+v of %v", expected, result.Entries, result.Files)
+	}
+
+	// audit record of the set value
+	var record AuditRecord
+	if err := json.Unmarshal(buffer.Bytes(), &record); err != nil || record.Key != "PORT" || record.File != "overrides" {
+		t.Errorf("expected one audit record of PORT from overrides, got %q (%v)", buffer.String(), err)
+	}
+}
@@ -0,0 +1,15 @@
+package envfile
+
+// WithLegacySyntax parses files the way the first version of the module did, so existing files
+// keep their meaning while they are reviewed for the newer syntax: quotes are part of the value,
+// lines after KEY = <<EOF are ordinary lines, include, source, [profile] and #if lines are not directives, a trailing
+// backslash and \" are kept, raw, ?= and KEY[] are parts of invalid key names, and { KEY:-default }
+// and { KEY:?message } are references to variables named with the whole text between the curly braces. Inline comments, $KEY references and command
+// substitution are disabled even if their options are set.
+func WithLegacySyntax(enabled bool) Option {
+	return func(l *Loader) {
+
+		// set legacy syntax status
+		l.legacy = enabled
+	}
+}
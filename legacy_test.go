@@ -0,0 +1,57 @@
+package envfile
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLegacySyntax tests parsing of files the way the first version of the module did.
+func TestLegacySyntax(t *testing.T) {
+
+	// file with quotes, a trailing backslash, an unclosed multi-line value, an inline comment and an escaped quote
+	filename := createFile(t, "QUOTED = \"value\"\nSLASH = a\\\nHERE = <<EOF\nBODY = x # comment\nESCAPED = a\\\"b\n")
+
+	// parse file with the legacy syntax, the inline comments option is ignored
+	payloads, err := NewLoader(WithLegacySyntax(true), WithInlineComments(true)).Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// values are taken as they are written
+	expected := map[string]string{"QUOTED": `"value"`, "SLASH": `a\`, "HERE": "<<EOF", "BODY": "x # comment", "ESCAPED": `a\"b`}
+
+	// iterating over payloads
+	for _, payload := range payloads {
+
+		// value is different from expected
+		if payload.Value != expected[payload.Key] {
+			t.Errorf("expected %s to be '%s', got '%s'", payload.Key, expected[payload.Key], payload.Value)
+		}
+	}
+
+	// all keys are parsed
+	if len(payloads) != len(expected) {
+		t.Errorf("expected %d keys, got %d", len(expected), len(payloads))
+	}
+
+	// files rejected by the legacy syntax
+	files := map[string]string{
+		"include base.envfile\n":               "can't split line into key and value",
+		"URL = { LEGACY_MISSING:-fallback }\n": "variable 'LEGACY_MISSING:-fallback' does not exist",
+		"raw KEY = a\n":                        "invalid key name 'raw KEY'",
+		"KEY ?= a\n":                           "invalid key name 'KEY ?'",
+		"KEY[] = a\nKEY[] = b\n":               "invalid key name 'KEY[]'",
+	}
+
+	// iterating over files
+	for content, message := range files {
+
+		// parse file with the legacy syntax
+		_, err := NewLoader(WithLegacySyntax(true)).Parse(createFile(t, content))
+
+		// error is different from expected
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("expected error '%s' for %q, got %v", message, content, err)
+		}
+	}
+}
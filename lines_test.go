@@ -0,0 +1,53 @@
+package envfile
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseCRLF tests that carriage returns never end up in values.
+func TestParseCRLF(t *testing.T) {
+
+	// iterating over dialects
+	for _, dialect := range []Dialect{DialectDefault, DialectKubernetes} {
+
+		// parse file with windows line endings
+		payloads, err := NewLoader(WithDialect(dialect)).Parse("test.crlf.envfile")
+		if err != nil {
+			t.Fatalf("error parsing env file: %v", err)
+		}
+
+		// number of payloads is different from expected
+		if len(payloads) != 4 {
+			t.Fatalf("expected 4 payloads, got %d", len(payloads))
+		}
+
+		// iteration over payloads
+		for _, payload := range payloads {
+
+			// value contains carriage return
+			if strings.Contains(payload.Value, "\r") {
+				t.Errorf("value of %s contains carriage return: %q", payload.Key, payload.Value)
+			}
+		}
+	}
+}
+
+// TestScanLines tests splitting lines regardless of the line ending.
+func TestScanLines(t *testing.T) {
+
+	// lines of file with mixed line endings
+	lines, err := readLines("test.crlf.envfile")
+	if err != nil {
+		t.Fatalf("error reading env file: %v", err)
+	}
+
+	// iterating over a list of lines
+	for i, line := range lines {
+
+		// line ends with carriage return
+		if strings.HasSuffix(line, "\r") {
+			t.Errorf("line %d ends with carriage return: %q", i+1, line)
+		}
+	}
+}
@@ -0,0 +1,642 @@
+package envfile
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// Finding is a problem found by a lint rule.
+type Finding struct {
+
+	// rule identifier
+	Rule string
+
+	// file name
+	File string
+
+	// line number in file
+	Line int
+
+	// key
+	Key string
+
+	// message
+	Message string
+}
+
+// String returns the finding in the form "file:line: message (rule)".
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s (%s)", f.File, f.Line, f.Message, f.Rule)
+}
+
+// LintFile is a file checked by lint rules.
+type LintFile struct {
+
+	// file name
+	Name string
+
+	// syntax of the file
+	Dialect Dialect
+
+	// lines of the file as they are written
+	Lines []string
+
+	// parsed payloads
+	Payloads []Payload
+
+	// the way lines and references are written
+	syntax parser.Syntax
+
+	// naming convention of exported and overloaded keys
+	keyPattern *regexp.Regexp
+
+	// loader of the linter, related files are read with its dialect, delimiters and profile
+	loader *Loader
+}
+
+// Rule is a lint rule.
+type Rule struct {
+
+	// rule identifier, used to enable, disable and suppress the rule
+	ID string
+
+	// description
+	Description string
+
+	// rule is disabled unless explicitly enabled
+	Optional bool
+
+	// check function
+	Check func(file *LintFile) []Finding
+}
+
+// rules is a registry of lint rules in order of registration.
+var rules []Rule
+
+// RegisterRule adds the rule to the registry used by new linters.
+func RegisterRule(rule Rule) {
+
+	// add rule to registry
+	rules = append(rules, rule)
+}
+
+// Linter checks files with environment variables against lint rules.
+type Linter struct {
+
+	// loader used to parse files
+	loader *Loader
+
+	// lint rules
+	rules []Rule
+
+	// rule status by identifier
+	enabled map[string]bool
+}
+
+// NewLinter creates a linter with the registered rules, parsing files with the given options.
+func NewLinter(options ...Option) *Linter {
+
+	// linter
+	linter := &Linter{
+		loader:  NewLoader(options...),
+		enabled: make(map[string]bool),
+	}
+
+	// iterating over the registry of lint rules
+	for _, rule := range rules {
+
+		// add rule to linter
+		linter.Register(rule)
+	}
+
+	return linter
+}
+
+// Register adds the custom rule to the linter.
+func (l *Linter) Register(rule Rule) {
+
+	// add rule to list
+	l.rules = append(l.rules, rule)
+
+	// set rule status
+	l.enabled[rule.ID] = !rule.Optional
+}
+
+// Enable enables the rules with the given identifiers.
+func (l *Linter) Enable(ids ...string) {
+
+	// iterating over a list of identifiers
+	for _, id := range ids {
+
+		// enable rule
+		l.enabled[id] = true
+	}
+}
+
+// Disable disables the rules with the given identifiers.
+func (l *Linter) Disable(ids ...string) {
+
+	// iterating over a list of identifiers
+	for _, id := range ids {
+
+		// disable rule
+		l.enabled[id] = false
+	}
+}
+
+// Lint checks files with environment variables against the enabled rules.
+func (l *Linter) Lint(filenames ...string) ([]Finding, error) {
+
+	// findings list
+	var findings []Finding
+
+	// iterating over a list of filenames
+	for _, filename := range filenames {
+
+		// parse file
+		payloads, err := l.loader.Parse(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		// read lines of file
+		lines, err := readLines(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		// keys of included files are checked when the included files are linted
+		payloads = slices.DeleteFunc(payloads, func(payload Payload) bool { return len(payload.File) > 0 })
+
+		// file checked by lint rules
+		file := &LintFile{
+			Name:       filename,
+			Dialect:    l.loader.dialect,
+			Lines:      lines,
+			Payloads:   payloads,
+			syntax:     l.loader.syntax(),
+			keyPattern: l.loader.namingPattern(),
+			loader:     l.loader,
+		}
+
+		// iterating over a list of rules
+		for _, rule := range l.rules {
+
+			// rule is disabled
+			if !l.enabled[rule.ID] {
+				continue
+			}
+
+			// iterating over rule findings
+			for _, finding := range rule.Check(file) {
+
+				// set rule identifier
+				finding.Rule = rule.ID
+
+				// set file name
+				finding.File = filename
+
+				// add finding to list
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+// Lint checks files with environment variables against the registered rules.
+func Lint(filenames ...string) ([]Finding, error) {
+	return NewLinter().Lint(filenames...)
+}
+
+// readLines reads lines of the file.
+func readLines(filename string) ([]string, error) {
+
+	// open file
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// deferred file close
+	defer file.Close()
+
+	// lines list
+	var lines []string
+
+	// line by line file reading
+	scanner := bufio.NewScanner(file)
+
+	// split lines regardless of the line ending
+	scanner.Split(parser.ScanLines)
+
+	// iterate through the lines of the file
+	for scanner.Scan() {
+
+		// add line to list
+		lines = append(lines, scanner.Text())
+	}
+
+	return lines, scanner.Err()
+}
+
+var (
+
+	// key naming convention
+	screamingSnakeCase = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+)
+
+func init() {
+
+	// keys are sorted within blocks of consecutive lines
+	RegisterRule(Rule{
+		ID:          "ordered-keys",
+		Description: "keys are sorted alphabetically within blocks of consecutive lines",
+		Optional:    true,
+		Check: func(file *LintFile) []Finding {
+
+			// findings list
+			var findings []Finding
+
+			// iterating over a list of payloads
+			for i := 1; i < len(file.Payloads); i++ {
+
+				// previous payload
+				previous := file.Payloads[i-1]
+
+				// current payload
+				current := file.Payloads[i]
+
+				// payloads are in the same block and not sorted
+				if current.Line == previous.Line+1 && current.Key < previous.Key {
+					findings = append(findings, Finding{
+						Line:    current.Line,
+						Key:     current.Key,
+						Message: fmt.Sprintf("key '%s' should go before '%s'", current.Key, previous.Key),
+					})
+				}
+			}
+
+			return findings
+		},
+	})
+
+	// keys are written in screaming snake case
+	RegisterRule(Rule{
+		ID:          "naming-convention",
+		Description: "exported and overloaded keys are written in SCREAMING_SNAKE_CASE or match WithKeyPattern",
+		Check: func(file *LintFile) []Finding {
+
+			// findings list
+			var findings []Finding
+
+			// naming convention
+			pattern := file.keyPattern
+			if pattern == nil {
+				pattern = screamingSnakeCase
+			}
+
+			// iterating over a list of payloads
+			for _, payload := range file.Payloads {
+
+				// local key is not checked
+				if !payload.Export && !payload.Overload {
+					continue
+				}
+
+				// key name does not follow the convention
+				if !pattern.MatchString(payload.Key) {
+
+					// message of the default convention
+					message := fmt.Sprintf("key '%s' is not in SCREAMING_SNAKE_CASE", payload.Key)
+
+					// message of the custom convention
+					if pattern != screamingSnakeCase {
+						message = fmt.Sprintf("key '%s' does not match the naming convention '%s'", payload.Key, pattern)
+					}
+
+					// add finding to list
+					findings = append(findings, Finding{
+						Line:    payload.Line,
+						Key:     payload.Key,
+						Message: message,
+					})
+				}
+			}
+
+			return findings
+		},
+	})
+
+	// no spaces around the equal sign
+	RegisterRule(Rule{
+		ID:          "spacing",
+		Description: "no spaces around the equal sign, as docker and compose env files require",
+		Optional:    true,
+		Check: func(file *LintFile) []Finding {
+
+			// findings list
+			var findings []Finding
+
+			// iterating over a list of payloads
+			for _, payload := range file.Payloads {
+
+				// line as it is written in file
+				current := file.Lines[payload.Line-1]
+
+				// position of the equal sign
+				position := strings.Index(current, "=")
+
+				// equal sign is missing
+				if position < 0 {
+					continue
+				}
+
+				// text before the equal sign
+				before := current[:position]
+
+				// text after the equal sign
+				after := current[position+1:]
+
+				// space before or after the equal sign
+				if strings.TrimRight(before, " \t") != before || strings.TrimLeft(after, " \t") != after {
+					findings = append(findings, Finding{
+						Line:    payload.Line,
+						Key:     payload.Key,
+						Message: fmt.Sprintf("key '%s' has spaces around the equal sign", payload.Key),
+					})
+				}
+			}
+
+			return findings
+		},
+	})
+
+	// local keys are referenced
+	RegisterRule(Rule{
+		ID:          "unused-variable",
+		Description: "keys that are not exported are referenced by other values",
+		Check: func(file *LintFile) []Finding {
+
+			// findings list
+			var findings []Finding
+
+			// referenced variables
+			referenced := make(map[string]bool)
+
+			// iterating over references by referencing key
+			for _, refs := range file.syntax.References(file.Payloads) {
+
+				// iterating over a list of references
+				for _, ref := range refs {
+
+					// add variable, without the path to a JSON field
+					referenced[strings.SplitN(ref.Name, ".", 2)[0]] = true
+				}
+			}
+
+			// iterating over a list of payloads
+			for _, payload := range file.Payloads {
+
+				// local key is not referenced
+				if !payload.Export && !payload.Overload && !referenced[payload.Key] {
+					findings = append(findings, Finding{
+						Line:    payload.Line,
+						Key:     payload.Key,
+						Message: fmt.Sprintf("key '%s' is not exported and never referenced", payload.Key),
+					})
+				}
+			}
+
+			return findings
+		},
+	})
+
+	// values are not empty
+	RegisterRule(Rule{
+		ID:          "empty-value",
+		Description: "values are not empty",
+		Check: func(file *LintFile) []Finding {
+
+			// findings list
+			var findings []Finding
+
+			// iterating over a list of payloads
+			for _, payload := range file.Payloads {
+
+				// value is empty
+				if len(payload.Value) == 0 {
+					findings = append(findings, Finding{
+						Line:    payload.Line,
+						Key:     payload.Key,
+						Message: fmt.Sprintf("key '%s' has an empty value", payload.Key),
+					})
+				}
+			}
+
+			return findings
+		},
+	})
+
+	// secrets are not written in plain text
+	RegisterRule(Rule{
+		ID:          "plaintext-secret",
+		Description: "secrets are taken from other variables instead of being written in plain text",
+		Check: func(file *LintFile) []Finding {
+
+			// findings list
+			var findings []Finding
+
+			// iterating over a list of payloads
+			for _, payload := range file.Payloads {
+
+				// key does not hold a secret or the value is empty
+				if !isSensitive(payload.Key) || len(payload.Value) == 0 {
+					continue
+				}
+
+				// secret is taken from another variable, written with the references of the file syntax
+				if !payload.Literal && len(parser.ScanReferences(file.syntax, payload.Raw, 0)) > 0 {
+					continue
+				}
+
+				// add finding to list
+				findings = append(findings, Finding{
+					Line:    payload.Line,
+					Key:     payload.Key,
+					Message: fmt.Sprintf("key '%s' holds a secret in plain text", payload.Key),
+				})
+			}
+
+			return findings
+		},
+	})
+
+	// values are not copied or left as placeholders
+	RegisterRule(Rule{
+		ID:          "duplicate-value",
+		Description: "distinct keys do not share a secret value and values differ from their key names",
+		Check: func(file *LintFile) []Finding {
+
+			// findings list
+			var findings []Finding
+
+			// first key holding a secret by its value
+			secrets := make(map[string]Payload)
+
+			// iterating over a list of payloads
+			for _, payload := range file.Payloads {
+
+				// empty value
+				if len(payload.Value) == 0 {
+					continue
+				}
+
+				// value taken from other variables shares their value on purpose, quoted and escaped values are compared resolved
+				if !payload.Literal && len(parser.ScanReferences(file.syntax, payload.Raw, 0)) > 0 {
+					continue
+				}
+
+				// placeholder that was never filled in
+				if payload.Value == payload.Key {
+					findings = append(findings, Finding{
+						Line:    payload.Line,
+						Key:     payload.Key,
+						Message: fmt.Sprintf("key '%s' has its own name as the value, it looks like a placeholder", payload.Key),
+					})
+					continue
+				}
+
+				// value does not look like a secret
+				if !IsSecret(payload.Key, payload.Value) {
+					continue
+				}
+
+				// secret is already held by another key
+				if first, ok := secrets[payload.Value]; ok {
+					findings = append(findings, Finding{
+						Line:    payload.Line,
+						Key:     payload.Key,
+						Message: fmt.Sprintf("key '%s' has the same secret value as '%s' on line %d", payload.Key, first.Key, first.Line),
+					})
+					continue
+				}
+
+				// remember key holding the secret
+				secrets[payload.Value] = payload
+			}
+
+			return findings
+		},
+	})
+
+	// escape sequences are special characters
+	RegisterRule(Rule{
+		ID:          "unknown-escape",
+		Description: "escape sequences in values are \\n, \\t, \\\\ or escaped curly braces",
+		Check: func(file *LintFile) []Finding {
+
+			// findings list
+			var findings []Finding
+
+			// backslash escapes are not processed
+			if !file.syntax.Escapes() {
+				return nil
+			}
+
+			// iterating over a list of payloads
+			for _, payload := range file.Payloads {
+
+				// raw value is taken as it is written
+				if payload.Literal {
+					continue
+				}
+
+				// iterating over unknown escape sequences
+				for _, sequence := range parser.UnknownEscapes(file.syntax, payload.Raw) {
+
+					// add finding to list
+					findings = append(findings, Finding{
+						Line:    payload.Line,
+						Key:     payload.Key,
+						Message: fmt.Sprintf("key '%s' has an unknown escape sequence '%s', it is kept as it is written", payload.Key, sequence),
+					})
+				}
+			}
+
+			return findings
+		},
+	})
+
+	// values of URL keys are valid URLs
+	RegisterRule(Rule{
+		ID:          "invalid-url",
+		Description: "values of keys named *_URL or *_URI are absolute URLs",
+		Check: func(file *LintFile) []Finding {
+
+			// findings list
+			var findings []Finding
+
+			// iterating over a list of payloads
+			for _, payload := range file.Payloads {
+
+				// key does not hold a URL or the value is empty
+				if !parser.IsURLKey(payload.Key) || len(payload.Value) == 0 {
+					continue
+				}
+
+				// value is not a valid URL
+				if _, err := parser.ParseURL(payload.Value); err != nil {
+					findings = append(findings, Finding{
+						Line:    payload.Line,
+						Key:     payload.Key,
+						Message: fmt.Sprintf("key '%s' is not a valid URL: %s", payload.Key, err),
+					})
+				}
+			}
+
+			return findings
+		},
+	})
+
+	// keys are documented in the example file
+	RegisterRule(Rule{
+		ID:          "example-parity",
+		Description: "keys are present in the example file next to the checked one",
+		Optional:    true,
+		Check: func(file *LintFile) []Finding {
+
+			// findings list
+			var findings []Finding
+
+			// example file name
+			example := file.Name + ".example"
+
+			// parse example file the way the checked file is parsed
+			payloads, err := file.loader.read(example)
+			if err != nil {
+				return []Finding{{Message: fmt.Sprintf("can't read example file: %s", err)}}
+			}
+
+			// iterating over payloads missing in the example file
+			for _, payload := range missingKeys(file.Payloads, payloads) {
+
+				// add finding to list
+				findings = append(findings, Finding{
+					Line:    payload.Line,
+					Key:     payload.Key,
+					Message: fmt.Sprintf("key '%s' is missing in '%s'", payload.Key, example),
+				})
+			}
+
+			return findings
+		},
+	})
+}
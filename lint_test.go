@@ -0,0 +1,287 @@
+package envfile
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestLint tests checking files against lint rules.
+func TestLint(t *testing.T) {
+
+	// file content
+	filename := createFile(t, `
+export B_KEY = value
+export A_KEY =
+export lower_key = value
+export DB_PASSWORD = qwerty
+export API_TOKEN = { A_KEY }
+`)
+
+	// linter with optional rule enabled and default rule disabled
+	linter := NewLinter()
+	linter.Enable("ordered-keys")
+	linter.Disable("naming-convention")
+
+	// custom rule
+	linter.Register(Rule{
+		ID: "custom",
+		Check: func(file *LintFile) []Finding {
+			return []Finding{{Line: 1, Message: "custom finding"}}
+		},
+	})
+
+	// check file
+	findings, err := linter.Lint(filename)
+	if err != nil {
+		t.Fatalf("error linting env file: %v", err)
+	}
+
+	// expected number of findings by rule
+	expected := map[string]int{
+		"ordered-keys":     3,
+		"empty-value":      2,
+		"plaintext-secret": 1,
+		"custom":           1,
+	}
+
+	// number of findings by rule
+	actual := make(map[string]int)
+
+	// iterating over a list of findings
+	for _, finding := range findings {
+
+		// file name is not set
+		if finding.File != filename {
+			t.Errorf("expected finding file to be %s, got %s", filename, finding.File)
+		}
+
+		// increase number of findings
+		actual[finding.Rule]++
+	}
+
+	// iterating over expected number of findings
+	for rule, count := range expected {
+
+		// number of findings is different from expected
+		if actual[rule] != count {
+			t.Errorf("expected %d findings of rule %s, got %d", count, rule, actual[rule])
+		}
+	}
+
+	// disabled rule reported findings
+	if actual["naming-convention"] != 0 {
+		t.Errorf("disabled rule naming-convention reported findings")
+	}
+}
+
+// TestLintUnusedVariable tests detection of unused local keys.
+func TestLintUnusedVariable(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "USED = value\nUNUSED = value\nexport KEY = { USED }\n")
+
+	// check file
+	findings, err := Lint(filename)
+	if err != nil {
+		t.Fatalf("error linting env file: %v", err)
+	}
+
+	// unused keys
+	var unused []Finding
+
+	// iterating over a list of findings
+	for _, finding := range findings {
+
+		// finding of the rule
+		if finding.Rule == "unused-variable" {
+			unused = append(unused, finding)
+		}
+	}
+
+	// unused key is different from expected
+	if len(unused) != 1 || unused[0].Key != "UNUSED" || unused[0].Line != 2 {
+		t.Errorf("expected UNUSED on line 2 to be flagged, got %v", unused)
+	}
+}
+
+// TestLintExampleParity tests that the example file is read with the options of the linter.
+func TestLintExampleParity(t *testing.T) {
+
+	// file and example file with keys of the profile section
+	filename := createFile(t, "[prod]\nKEY = value\nOTHER = value\n")
+	if err := ioutil.WriteFile(filename+".example", []byte("[prod]\nKEY =\n"), 0644); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+
+	// deferred removal of the example file
+	defer os.Remove(filename + ".example")
+
+	// linter with the profile
+	linter := NewLinter(WithProfile("prod"))
+	linter.Enable("example-parity")
+
+	// check file
+	findings, err := linter.Lint(filename)
+	if err != nil {
+		t.Fatalf("error linting env file: %v", err)
+	}
+
+	// missing keys
+	var missing []string
+
+	// iterating over a list of findings
+	for _, finding := range findings {
+
+		// finding of the rule
+		if finding.Rule == "example-parity" {
+			missing = append(missing, finding.Key)
+		}
+	}
+
+	// missing keys are different from expected
+	if strings.Join(missing, ",") != "OTHER" {
+		t.Errorf("expected OTHER to be missing in the example file, got %v", missing)
+	}
+}
+
+// TestLintUnknownEscape tests detection of unknown escape sequences.
+func TestLintUnknownEscape(t *testing.T) {
+
+	// file content
+	filename := createFile(t, `
+PATTERN = ^\d+\.\w+$
+KNOWN = first\nsecond\t\\d
+raw RAW = ^\d+$
+`)
+
+	// check file
+	findings, err := Lint(filename)
+	if err != nil {
+		t.Fatalf("error linting env file: %v", err)
+	}
+
+	// sequences of findings
+	var sequences []string
+
+	// iterating over a list of findings
+	for _, finding := range findings {
+
+		// finding of the rule
+		if finding.Rule == "unknown-escape" {
+			sequences = append(sequences, finding.Message)
+		}
+	}
+
+	// expected findings
+	expected := []string{
+		`key 'PATTERN' has an unknown escape sequence '\d', it is kept as it is written`,
+		`key 'PATTERN' has an unknown escape sequence '\.', it is kept as it is written`,
+		`key 'PATTERN' has an unknown escape sequence '\w', it is kept as it is written`,
+	}
+
+	// findings are different from expected
+	if strings.Join(sequences, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected findings %q, got %q", expected, sequences)
+	}
+}
+
+// TestLintDuplicateValue tests detection of copied secrets and placeholder values.
+func TestLintDuplicateValue(t *testing.T) {
+
+	// file content
+	filename := createFile(t, `
+DB_PASSWORD = qwerty
+CACHE_PASSWORD = qwerty
+export API_TOKEN = { DB_PASSWORD }
+HOST = localhost
+BIND = localhost
+SESSION_KEY = 9f8e7d6c5b4a39281716afbecd
+SIGNING_KEY = 9f8e7d6c5b4a39281716afbecd
+export STRIPE_API_KEY = STRIPE_API_KEY
+QUEUE_PASSWORD = "qwerty"
+MAIL_PASSWORD = "p\\w0rd-5ecret"
+SMTP_PASSWORD = p\\w0rd-5ecret
+export GITHUB_TOKEN = "GITHUB_TOKEN"
+`)
+
+	// check file
+	findings, err := Lint(filename)
+	if err != nil {
+		t.Fatalf("error linting env file: %v", err)
+	}
+
+	// messages of findings
+	var messages []string
+
+	// iterating over a list of findings
+	for _, finding := range findings {
+
+		// finding of the rule
+		if finding.Rule == "duplicate-value" {
+			messages = append(messages, finding.Message)
+		}
+	}
+
+	// expected findings
+	expected := []string{
+		"key 'CACHE_PASSWORD' has the same secret value as 'DB_PASSWORD' on line 2",
+		"key 'SIGNING_KEY' has the same secret value as 'SESSION_KEY' on line 7",
+		"key 'STRIPE_API_KEY' has its own name as the value, it looks like a placeholder",
+		"key 'QUEUE_PASSWORD' has the same secret value as 'DB_PASSWORD' on line 2",
+		"key 'SMTP_PASSWORD' has the same secret value as 'MAIL_PASSWORD' on line 11",
+		"key 'GITHUB_TOKEN' has its own name as the value, it looks like a placeholder",
+	}
+
+	// findings are different from expected
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected findings %q, got %q", expected, messages)
+	}
+}
+
+// TestLintPlaintextSecretReferences tests that secrets taken from references of the file syntax are not reported.
+func TestLintPlaintextSecretReferences(t *testing.T) {
+
+	// environment with the referenced secrets
+	env := WithEnvironment(MapEnvironment{"VAULT_TOKEN": "secret", "DB_SECRET": "secret"})
+
+	// files with secrets taken from other variables by the options of the linter
+	cases := []struct {
+		options []Option
+		content string
+	}{
+		{[]Option{env, WithDollarReferences(true)}, "export API_TOKEN = $VAULT_TOKEN\nexport DB_PASSWORD = ${DB_SECRET}\n"},
+		{[]Option{env, WithDelimiters("%", "%")}, "export API_TOKEN = %VAULT_TOKEN%\n"},
+		{[]Option{env}, "export API_TOKEN = '{ VAULT_TOKEN }'\n"},
+	}
+
+	// expected number of findings by case
+	expected := []int{0, 0, 1}
+
+	// iterating over cases
+	for i, c := range cases {
+
+		// linter with the options
+		linter := NewLinter(c.options...)
+
+		// check file
+		findings, err := linter.Lint(createFile(t, c.content))
+		if err != nil {
+			t.Fatalf("error linting env file: %v", err)
+		}
+
+		// number of findings of the rule
+		count := 0
+		for _, finding := range findings {
+			if finding.Rule == "plaintext-secret" {
+				count++
+			}
+		}
+
+		// number of findings is different from expected
+		if count != expected[i] {
+			t.Errorf("%q: expected %d plaintext-secret findings, got %d", c.content, expected[i], count)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package envfile
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// LiveValues holds the current values of files that are parsed again when they change.
+// Any number of goroutines, e.g. request handlers, read them without locks, while Reload
+// and Store publish new values at once: a reader sees either the previous or the new values,
+// never a mix of them.
+type LiveValues struct {
+
+	// loader parsing the files
+	loader *Loader
+
+	// files in precedence order
+	filenames []string
+
+	// current values, replaced as a whole
+	current atomic.Pointer[Values]
+
+	// receivers of changes of keys
+	subscriptions []*subscription
+
+	// subscriptions access synchronization
+	mu sync.Mutex
+}
+
+// ParseLiveValues parses files given in precedence order into live values, see Loader.ParseLiveValues.
+func ParseLiveValues(filenames ...string) (*LiveValues, error) {
+	return NewLoader().ParseLiveValues(filenames...)
+}
+
+// ParseLiveValues parses files given in precedence order into live values, the default file is parsed
+// if no file names are given. Reload parses the same files again. The environment is not changed.
+func (l *Loader) ParseLiveValues(filenames ...string) (*LiveValues, error) {
+
+	// live values
+	live := &LiveValues{loader: l, filenames: filenames}
+
+	// parse files
+	if err := live.Reload(); err != nil {
+		return nil, err
+	}
+
+	return live, nil
+}
+
+// Values returns the current values, they are never changed and can be kept as a consistent snapshot.
+func (v *LiveValues) Values() Values {
+	return *v.current.Load()
+}
+
+// Lookup returns the current value of the key and whether it exists in the files or in the environment.
+func (v *LiveValues) Lookup(key string) (string, bool) {
+	return v.Values().Lookup(key)
+}
+
+// GetWithSource returns the current value of the key with the place it comes from and whether it exists.
+func (v *LiveValues) GetWithSource(key string) (string, Source, bool) {
+	return v.Values().GetWithSource(key)
+}
+
+// Reload parses the files again and publishes the new values, e.g. when a watcher reports a change.
+// The current values are kept if the files can't be parsed.
+func (v *LiveValues) Reload() error {
+
+	// parse files
+	values, err := v.loader.ParseValues(v.filenames...)
+	if err != nil {
+		return err
+	}
+
+	// publish values
+	v.Store(values)
+
+	return nil
+}
+
+// Store publishes the values, e.g. parsed by the caller from other sources, and delivers
+// changes of keys to the subscriptions.
+func (v *LiveValues) Store(values Values) {
+
+	// publish values
+	previous := v.current.Swap(&values)
+
+	// first values have no changes
+	if previous == nil {
+		return
+	}
+
+	// deliver changes
+	v.notify(*previous, values)
+}
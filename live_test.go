@@ -0,0 +1,122 @@
+package envfile
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+)
+
+// TestLiveValues tests reading of values while they are reloaded.
+func TestLiveValues(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "export LIVE_HOST = first\nexport LIVE_PORT = 1\n")
+
+	// live values
+	live, err := ParseLiveValues(filename)
+	if err != nil {
+		t.Fatalf("error parsing live values: %v", err)
+	}
+
+	// snapshot of the first values
+	snapshot := live.Values()
+
+	// readers running while the file is reloaded
+	var wg sync.WaitGroup
+
+	// iterating over readers
+	for i := 0; i < 4; i++ {
+
+		// start reader
+		wg.Add(1)
+		go func() {
+
+			// deferred reader done
+			defer wg.Done()
+
+			// iterating over reads
+			for j := 0; j < 1000; j++ {
+
+				// values of one reload
+				values := live.Values()
+				host, _ := values.Lookup("LIVE_HOST")
+				port, _ := values.Lookup("LIVE_PORT")
+
+				// values of different reloads are mixed
+				if host == "first" && port != "1" || host == "second" && port != "2" {
+					t.Errorf("inconsistent values: %s, %s", host, port)
+					return
+				}
+			}
+		}()
+	}
+
+	// change the file
+	if err := ioutil.WriteFile(filename, []byte("export LIVE_HOST = second\nexport LIVE_PORT = 2\n"), 0644); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+
+	// reload values
+	if err := live.Reload(); err != nil {
+		t.Fatalf("error reloading values: %v", err)
+	}
+
+	// wait for readers
+	wg.Wait()
+
+	// new values are published
+	if host, source, _ := live.GetWithSource("LIVE_HOST"); host != "second" || source.Line != 1 {
+		t.Errorf("expected second from line 1, got %s from %s", host, source)
+	}
+
+	// snapshot is not changed
+	if host, _ := snapshot.Lookup("LIVE_HOST"); host != "first" {
+		t.Errorf("expected snapshot to keep first, got %s", host)
+	}
+
+	// break the file
+	if err := ioutil.WriteFile(filename, []byte("invalid line\n"), 0644); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+
+	// values are kept when the file can't be parsed
+	if err := live.Reload(); err == nil {
+		t.Error("file is invalid but reload didn't return an error")
+	}
+	if host, _ := live.Lookup("LIVE_HOST"); host != "second" {
+		t.Errorf("expected values to be kept, got %s", host)
+	}
+}
+
+// TestLiveValuesCaseInsensitive tests lookups of live values regardless of case after a reload.
+func TestLiveValuesCaseInsensitive(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "export Live_Host = first\n")
+
+	// live values of a loader with case-insensitive keys
+	live, err := NewLoader(WithCaseInsensitiveKeys(true), WithEnvironment(MapEnvironment{})).ParseLiveValues(filename)
+	if err != nil {
+		t.Fatalf("error parsing live values: %v", err)
+	}
+
+	// key is found regardless of case
+	if host, ok := live.Lookup("LIVE_HOST"); !ok || host != "first" {
+		t.Errorf("expected first, got %s", host)
+	}
+
+	// change the file, writing the key in another case
+	if err := ioutil.WriteFile(filename, []byte("export LIVE_HOST = second\n"), 0644); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+
+	// reload values
+	if err := live.Reload(); err != nil {
+		t.Fatalf("error reloading values: %v", err)
+	}
+
+	// new value is found regardless of case
+	if host, source, ok := live.GetWithSource("live_host"); !ok || host != "second" || source.Line != 1 {
+		t.Errorf("expected second from line 1, got %s from %s", host, source)
+	}
+}
@@ -0,0 +1,211 @@
+package envfile
+
+import (
+	"crypto/ed25519"
+	"io"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// Loader loads files with environment variables according to its options.
+type Loader struct {
+
+	// syntax of the files
+	dialect Dialect
+
+	// custom opening delimiter of references
+	open string
+
+	// custom closing delimiter of references
+	close string
+
+	// way literal curly braces are written
+	braces BraceMode
+
+	// value prefixes status
+	sources bool
+
+	// list items are joined into one value
+	join bool
+
+	// separator of joined list items
+	separator string
+
+	// behavior for references to variables that do not exist
+	undefinedPolicy UndefinedPolicy
+
+	// values of variables that do not exist
+	undefinedFallback func(variable string) (string, error)
+
+	// providers of secrets by URI scheme
+	providers map[string]SecretProvider
+
+	// remote services by scheme
+	remotes map[string]Remote
+
+	// audit log of set variables
+	audit io.Writer
+
+	// key verifying signatures of files, verification is disabled if nil
+	publicKey ed25519.PublicKey
+
+	// identifier of the minisign key, signatures are not matched by identifier if nil
+	keyID []byte
+
+	// check of values before they are set
+	policy func(key, value string) error
+
+	// application name for per-user default files
+	app string
+
+	// backend of environment variables, the environment of the process if nil
+	env Environment
+
+	// keys differing only in case are the same key
+	caseInsensitive bool
+
+	// naming convention of exported and overloaded keys, SCREAMING_SNAKE_CASE if nil
+	keyPattern *regexp.Regexp
+
+	// values referenced as { data.name }
+	data map[string]interface{}
+
+	// chains of transformers of values by key pattern
+	transforms []transform
+
+	// parsing goes on after a line with a problem
+	continueOnError bool
+
+	// comments after values are stripped
+	inlineComments bool
+
+	// $KEY and ${KEY} references are resolved
+	dollar bool
+
+	// way values of keys holding secrets are hidden, RedactMask if nil
+	redactor Redactor
+
+	// timeout of commands of values written as $(command args), substitution is disabled if zero
+	commandTimeout time.Duration
+
+	// names of commands allowed in values, any command if nil
+	allowedCommands []string
+
+	// lines are parsed the way the first version of the module did
+	legacy bool
+
+	// CRLF and CR line endings inside values are replaced with LF
+	newlines bool
+
+	// profile whose section is applied, keys of sections are skipped if empty
+	profile string
+
+	// result of the last loading
+	result *Result
+
+	// states of the files of the last loading by file name
+	stamps map[string]fileStamp
+
+	// shortest lifetimes of leased secrets by file name and line
+	leases map[string]map[int]time.Duration
+
+	// parsing statistics by file name
+	stats map[string]Stats
+
+	// effective definitions of the files of the last loading served by Report
+	reported *reportCache
+
+	// result, stamps, leases, statistics, report cache and application name access synchronization
+	mu sync.Mutex
+}
+
+// Option configures the loader.
+type Option func(*Loader)
+
+// std is the loader used by the package-level functions.
+var std = NewLoader()
+
+// NewLoader creates a loader with the given options.
+func NewLoader(options ...Option) *Loader {
+
+	// loader with default options
+	loader := &Loader{}
+
+	// iterating over a list of options
+	for _, option := range options {
+
+		// apply option
+		option(loader)
+	}
+
+	return loader
+}
+
+// WithDialect sets the syntax of the files.
+func WithDialect(dialect Dialect) Option {
+	return func(l *Loader) {
+
+		// set dialect
+		l.dialect = dialect
+	}
+}
+
+// WithListSeparator joins repeated KEY[] = value lines into one KEY value with the separator
+// instead of indexed KEY_0, KEY_1 keys.
+func WithListSeparator(separator string) Option {
+	return func(l *Loader) {
+
+		// join list items
+		l.join = true
+
+		// set separator
+		l.separator = separator
+	}
+}
+
+// WithDelimiters sets custom delimiters of references, e.g. "%" and "%" for %KEY% or "@{" and "}"
+// for @{KEY}, instead of the curly braces of the default dialect. A doubled opening delimiter
+// is a literal one.
+func WithDelimiters(open, close string) Option {
+	return func(l *Loader) {
+
+		// set opening delimiter
+		l.open = open
+
+		// set closing delimiter
+		l.close = close
+	}
+}
+
+// syntax returns the way lines and references are written.
+func (l *Loader) syntax() parser.Syntax {
+	return parser.Syntax{Dialect: l.dialect, Open: l.open, Close: l.close, Braces: l.braces, Comments: l.inlineComments && !l.legacy, Dollar: l.dollar && !l.legacy, Legacy: l.legacy}
+}
+
+// Result returns the result of the last loading or nil if nothing was loaded.
+func (l *Loader) Result() *Result {
+
+	// lock result
+	l.mu.Lock()
+
+	// deferred unlock of result
+	defer l.mu.Unlock()
+
+	return l.result
+}
+
+// setResult stores the result of loading.
+func (l *Loader) setResult(result *Result) {
+
+	// lock result
+	l.mu.Lock()
+
+	// deferred unlock of result
+	defer l.mu.Unlock()
+
+	// update result
+	l.result = result
+}
@@ -0,0 +1,307 @@
+package envfile
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// LockFile is the name of the lock file written by WriteLock and checked by VerifyLock.
+const LockFile = ".envfile.lock"
+
+// lockSaltSize is the number of random bytes of the salt of the lock file.
+const lockSaltSize = 16
+
+// lock is the content of the lock file.
+type lock struct {
+
+	// random salt of the digests, hex-encoded, empty in locks written before salting
+	Salt string `json:"salt,omitempty"`
+
+	// locked files in order of loading
+	Files []lockedFile `json:"files"`
+}
+
+// lockedFile is a file of the lock.
+type lockedFile struct {
+
+	// file name
+	Name string `json:"name"`
+
+	// digest of the content
+	Hash string `json:"hash"`
+
+	// digests of values as they are written by key
+	Keys map[string]string `json:"keys"`
+
+	// digests of the content of included files by file name
+	Includes map[string]string `json:"includes,omitempty"`
+}
+
+// WriteLock writes the lock file with digests of the files and of every value as it is written,
+// so the configuration can be verified before deploying. Without names .envfile is locked.
+// Digests are keyed with a random salt of the lock file, so values can't be found by trying them.
+func WriteLock(filenames ...string) error {
+	return NewLoader().WriteLock(filenames...)
+}
+
+// VerifyLock checks that the files of the lock file have not changed since it was written.
+func VerifyLock() error {
+	return NewLoader().VerifyLock()
+}
+
+// WriteLock writes the lock file with digests of the files and of every value as it is written,
+// keyed with a new random salt.
+func (l *Loader) WriteLock(filenames ...string) error {
+
+	// file name list is empty
+	if len(filenames) == 0 {
+
+		// default file and drop-in fragments
+		filenames = l.defaultNames()
+	}
+
+	// random salt
+	salt := make([]byte, lockSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+
+	// lock content
+	content := lock{Salt: hex.EncodeToString(salt)}
+
+	// iterating over a list of filenames
+	for _, filename := range filenames {
+
+		// lock file
+		file, err := l.lockFile(filename, content.Salt)
+		if err != nil {
+			return err
+		}
+
+		// add file to lock
+		content.Files = append(content.Files, file)
+	}
+
+	// encode lock
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(LockFile, append(data, '\n'), 0644)
+}
+
+// VerifyLock checks that the files of the lock file have not changed since it was written,
+// the error names the changed, added and removed keys.
+func (l *Loader) VerifyLock() error {
+
+	// read lock file
+	data, err := ioutil.ReadFile(LockFile)
+	if err != nil {
+		return err
+	}
+
+	// lock content
+	var content lock
+
+	// decode lock
+	if err := json.Unmarshal(data, &content); err != nil {
+		return fmt.Errorf("[%s] %s", LockFile, err)
+	}
+
+	// iterating over locked files
+	for _, locked := range content.Files {
+
+		// current state of the file with the salt of the lock
+		file, err := l.lockFile(locked.Name, content.Salt)
+		if err != nil {
+			return err
+		}
+
+		// file and its included files have not changed
+		if file.Hash == locked.Hash && maps.Equal(file.Includes, locked.Includes) {
+			continue
+		}
+
+		// differences by kind
+		var changed, added, removed, included []string
+
+		// iterating over current keys
+		for key, hash := range file.Keys {
+
+			// key is new or has another value
+			if lockedHash, ok := locked.Keys[key]; !ok {
+				added = append(added, key)
+			} else if lockedHash != hash {
+				changed = append(changed, key)
+			}
+		}
+
+		// iterating over locked keys
+		for key := range locked.Keys {
+
+			// key is removed
+			if _, ok := file.Keys[key]; !ok {
+				removed = append(removed, key)
+			}
+		}
+
+		// iterating over current and locked included files
+		for _, includes := range []map[string]string{file.Includes, locked.Includes} {
+			for name := range includes {
+
+				// included file is new, removed or changed
+				if file.Includes[name] != locked.Includes[name] && !slices.Contains(included, name) {
+					included = append(included, name)
+				}
+			}
+		}
+
+		// description of differences
+		var differences []string
+
+		// iterating over kinds of differences
+		for _, kind := range []struct {
+			name string
+			keys []string
+		}{{"changed", changed}, {"added", added}, {"removed", removed}, {"included files changed", included}} {
+
+			// no keys of the kind
+			if len(kind.keys) == 0 {
+				continue
+			}
+
+			// sort keys
+			sort.Strings(kind.keys)
+
+			// add description
+			differences = append(differences, kind.name+" "+strings.Join(kind.keys, ", "))
+		}
+
+		// only formatting or comments have changed
+		if len(differences) == 0 {
+			differences = append(differences, "values are the same, but the content is not")
+		}
+
+		return fmt.Errorf("[%s] file '%s' has changed: %s", LockFile, locked.Name, strings.Join(differences, "; "))
+	}
+
+	return nil
+}
+
+// lockFile returns digests of the file and of its values as they are written keyed with the salt.
+func (l *Loader) lockFile(filename, salt string) (lockedFile, error) {
+
+	// read content
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return lockedFile{}, err
+	}
+
+	// read payloads leaving values as they are written
+	payloads, err := l.read(filename)
+	if err != nil {
+		return lockedFile{}, err
+	}
+
+	// locked file
+	file := lockedFile{
+		Name: filename,
+		Hash: lockDigest(salt, data),
+		Keys: make(map[string]string, len(payloads)),
+	}
+
+	// iterating over a list of payloads
+	for _, payload := range payloads {
+
+		// add digest of the value
+		file.Keys[payload.Key] = lockDigest(salt, []byte(payload.Raw))
+	}
+
+	// add digests of included files
+	if err := l.lockIncludes(&file, salt, filename, data); err != nil {
+		return lockedFile{}, err
+	}
+
+	return file, nil
+}
+
+// lockIncludes adds digests of the files included by the content of the file, and of the files they include.
+func (l *Loader) lockIncludes(file *lockedFile, salt, filename string, data []byte) error {
+
+	// document of the content
+	doc, err := l.readDocument(filename, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	// iterating over document lines
+	for _, node := range doc.Nodes {
+
+		// line does not include a file
+		if node.Kind != NodeInclude {
+			continue
+		}
+
+		// path of the included file
+		path := doc.IncludedPath(node)
+
+		// file is already locked
+		if _, ok := file.Includes[path]; ok {
+			continue
+		}
+
+		// read content of the included file
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		// included files are not set yet
+		if file.Includes == nil {
+			file.Includes = make(map[string]string)
+		}
+
+		// add digest of the included file
+		file.Includes[path] = lockDigest(salt, content)
+
+		// add files included by the included file
+		if err := l.lockIncludes(file, salt, path, content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lockDigest returns the HMAC-SHA256 of the data keyed with the hex-encoded salt as a hex string,
+// the SHA-256 hash for locks written without a salt.
+func lockDigest(salt string, data []byte) string {
+
+	// lock written before salting
+	if len(salt) == 0 {
+		return hashValue(string(data))
+	}
+
+	// keyed digest, a salt that is not hex is taken as it is written
+	key, err := hex.DecodeString(salt)
+	if err != nil {
+		key = []byte(salt)
+	}
+
+	// digest of the data
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
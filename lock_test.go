@@ -0,0 +1,191 @@
+package envfile
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestLock tests writing and verification of the lock file.
+func TestLock(t *testing.T) {
+
+	// working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("error getting working directory: %v", err)
+	}
+
+	// temporary directory for the lock file
+	dir, err := ioutil.TempDir("", "lock")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v", err)
+	}
+
+	// deferred removal of the directory
+	defer os.RemoveAll(dir)
+
+	// change working directory
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("error changing working directory: %v", err)
+	}
+
+	// deferred return to the working directory
+	defer os.Chdir(wd)
+
+	// file content
+	filename := createFile(t, "HOST = localhost\nPORT = 80\nNAME = app\n")
+
+	// write lock
+	if err := WriteLock(filename); err != nil {
+		t.Fatalf("error writing lock: %v", err)
+	}
+
+	// verify unchanged file
+	if err := VerifyLock(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// expected errors by new content
+	contents := map[string]string{
+		"# comment\nHOST = localhost\nPORT = 80\nNAME = app\n": "values are the same, but the content is not",
+		"HOST = example.com\nPORT = 80\nDEBUG = true\n":        "changed HOST; added DEBUG; removed NAME",
+	}
+
+	// iterating over new contents
+	for content, message := range contents {
+
+		// change file
+		if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatalf("error writing file: %v", err)
+		}
+
+		// verify changed file
+		err := VerifyLock()
+
+		// error is different from expected
+		if err == nil || err.Error() != "["+LockFile+"] file '"+filename+"' has changed: "+message {
+			t.Errorf("expected error %q, got %v", message, err)
+		}
+	}
+}
+
+// TestLockInclude tests that changes of included files are detected.
+func TestLockInclude(t *testing.T) {
+
+	// working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("error getting working directory: %v", err)
+	}
+
+	// temporary directory for the lock file
+	dir, err := ioutil.TempDir("", "lock")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v", err)
+	}
+
+	// deferred removal of the directory
+	defer os.RemoveAll(dir)
+
+	// change working directory
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("error changing working directory: %v", err)
+	}
+
+	// deferred return to the working directory
+	defer os.Chdir(wd)
+
+	// included file including a file without keys
+	included := createFile(t, "HOST = localhost\n")
+	empty := createFile(t, "# no keys\n")
+	if err := ioutil.WriteFile(included, []byte("HOST = localhost\ninclude "+empty+"\n"), 0644); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+
+	// file including the file
+	filename := createFile(t, "include "+included+"\nPORT = 80\n")
+
+	// write lock
+	if err := WriteLock(filename); err != nil {
+		t.Fatalf("error writing lock: %v", err)
+	}
+
+	// verify unchanged files
+	if err := VerifyLock(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// both included files are changed by the second change, in order of names
+	both := []string{empty, included}
+	sort.Strings(both)
+
+	// expected errors by changed file
+	changes := []struct {
+		filename string
+		content  string
+		message  string
+	}{
+		{empty, "# still no keys\n", "included files changed " + empty},
+		{included, "HOST = example.com\ninclude " + empty + "\n", "changed HOST; included files changed " + strings.Join(both, ", ")},
+	}
+
+	// iterating over changes
+	for _, change := range changes {
+
+		// change file
+		if err := ioutil.WriteFile(change.filename, []byte(change.content), 0644); err != nil {
+			t.Fatalf("error writing file: %v", err)
+		}
+
+		// verify changed file
+		err := VerifyLock()
+
+		// error is different from expected
+		if err == nil || err.Error() != "["+LockFile+"] file '"+filename+"' has changed: "+change.message {
+			t.Errorf("expected error %q, got %v", change.message, err)
+		}
+	}
+}
+
+// TestLockSalt tests that digests of the same value differ with the salts of lock files.
+func TestLockSalt(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "PASSWORD = 1234\n")
+
+	// loader
+	loader := NewLoader()
+
+	// files locked with different salts
+	first, err := loader.lockFile(filename, "00112233445566778899aabbccddeeff")
+	if err != nil {
+		t.Fatalf("error locking file: %v", err)
+	}
+	second, err := loader.lockFile(filename, "ffeeddccbbaa99887766554433221100")
+	if err != nil {
+		t.Fatalf("error locking file: %v", err)
+	}
+
+	// digests of the same value are the same
+	if first.Keys["PASSWORD"] == second.Keys["PASSWORD"] || first.Hash == second.Hash {
+		t.Errorf("expected different digests with different salts, got %q", first.Keys["PASSWORD"])
+	}
+
+	// digest is the plain hash of the value
+	if first.Keys["PASSWORD"] == hashValue("1234") {
+		t.Error("expected digest keyed with the salt, got the hash of the value")
+	}
+
+	// file locked again with the same salt
+	again, err := loader.lockFile(filename, "00112233445566778899aabbccddeeff")
+	if err != nil {
+		t.Fatalf("error locking file: %v", err)
+	}
+
+	// digests with the same salt are different
+	if again.Keys["PASSWORD"] != first.Keys["PASSWORD"] {
+		t.Errorf("expected the same digest with the same salt, got %q and %q", first.Keys["PASSWORD"], again.Keys["PASSWORD"])
+	}
+}
@@ -0,0 +1,17 @@
+package envfile
+
+import "github.com/afonichev/envfile/parser"
+
+// Marshal writes the payloads as a file of the default dialect, so that Parse returns the same keys,
+// values and export, overload and conditional statuses. Backslashes, new lines, tabs and curly braces
+// are escaped, values with leading or trailing spaces, looking quoted or like an inline comment are
+// double-quoted, so they are read the same with WithInlineComments. Carriage returns can't be written.
+func Marshal(payloads []Payload) ([]byte, error) {
+	return parser.Marshal(payloads)
+}
+
+// MarshalMap writes the keys and values as exported keys of a file of the default dialect
+// in alphabetical order, see Marshal.
+func MarshalMap(values map[string]string) ([]byte, error) {
+	return parser.MarshalMap(values)
+}
@@ -0,0 +1,71 @@
+package envfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestMarshal tests that parsing of marshaled payloads returns the same payloads.
+func TestMarshal(t *testing.T) {
+
+	// payloads with special characters
+	payloads := []Payload{
+		{Key: "PLAIN", Value: "value"},
+		{Key: "EXPORTED", Value: "a\\b\n\tc", Export: true},
+		{Key: "OVERLOADED", Value: "{ not a reference } {{", Overload: true},
+		{Key: "CONDITIONAL", Value: "\nnew line first", Export: true, Conditional: true},
+		{Key: "EMPTY", Export: true},
+		{Key: "SPACES", Value: " padded "},
+		{Key: "QUOTED", Value: "'single' and \"double\""},
+		{Key: "BACKSLASH", Value: "ends with \\"},
+	}
+
+	// marshal payloads
+	content, err := Marshal(payloads)
+	if err != nil {
+		t.Fatalf("error marshaling payloads: %v", err)
+	}
+
+	// parse content again
+	parsed, err := ParseString(string(content))
+	if err != nil {
+		t.Fatalf("error parsing marshaled content: %v\n%s", err, content)
+	}
+
+	// iterating over payloads
+	for i, payload := range payloads {
+
+		// parsed payload
+		got := Payload{Key: parsed[i].Key, Value: parsed[i].Value, Export: parsed[i].Export, Overload: parsed[i].Overload, Conditional: parsed[i].Conditional}
+
+		// payload is different
+		if !reflect.DeepEqual(got, payload) {
+			t.Errorf("expected %+v, got %+v", payload, got)
+		}
+	}
+
+	// carriage returns can't be written
+	if _, err := Marshal([]Payload{{Key: "KEY", Value: "a\rb"}}); err == nil {
+		t.Error("value has a carriage return but marshal didn't return an error")
+	}
+
+	// invalid key
+	if _, err := Marshal([]Payload{{Key: "KEY NAME", Value: "value"}}); err == nil {
+		t.Error("key is not valid but marshal didn't return an error")
+	}
+}
+
+// TestMarshalMap tests marshaling of the map in alphabetical order.
+func TestMarshalMap(t *testing.T) {
+
+	// marshal map
+	content, err := MarshalMap(map[string]string{"B": "{ b }", "A": "a"})
+	if err != nil {
+		t.Fatalf("error marshaling map: %v", err)
+	}
+
+	// exported keys in alphabetical order
+	if expected := "export A = a\nexport B = {{ b }}\n"; string(content) != expected {
+		t.Errorf("expected %q, got %q", expected, content)
+	}
+}
@@ -0,0 +1,108 @@
+package envfile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// documentSeparator starts a named document within a file, e.g. "--- service: worker".
+const documentSeparator = "---"
+
+// ParseMultiDocument parses a file of documents separated by "--- service: name" or "--- name" lines,
+// see Loader.ParseMultiDocument.
+func ParseMultiDocument(filename string) (map[string]Payloads, error) {
+	return NewLoader().ParseMultiDocument(filename)
+}
+
+// ParseMultiDocument parses a file of documents separated by "--- service: name" or "--- name" lines
+// and returns payloads by document name. Lines before the first separator are the document named "",
+// returned only if it has keys. Every document is resolved on its own, line numbers are the ones of the file.
+func (l *Loader) ParseMultiDocument(filename string) (map[string]Payloads, error) {
+
+	// open file with environment variables after verifying its signature
+	file, err := l.openFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// deferred file close
+	defer file.Close()
+
+	// read document with all lines of the file
+	doc, err := l.readDocument(filename, file)
+	if err != nil {
+		return nil, err
+	}
+
+	// names of documents in order of the file
+	names := []string{""}
+
+	// lines by document name
+	sections := map[string][]Node{"": nil}
+
+	// name of the current document
+	current := ""
+
+	// iterating over a list of nodes
+	for _, node := range doc.Nodes {
+
+		// line without surrounding spaces
+		text := strings.TrimSpace(node.Text)
+
+		// line is not a separator
+		if !strings.HasPrefix(text, documentSeparator) {
+			sections[current] = append(sections[current], node)
+			continue
+		}
+
+		// name of the document after the separator, "service: name" or "name"
+		name := strings.TrimSpace(text[len(documentSeparator):])
+		if position := strings.IndexByte(name, ':'); position >= 0 {
+			name = strings.TrimSpace(name[position+1:])
+		}
+
+		// name is missing
+		if len(name) == 0 {
+			return nil, fmt.Errorf("[%s] line %d: document name is missing after '%s'", filename, node.Line, documentSeparator)
+		}
+
+		// name is already used
+		if _, ok := sections[name]; ok {
+			return nil, fmt.Errorf("[%s] line %d: document '%s' is already defined", filename, node.Line, name)
+		}
+
+		// start of the document
+		names = append(names, name)
+		sections[name] = nil
+		current = name
+	}
+
+	// payloads by document name
+	documents := make(map[string]Payloads)
+
+	// iterating over documents
+	for _, name := range names {
+
+		// convert lines of the document into payloads
+		payloads, err := l.payloads(&Document{Name: filename, Nodes: sections[name], Syntax: doc.Syntax})
+		if err != nil {
+			return nil, err
+		}
+
+		// replace variables with their values
+		payloads, err = l.resolve(filename, payloads)
+		if err != nil {
+			return nil, err
+		}
+
+		// lines before the first separator have no keys
+		if len(name) == 0 && len(payloads) == 0 {
+			continue
+		}
+
+		// add document
+		documents[name] = payloads
+	}
+
+	return documents, nil
+}
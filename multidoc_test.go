@@ -0,0 +1,60 @@
+package envfile
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseMultiDocument tests parsing of named documents within one file.
+func TestParseMultiDocument(t *testing.T) {
+
+	// file content
+	filename := createFile(t, `# shared comment
+--- service: api
+export PORT = 8080
+export URL = http://localhost:{ PORT }
+--- worker
+export PORT = 9090
+export QUEUE = jobs
+`)
+
+	// parse file
+	documents, err := ParseMultiDocument(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// number of documents is different from expected, the first one has no keys
+	if len(documents) != 2 {
+		t.Fatalf("expected 2 documents, got %d", len(documents))
+	}
+
+	// references are resolved within the document
+	if payload, _ := documents["api"].Lookup("URL"); payload.Value != "http://localhost:8080" || payload.Line != 4 {
+		t.Errorf("expected URL on line 4 to be http://localhost:8080, got %+v", payload)
+	}
+
+	// same key in another document
+	if payload, _ := documents["worker"].Lookup("PORT"); payload.Value != "9090" || payload.Line != 6 {
+		t.Errorf("expected PORT on line 6 to be 9090, got %+v", payload)
+	}
+
+	// expected errors by content
+	contents := map[string]string{
+		"--- api\nKEY = 1\n--- api\nKEY = 2\n":              "line 3: document 'api' is already defined",
+		"KEY = 1\n---\n":                                    "line 2: document name is missing",
+		"--- api\nKEY = { OTHER }\n--- worker\nOTHER = 1\n": "line 2: variable 'OTHER' does not exist",
+	}
+
+	// iterating over contents
+	for content, expected := range contents {
+
+		// parse file
+		_, err := ParseMultiDocument(createFile(t, content))
+
+		// error is different from expected
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("content %q: expected error %q, got %v", content, expected, err)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package envfile
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// WithKeyPattern sets the naming convention of exported and overloaded keys checked by
+// the naming-convention lint rule and followed by FixKeyNames, SCREAMING_SNAKE_CASE by default.
+func WithKeyPattern(pattern *regexp.Regexp) Option {
+	return func(l *Loader) {
+
+		// set pattern
+		l.keyPattern = pattern
+	}
+}
+
+// namingPattern returns the naming convention of exported and overloaded keys.
+func (l *Loader) namingPattern() *regexp.Regexp {
+
+	// default convention
+	if l.keyPattern == nil {
+		return screamingSnakeCase
+	}
+
+	return l.keyPattern
+}
+
+// FixKeyNames renames exported and overloaded keys of the document that do not follow the naming
+// convention to SCREAMING_SNAKE_CASE, together with references to them. Keys are left as they are
+// if the new name does not follow the convention either or is already used. It returns new names by old ones.
+func (l *Loader) FixKeyNames(doc *Document) (map[string]string, error) {
+
+	// naming convention
+	pattern := l.namingPattern()
+
+	// keys of the document
+	keys := make(map[string]bool)
+
+	// iterating over a list of nodes
+	for _, node := range doc.Nodes {
+
+		// key is defined
+		if node.Kind == NodeEntry {
+			keys[node.Key] = true
+		}
+	}
+
+	// new names by old ones
+	renames := make(map[string]string)
+
+	// iterating over a list of nodes
+	for _, node := range doc.Nodes {
+
+		// node is not an exported or overloaded key, or the key follows the convention
+		if node.Kind != NodeEntry || !(node.Export || node.Overload) || pattern.MatchString(node.Key) {
+			continue
+		}
+
+		// new name
+		name := screamingSnake(node.Key)
+
+		// new name does not follow the convention or is already used
+		if !pattern.MatchString(name) || keys[name] {
+			continue
+		}
+
+		// add rename
+		renames[node.Key] = name
+	}
+
+	// iterating over nodes from the last one, so edits do not shift positions of the next ones
+	for i := len(doc.Nodes) - 1; i >= 0; i-- {
+
+		// current node
+		node := doc.Nodes[i]
+
+		// node is not an entry
+		if node.Kind != NodeEntry {
+			continue
+		}
+
+		// iterating over references from the last one
+		for j := len(node.References) - 1; j >= 0; j-- {
+
+			// reference
+			reference := node.References[j]
+
+			// referenced variable, without the path to a JSON field
+			variable := strings.SplitN(reference.Name, ".", 2)[0]
+
+			// variable is not renamed
+			name, ok := renames[variable]
+			if !ok {
+				continue
+			}
+
+			// position of the variable name in the line
+			start := reference.Span.Start + strings.Index(node.Text[reference.Span.Start:reference.Span.End], variable)
+
+			// replace variable name
+			if _, err := doc.ApplyEdit(Range{Start: Position{Line: node.Line, Column: start}, End: Position{Line: node.Line, Column: start + len(variable)}}, name); err != nil {
+				return nil, err
+			}
+		}
+
+		// key is not renamed
+		name, ok := renames[node.Key]
+		if !ok {
+			continue
+		}
+
+		// replace key name
+		if _, err := doc.ApplyEdit(Range{Start: Position{Line: node.Line, Column: node.KeySpan.Start}, End: Position{Line: node.Line, Column: node.KeySpan.End}}, name); err != nil {
+			return nil, err
+		}
+	}
+
+	return renames, nil
+}
+
+// screamingSnake converts the key name to SCREAMING_SNAKE_CASE, e.g. dbHost and HTTPServer
+// become DB_HOST and HTTP_SERVER.
+func screamingSnake(key string) string {
+
+	// key characters
+	runes := []rune(key)
+
+	// converted name
+	var builder strings.Builder
+
+	// iterating over characters
+	for i, r := range runes {
+
+		// start of a word in camel case
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+			(unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			builder.WriteByte('_')
+		}
+
+		// add character in upper case
+		builder.WriteRune(unicode.ToUpper(r))
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,115 @@
+package envfile
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestLintKeyPattern tests the naming convention of exported keys.
+func TestLintKeyPattern(t *testing.T) {
+
+	// file content
+	filename := createFile(t, `
+export APP_NAME = app
+export OTHER_NAME = other
+export dbHost = localhost
+localKey = value
+`)
+
+	// expected findings by pattern
+	expected := map[string][]string{
+		"": {
+			"key 'dbHost' is not in SCREAMING_SNAKE_CASE",
+		},
+		"^APP_": {
+			"key 'OTHER_NAME' does not match the naming convention '^APP_'",
+			"key 'dbHost' does not match the naming convention '^APP_'",
+		},
+	}
+
+	// iterating over patterns
+	for pattern, messages := range expected {
+
+		// loader options
+		var options []Option
+		if len(pattern) > 0 {
+			options = append(options, WithKeyPattern(regexp.MustCompile(pattern)))
+		}
+
+		// check file
+		findings, err := NewLinter(options...).Lint(filename)
+		if err != nil {
+			t.Fatalf("error linting env file: %v", err)
+		}
+
+		// messages of the rule
+		var actual []string
+
+		// iterating over a list of findings
+		for _, finding := range findings {
+
+			// finding of the rule
+			if finding.Rule == "naming-convention" {
+				actual = append(actual, finding.Message)
+			}
+		}
+
+		// findings are different from expected
+		if !reflect.DeepEqual(actual, messages) {
+			t.Errorf("pattern %q: expected findings %q, got %q", pattern, messages, actual)
+		}
+	}
+}
+
+// TestFixKeyNames tests renaming of keys to SCREAMING_SNAKE_CASE.
+func TestFixKeyNames(t *testing.T) {
+
+	// file content
+	filename := createFile(t, `export dbHost = localhost
+export HTTPServer = http://{ dbHost }:{ port }/{dbHost}
+port = 80
+export apiKey = { API_KEY }
+export API_KEY = secret
+`)
+
+	// parse document
+	doc, err := ParseDocument(filename)
+	if err != nil {
+		t.Fatalf("error parsing document: %v", err)
+	}
+
+	// rename keys
+	renames, err := NewLoader().FixKeyNames(doc)
+	if err != nil {
+		t.Fatalf("error fixing key names: %v", err)
+	}
+
+	// renames are different from expected, apiKey is kept because API_KEY is used
+	if expected := map[string]string{"dbHost": "DB_HOST", "HTTPServer": "HTTP_SERVER"}; !reflect.DeepEqual(renames, expected) {
+		t.Errorf("expected renames %v, got %v", expected, renames)
+	}
+
+	// lines of the document
+	var lines []string
+
+	// iterating over a list of nodes
+	for _, node := range doc.Nodes {
+
+		// add line
+		lines = append(lines, node.Text)
+	}
+
+	// expected content
+	expected := `export DB_HOST = localhost
+export HTTP_SERVER = http://{ DB_HOST }:{ port }/{DB_HOST}
+port = 80
+export apiKey = { API_KEY }
+export API_KEY = secret`
+
+	// content is different from expected
+	if content := strings.Join(lines, "\n"); content != expected {
+		t.Errorf("expected content:\n%s\ngot:\n%s", expected, content)
+	}
+}
@@ -0,0 +1,29 @@
+package envfile
+
+import "strings"
+
+// WithNormalizedNewlines replaces CRLF and lone CR line endings inside values with LF, e.g. of multi-line
+// values read with value sources from files written on Windows, base64-encoded ones or command outputs.
+// Line endings of the env file itself never end up in values.
+func WithNormalizedNewlines(enabled bool) Option {
+	return func(l *Loader) {
+
+		// set newlines normalization status
+		l.newlines = enabled
+	}
+}
+
+// normalizeNewlines replaces CRLF and CR line endings inside values with LF.
+func normalizeNewlines(payloads []Payload) []Payload {
+
+	// iterating over a list of payloads
+	for i := range payloads {
+
+		// value with carriage returns
+		if strings.Contains(payloads[i].Value, "\r") {
+			payloads[i].Value = strings.ReplaceAll(strings.ReplaceAll(payloads[i].Value, "\r\n", "\n"), "\r", "\n")
+		}
+	}
+
+	return payloads
+}
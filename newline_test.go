@@ -0,0 +1,43 @@
+package envfile
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+// TestNormalizedNewlines tests replacing of CRLF line endings inside multi-line values.
+func TestNormalizedNewlines(t *testing.T) {
+
+	// multi-line value with windows line endings
+	encoded := base64.StdEncoding.EncodeToString([]byte("-----BEGIN KEY-----\r\nMIIE\r\n-----END KEY-----\r\n"))
+
+	// file with windows line endings, a multi-line value and an encoded one
+	filename := createFile(t, "CERT = <<EOF\r\nline 1\r\nline 2\r\nEOF\r\nKEY = base64:"+encoded+"\r\n")
+
+	// parse file keeping line endings of the encoded value
+	payloads, err := NewLoader(WithValueSources(true)).Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// line endings of the file are not part of the multi-line value
+	if value := payloads[0].Value; value != "line 1\nline 2" {
+		t.Errorf("expected CERT to be %q, got %q", "line 1\nline 2", value)
+	}
+
+	// line endings of the encoded value are kept without the option
+	if value := payloads[1].Value; value != "-----BEGIN KEY-----\r\nMIIE\r\n-----END KEY-----\r\n" {
+		t.Errorf("expected KEY to keep CRLF line endings, got %q", value)
+	}
+
+	// parse file normalizing line endings
+	payloads, err = NewLoader(WithValueSources(true), WithNormalizedNewlines(true)).Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// line endings of the encoded value are normalized
+	if value := payloads[1].Value; value != "-----BEGIN KEY-----\nMIIE\n-----END KEY-----\n" {
+		t.Errorf("expected KEY to have LF line endings, got %q", value)
+	}
+}
@@ -0,0 +1,230 @@
+package envfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+)
+
+// OnePassword resolves { op://vault/item/field } and { op://vault/item/section/field } references
+// with the 1Password CLI or, if the Connect host is set, with the 1Password Connect API.
+// Vaults and items are found by name or identifier.
+type OnePassword struct {
+
+	// CLI command, "op" by default
+	Command string
+
+	// Connect server address, e.g. http://localhost:8080
+	ConnectHost string
+
+	// Connect access token
+	ConnectToken string
+
+	// HTTP client for Connect, http.DefaultClient by default
+	Client *http.Client
+}
+
+// onePasswordObject is a vault, an item or a section of the Connect API.
+type onePasswordObject struct {
+
+	// identifier
+	ID string `json:"id"`
+
+	// name of vault or item
+	Title string `json:"title"`
+
+	// name of section
+	Label string `json:"label"`
+}
+
+// onePasswordItem is an item with fields of the Connect API.
+type onePasswordItem struct {
+
+	// fields of the item
+	Fields []struct {
+
+		// identifier
+		ID string `json:"id"`
+
+		// name
+		Label string `json:"label"`
+
+		// value
+		Value string `json:"value"`
+
+		// section of the field
+		Section *onePasswordObject `json:"section"`
+	} `json:"fields"`
+
+	// sections of the item
+	Sections []onePasswordObject `json:"sections"`
+}
+
+// Scheme returns the URI scheme of 1Password secret references.
+func (p *OnePassword) Scheme() string {
+	return "op"
+}
+
+// Resolve returns the secret referenced by the op:// URI.
+func (p *OnePassword) Resolve(uri string) (string, error) {
+
+	// Connect API is configured
+	if len(p.ConnectHost) > 0 {
+		return p.connect(uri)
+	}
+
+	// CLI command
+	command := p.Command
+	if len(command) == 0 {
+		command = "op"
+	}
+
+	// output of the command
+	var stdout, stderr bytes.Buffer
+
+	// read secret
+	cmd := exec.Command(command, "read", "--no-newline", uri)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+
+		// error message of the command
+		if message := strings.TrimSpace(stderr.String()); len(message) > 0 {
+			return "", errors.New(message)
+		}
+
+		return "", err
+	}
+
+	return stdout.String(), nil
+}
+
+// connect returns the secret referenced by the op:// URI from the Connect API.
+func (p *OnePassword) connect(uri string) (string, error) {
+
+	// parts of the reference: vault, item, optional section and field
+	parts := strings.Split(strings.TrimPrefix(uri, "op://"), "/")
+	if len(parts) < 3 || len(parts) > 4 {
+		return "", errors.New("reference must be op://vault/item/[section/]field")
+	}
+
+	// vault
+	vault, err := p.find("/v1/vaults", parts[0])
+	if err != nil {
+		return "", err
+	}
+
+	// item
+	item, err := p.find("/v1/vaults/"+url.PathEscape(vault)+"/items", parts[1])
+	if err != nil {
+		return "", err
+	}
+
+	// item with fields
+	var data onePasswordItem
+	if err := p.get("/v1/vaults/"+url.PathEscape(vault)+"/items/"+url.PathEscape(item), &data); err != nil {
+		return "", err
+	}
+
+	// field name and section name
+	field, section := parts[len(parts)-1], ""
+	if len(parts) == 4 {
+		section = parts[2]
+	}
+
+	// iterating over fields of the item
+	for _, f := range data.Fields {
+
+		// field has another name
+		if f.Label != field && f.ID != field {
+			continue
+		}
+
+		// field is in another section
+		if len(section) > 0 && (f.Section == nil || !p.section(data.Sections, f.Section.ID, section)) {
+			continue
+		}
+
+		return f.Value, nil
+	}
+
+	return "", fmt.Errorf("field '%s' is not found", field)
+}
+
+// section reports whether the section with the identifier has the name or the identifier.
+func (p *OnePassword) section(sections []onePasswordObject, id, name string) bool {
+
+	// section is referenced by identifier
+	if id == name {
+		return true
+	}
+
+	// iterating over sections
+	for _, s := range sections {
+
+		// section is found
+		if s.ID == id {
+			return s.Label == name
+		}
+	}
+
+	return false
+}
+
+// find returns the identifier of the vault or item with the name, the name is taken
+// as an identifier if nothing has it.
+func (p *OnePassword) find(path, name string) (string, error) {
+
+	// objects with the name
+	var objects []onePasswordObject
+	if err := p.get(path+"?filter="+url.QueryEscape(`title eq "`+name+`"`), &objects); err != nil {
+		return "", err
+	}
+
+	// object is found by name
+	if len(objects) > 0 {
+		return objects[0].ID, nil
+	}
+
+	return name, nil
+}
+
+// get decodes the JSON response of the Connect API.
+func (p *OnePassword) get(path string, v interface{}) error {
+
+	// request
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(p.ConnectHost, "/")+path, nil)
+	if err != nil {
+		return err
+	}
+
+	// access token
+	req.Header.Set("Authorization", "Bearer "+p.ConnectToken)
+
+	// HTTP client
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	// send request
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	// deferred body close
+	defer resp.Body.Close()
+
+	// request failed
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("connect API returned %s", resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
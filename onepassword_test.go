@@ -0,0 +1,135 @@
+package envfile
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestOnePasswordCLI tests resolving secret references with the 1Password CLI.
+func TestOnePasswordCLI(t *testing.T) {
+
+	// directory of the fake CLI
+	dir, err := ioutil.TempDir("", "op")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v", err)
+	}
+
+	// deferred removal of the directory
+	defer os.RemoveAll(dir)
+
+	// fake CLI printing the secret of a known reference
+	command := filepath.Join(dir, "op")
+	script := "#!/bin/sh\n[ \"$3\" = op://dev/db/password ] && printf 'p{a}ss' && exit 0\necho \"item not found\" >&2\nexit 1\n"
+	if err := ioutil.WriteFile(command, []byte(script), 0755); err != nil {
+		t.Fatalf("error writing fake CLI: %v", err)
+	}
+
+	// file content
+	filename := createFile(t, "DB_PASSWORD = { op://dev/db/password }\nDSN = admin:{ DB_PASSWORD }@db\n")
+
+	// parse file
+	payloads, err := NewLoader(WithProvider(&OnePassword{Command: command})).Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// secret is different from expected
+	if payload, _ := payloads.Lookup("DSN"); payload.Value != "admin:p{a}ss@db" {
+		t.Errorf("expected DSN to be admin:p{a}ss@db, got %s", payload.Value)
+	}
+
+	// unknown reference
+	_, err = NewLoader(WithProvider(&OnePassword{Command: command})).Parse(createFile(t, "KEY = { op://dev/db/missing }\n"))
+
+	// error of the CLI is not returned
+	if err == nil || !strings.HasSuffix(err.Error(), "item not found") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestOnePasswordConnect tests resolving secret references with the 1Password Connect API.
+func TestOnePasswordConnect(t *testing.T) {
+
+	// fake Connect server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		// token is missing
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+
+		// vaults by name
+		case "/v1/vaults":
+			w.Write([]byte(`[{"id": "v1", "title": "dev"}]`))
+
+		// items by name
+		case "/v1/vaults/v1/items":
+			w.Write([]byte(`[{"id": "i1", "title": "db"}]`))
+
+		// item with fields
+		case "/v1/vaults/v1/items/i1":
+			w.Write([]byte(`{
+				"sections": [{"id": "s1", "label": "replica"}],
+				"fields": [
+					{"id": "f1", "label": "password", "value": "primary"},
+					{"id": "f2", "label": "password", "value": "replica", "section": {"id": "s1"}}
+				]
+			}`))
+
+		// any
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	// deferred server close
+	defer server.Close()
+
+	// provider
+	provider := &OnePassword{ConnectHost: server.URL, ConnectToken: "token"}
+
+	// expected secrets by reference
+	secrets := map[string]string{
+		"op://dev/db/password":         "primary",
+		"op://dev/db/replica/password": "replica",
+		"op://v1/i1/s1/f2":             "replica",
+	}
+
+	// iterating over references
+	for uri, secret := range secrets {
+
+		// resolve secret
+		value, err := provider.Resolve(uri)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", uri, err)
+			continue
+		}
+
+		// secret is different from expected
+		if value != secret {
+			t.Errorf("%s: expected %s, got %s", uri, secret, value)
+		}
+	}
+
+	// iterating over invalid references
+	for _, uri := range []string{"op://dev/db", "op://dev/db/missing", "op://dev/db/other/password"} {
+
+		// invalid reference is resolved
+		if _, err := provider.Resolve(uri); err == nil {
+			t.Errorf("%s: reference is invalid but resolve didn't return an error", uri)
+		}
+	}
+
+	// wrong token
+	if _, err := (&OnePassword{ConnectHost: server.URL}).Resolve("op://dev/db/password"); err == nil {
+		t.Error("token is wrong but resolve didn't return an error")
+	}
+}
@@ -0,0 +1,43 @@
+package envfile
+
+// Overload will load files with environment variables for this process, every key overwrites the environment,
+// see Loader.Overload.
+func Overload(filenames ...string) error {
+	return std.Overload(filenames...)
+}
+
+// Overload will load files with environment variables for this process as if every key had the overload
+// directive, including keys local to the files, matching godotenv.Overload. Conditional assignments
+// are still set only if the key is not defined yet.
+func (l *Loader) Overload(filenames ...string) error {
+
+	// file name list is empty
+	if len(filenames) == 0 {
+
+		// default file and drop-in fragments
+		filenames = l.defaultNames()
+	}
+
+	return l.load(filenames, func(filename string) (Payloads, error) {
+
+		// parse file
+		payloads, err := l.Parse(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		// iterating over a list of payloads
+		for i := range payloads {
+
+			// conditional key keeps its meaning
+			if payloads[i].Conditional {
+				continue
+			}
+
+			// overload key
+			payloads[i].Overload = true
+		}
+
+		return payloads, nil
+	}, true)
+}
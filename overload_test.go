@@ -0,0 +1,31 @@
+package envfile
+
+import (
+	"os"
+	"testing"
+)
+
+// TestOverload tests that every key overwrites the environment.
+func TestOverload(t *testing.T) {
+
+	// variables of the environment
+	os.Setenv("FORCED_EXPORTED", "env")
+	os.Setenv("FORCED_CONDITIONAL", "env")
+
+	// deferred unset of the variables
+	for _, key := range []string{"FORCED_EXPORTED", "FORCED_LOCAL", "FORCED_CONDITIONAL"} {
+		defer os.Unsetenv(key)
+	}
+
+	// overload file
+	if err := Overload(createFile(t, "export FORCED_EXPORTED = file\nFORCED_LOCAL = file\nFORCED_CONDITIONAL ?= file\n")); err != nil {
+		t.Fatalf("error overloading env file: %v", err)
+	}
+
+	// expected values
+	for key, expected := range map[string]string{"FORCED_EXPORTED": "file", "FORCED_LOCAL": "file", "FORCED_CONDITIONAL": "env"} {
+		if value := os.Getenv(key); value != expected {
+			t.Errorf("expected %s to be %s, got %s", key, expected, value)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// block is a run of lines of the document moved together: a key with the comments written right
+// above it, or a line belonging to no key, e.g. a blank line or a comment followed by one.
+type block struct {
+
+	// key of the block, empty for lines belonging to no key
+	key string
+
+	// lines of the block
+	nodes []Node
+}
+
+// SortKeys sorts keys alphabetically within each section of the document, sections are separated
+// by blank lines and comments that are not attached to a key. Comments right above a key move with it.
+func (d *Document) SortKeys() {
+
+	// blocks of the document
+	blocks := d.blocks()
+
+	// iterating over runs of keys
+	for start := 0; start < len(blocks); {
+
+		// end of the run
+		end := start
+		for end < len(blocks) && len(blocks[end].key) > 0 {
+			end++
+		}
+
+		// sort run
+		run := blocks[start:end]
+		sort.SliceStable(run, func(i, j int) bool {
+			return run[i].key < run[j].key
+		})
+
+		// next run
+		start = end + 1
+	}
+
+	d.setBlocks(blocks)
+}
+
+// GroupByPrefix groups keys by their prefix before the first underscore, DB_HOST and DB_PORT go to
+// the DB group, in order of the first key of each group; groups are separated by a blank line.
+// Comments at the top of the document followed by a blank line stay there, other comments move
+// with the key below them.
+func (d *Document) GroupByPrefix() {
+
+	// blocks of the document
+	blocks := d.blocks()
+
+	// header of the document
+	var header []block
+	if len(blocks) > 0 && len(blocks[0].key) == 0 {
+		for len(blocks) > 0 && len(blocks[0].key) == 0 {
+			header, blocks = append(header, blocks[0]), blocks[1:]
+		}
+	}
+
+	// keys by prefix in order of the first key
+	groups := make(map[string][]block)
+	var prefixes []string
+
+	// comments waiting for the key below them
+	var pending []Node
+
+	// iterating over blocks
+	for _, b := range blocks {
+
+		// line belonging to no key
+		if len(b.key) == 0 {
+
+			// comments wait for the next key, blank lines are replaced by separators of groups
+			if b.nodes[0].Kind != NodeBlank {
+				pending = append(pending, b.nodes...)
+			}
+
+			continue
+		}
+
+		// prefix of the key
+		prefix, _, _ := strings.Cut(b.key, "_")
+
+		// group is found for the first time
+		if _, ok := groups[prefix]; !ok {
+			prefixes = append(prefixes, prefix)
+		}
+
+		// add key to the group with the waiting comments
+		groups[prefix] = append(groups[prefix], block{key: b.key, nodes: append(pending, b.nodes...)})
+		pending = nil
+	}
+
+	// arranged blocks
+	arranged := header
+
+	// iterating over groups
+	for i, prefix := range prefixes {
+
+		// blank line between groups
+		if i > 0 {
+			arranged = append(arranged, block{nodes: []Node{{Kind: NodeBlank}}})
+		}
+
+		// add group
+		arranged = append(arranged, groups[prefix]...)
+	}
+
+	// comments at the end
+	if len(pending) > 0 {
+		arranged = append(arranged, block{nodes: pending})
+	}
+
+	d.setBlocks(arranged)
+}
+
+// MoveKeyAfter moves the key with the comments above it right after the other key.
+func (d *Document) MoveKeyAfter(key, after string) error {
+
+	// blocks of the document
+	blocks := d.blocks()
+
+	// position of the moved key
+	from := blockIndex(blocks, key)
+	if from < 0 {
+		return fmt.Errorf("key '%s' is not defined", key)
+	}
+
+	// block of the moved key
+	moved := blocks[from]
+
+	// blocks without the moved key
+	blocks = append(blocks[:from], blocks[from+1:]...)
+
+	// position of the other key
+	to := blockIndex(blocks, after)
+	if to < 0 {
+		return fmt.Errorf("key '%s' is not defined", after)
+	}
+
+	// insert moved key after the other key
+	blocks = append(blocks[:to+1], append([]block{moved}, blocks[to+1:]...)...)
+
+	d.setBlocks(blocks)
+
+	return nil
+}
+
+// blocks splits the document into blocks in order of lines.
+func (d *Document) blocks() []block {
+
+	// blocks list
+	var blocks []block
+
+	// comments right above the current line
+	var comments []Node
+
+	// iterating over a list of nodes
+	for _, node := range d.Nodes {
+
+		switch node.Kind {
+
+		// comment is attached to the key below it
+		case NodeComment:
+			comments = append(comments, node)
+
+		// line of the multi-line value of the key above
+		case NodeContinuation:
+			blocks[len(blocks)-1].nodes = append(blocks[len(blocks)-1].nodes, node)
+
+		// key with its comments
+		case NodeEntry:
+			blocks = append(blocks, block{key: node.Key, nodes: append(comments, node)})
+			comments = nil
+
+		// blank and invalid lines detach the comments above them
+		default:
+
+			// detached comments
+			for _, comment := range comments {
+				blocks = append(blocks, block{nodes: []Node{comment}})
+			}
+			comments = nil
+
+			// add line
+			blocks = append(blocks, block{nodes: []Node{node}})
+		}
+	}
+
+	// comments at the end
+	for _, comment := range comments {
+		blocks = append(blocks, block{nodes: []Node{comment}})
+	}
+
+	return blocks
+}
+
+// setBlocks replaces the lines of the document with the lines of the blocks, renumbering them.
+func (d *Document) setBlocks(blocks []block) {
+
+	// lines of the document
+	nodes := make([]Node, 0, len(d.Nodes))
+
+	// iterating over blocks
+	for _, b := range blocks {
+
+		// iterating over lines of the block
+		for _, node := range b.nodes {
+
+			// renumber line
+			node.Line = len(nodes) + 1
+
+			// add line
+			nodes = append(nodes, node)
+		}
+	}
+
+	// update lines
+	d.Nodes = nodes
+}
+
+// blockIndex returns the position of the block of the key, -1 if it is not defined.
+func blockIndex(blocks []block, key string) int {
+
+	// iterating over blocks
+	for i, b := range blocks {
+
+		// block of the key
+		if b.key == key {
+			return i
+		}
+	}
+
+	return -1
+}
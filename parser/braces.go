@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// BraceMode is the way literal curly braces are written in values of the default dialect.
+type BraceMode int
+
+const (
+
+	// BracesDoubled takes {{ and }} as literal curly braces.
+	BracesDoubled BraceMode = iota
+
+	// BracesBackslash also takes \{ and \} as literal curly braces.
+	BracesBackslash
+
+	// BracesLenient takes \{ and \} as literal curly braces and leaves braces that do not
+	// enclose a variable name, a JSON path or a secret URI untouched, so Go templates
+	// and JSON snippets are written as they are; {{ and }} are not escapes in this mode,
+	// braces next to other braces are always literal.
+	BracesLenient
+)
+
+// braceReference is a variable name or a path to a field of JSON value with an optional default value
+// or error message, or a URI of a secret enclosed in curly braces in the lenient mode.
+var braceReference = regexp.MustCompile(`^([A-Za-z0-9_]+(\.[A-Za-z0-9_]+)*(\s*:[-?].*)?|[A-Za-z][A-Za-z0-9+.-]*://\S+)$`)
+
+// EscapeBraces rewrites literal curly braces of the mode as doubled ones.
+func EscapeBraces(mode BraceMode, value string) string {
+	return rewriteBraces(mode, value, func(literal string) string {
+
+		// doubled curly brace
+		return strings.Repeat(literal[len(literal)-1:], 2)
+	})
+}
+
+// positionalBraces replaces literal curly braces of the mode with spaces, keeping positions of references.
+func positionalBraces(mode BraceMode, value string) string {
+	return rewriteBraces(mode, value, func(literal string) string {
+
+		// spaces of the same length
+		return strings.Repeat(" ", len(literal))
+	})
+}
+
+// rewriteBraces replaces literal curly braces of the mode, written as \{ or as a single
+// brace in the lenient mode, with the result of the function.
+func rewriteBraces(mode BraceMode, value string, replace func(literal string) string) string {
+
+	// doubled braces are the native escape
+	if mode == BracesDoubled {
+		return value
+	}
+
+	// rewritten value
+	var builder strings.Builder
+
+	// iteration over value
+	for i := 0; i < len(value); i++ {
+
+		switch {
+
+		// escaped backslash is kept for unescaping
+		case value[i] == '\\' && i+1 < len(value) && value[i+1] == '\\':
+			builder.WriteString(`\\`)
+			i++
+
+		// escaped curly brace
+		case value[i] == '\\' && i+1 < len(value) && (value[i+1] == '{' || value[i+1] == '}'):
+			builder.WriteString(replace(value[i : i+2]))
+			i++
+
+		// reference in the lenient mode
+		case mode == BracesLenient && value[i] == '{' && isBraceReference(value, i):
+
+			// length of the reference
+			length := braceLength(value[i:])
+
+			// add reference
+			builder.WriteString(value[i : i+length])
+
+			// skip reference
+			i += length - 1
+
+		// literal curly brace in the lenient mode
+		case mode == BracesLenient && (value[i] == '{' || value[i] == '}'):
+			builder.WriteString(replace(value[i : i+1]))
+
+		// any
+		default:
+			builder.WriteByte(value[i])
+		}
+	}
+
+	return builder.String()
+}
+
+// braceLength returns the length of the reference at the beginning of the value
+// in the lenient mode, or zero if the curly brace does not start a reference.
+func braceLength(value string) int {
+
+	// end of reference
+	end := strings.IndexAny(value[1:], "{}")
+
+	// closing curly brace is missing
+	if end < 0 || value[1+end] != '}' {
+		return 0
+	}
+
+	// enclosed text is not a reference
+	if !braceReference.MatchString(strings.TrimSpace(value[1 : 1+end])) {
+		return 0
+	}
+
+	return end + 2
+}
+
+// isBraceReference reports whether the curly brace at the position starts a reference
+// in the lenient mode: it encloses a reference and is not next to other braces.
+func isBraceReference(value string, position int) bool {
+
+	// length of the reference
+	length := braceLength(value[position:])
+
+	// curly brace does not enclose a reference
+	if length == 0 {
+		return false
+	}
+
+	// curly brace follows another one
+	if position > 0 && value[position-1] == '{' {
+		return false
+	}
+
+	// reference is followed by another curly brace
+	return position+length == len(value) || value[position+length] != '}'
+}
@@ -0,0 +1,168 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PayloadBuilder builds a payload checking the rules of the parser as it goes,
+// the first problem is kept and returned by Build.
+type PayloadBuilder struct {
+
+	// payload being built
+	payload Payload
+
+	// first problem
+	err error
+}
+
+// NewPayload starts building the payload of the key, e.g. NewPayload("PORT").Export().Value("8080").
+func NewPayload(key string) *PayloadBuilder {
+
+	// builder of the payload
+	b := &PayloadBuilder{payload: Payload{Key: key}}
+
+	switch {
+
+	// empty key name
+	case len(key) == 0:
+		b.err = errors.New("key name is empty")
+
+	// invalid key name
+	case !validation.MatchString(key):
+		b.err = fmt.Errorf("invalid key name '%s'", key)
+	}
+
+	return b
+}
+
+// Export sets the export directive.
+func (b *PayloadBuilder) Export() *PayloadBuilder {
+
+	// set export status
+	b.payload.Export = true
+
+	return b.check()
+}
+
+// Overload sets the overload directive.
+func (b *PayloadBuilder) Overload() *PayloadBuilder {
+
+	// set overload status
+	b.payload.Overload = true
+
+	return b.check()
+}
+
+// Conditional makes the assignment conditional, KEY ?= value, which is exported.
+func (b *PayloadBuilder) Conditional() *PayloadBuilder {
+
+	// set conditional status, conditional key is exported
+	b.payload.Conditional, b.payload.Export = true, true
+
+	return b.check()
+}
+
+// Value sets the value, it is escaped when the payload is written.
+func (b *PayloadBuilder) Value(value string) *PayloadBuilder {
+
+	// set value
+	b.payload.Value = value
+
+	return b.check()
+}
+
+// Build returns the payload or the first problem found while building it.
+func (b *PayloadBuilder) Build() (Payload, error) {
+
+	// problem is found
+	if b.err != nil {
+		return Payload{}, b.err
+	}
+
+	// payload with the type of its value
+	payload := b.payload
+	payload.Kind = KindOf(payload.Value)
+
+	return payload, nil
+}
+
+// check keeps the first problem of the payload as it can be written.
+func (b *PayloadBuilder) check() *PayloadBuilder {
+
+	// line of the payload
+	if _, err := marshalLine(b.payload); b.err == nil && err != nil {
+		b.err = err
+	}
+
+	return b
+}
+
+// NewDocument returns an empty document of the default dialect, payloads are added with Add
+// and the text is written by Bytes.
+func NewDocument() *Document {
+	return &Document{newline: "\n"}
+}
+
+// Add appends the lines of the built payloads to the document, nothing is added if one of them
+// has a problem or defines a key that is already defined.
+func (d *Document) Add(builders ...*PayloadBuilder) error {
+
+	// new lines
+	var nodes []Node
+
+	// keys of the document
+	keys := make(map[string]bool)
+	for _, node := range d.Nodes {
+		if node.Kind == NodeEntry {
+			keys[node.Key] = true
+		}
+	}
+
+	// iterating over builders
+	for _, builder := range builders {
+
+		// build payload
+		payload, err := builder.Build()
+		if err != nil {
+			return err
+		}
+
+		// key is already defined
+		if keys[payload.Key] {
+			return fmt.Errorf("duplicate key '%s'", payload.Key)
+		}
+		keys[payload.Key] = true
+
+		// line of the payload
+		line, err := marshalLine(payload)
+		if err != nil {
+			return err
+		}
+
+		// add line
+		nodes = append(nodes, ParseLine(d.Syntax, len(d.Nodes)+len(nodes)+1, line))
+	}
+
+	// empty document gets a line ending after the last line
+	if len(d.Nodes) == 0 && len(nodes) > 0 {
+		d.final = true
+	}
+
+	// add lines
+	d.Nodes = append(d.Nodes, nodes...)
+
+	return nil
+}
+
+// Append adds the line to the end of the document, it is parsed with the syntax of the document.
+func (d *Document) Append(text string) {
+
+	// empty document gets a line ending after the last line
+	if len(d.Nodes) == 0 {
+		d.final = true
+	}
+
+	// add line
+	d.Nodes = append(d.Nodes, ParseLine(d.Syntax, len(d.Nodes)+1, text))
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+)
+
+// inlineComment returns the position of the number sign starting a comment in the value or -1,
+// the quoted part of a value starting with a quote is skipped.
+func inlineComment(value string) int {
+
+	// position the search starts from
+	position := 0
+
+	// value starts with a quote
+	if len(value) > 0 && (value[0] == '"' || value[0] == '\'') {
+
+		// iterating over the value after the opening quote
+		for i := 1; i < len(value); i++ {
+
+			// escaped character of a double-quoted value
+			if value[0] == '"' && value[i] == '\\' {
+				i++
+				continue
+			}
+
+			// closing quote
+			if value[i] == value[0] {
+				position = i + 1
+				break
+			}
+		}
+	}
+
+	// iterating over the rest of the value
+	for i := position; i < len(value); i++ {
+
+		// number sign at the start of the value or after a space
+		if value[i] == '#' && (i == 0 || unicode.IsSpace(rune(value[i-1]))) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// commentSpan returns the position of the inline comment of the entry line including the number sign.
+func commentSpan(node Node) (Span, bool) {
+
+	// number sign after the value
+	i := strings.Index(node.Text[node.ValueSpan.End:], "#")
+	if i < 0 {
+		return Span{}, false
+	}
+
+	// position of the comment
+	start, end := trimSpan(node.Text, node.ValueSpan.End+i, len(node.Text))
+
+	return Span{start, end}, true
+}
@@ -0,0 +1,171 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"runtime"
+	"strings"
+	"unicode"
+)
+
+// conditionVariable is a condition on a variable, as in #if ${CI}.
+var conditionVariable = regexp.MustCompile(`^\$\{([A-Za-z0-9_]+)\}$`)
+
+// conditionComparison is a condition comparing a variable with a value, as in #if GOOS=linux or #if STAGE!=dev.
+var conditionComparison = regexp.MustCompile(`^([A-Za-z0-9_]+)\s*(!?=)\s*(.*)$`)
+
+// condition is an open #if block.
+type condition struct {
+
+	// line of the #if directive
+	line int
+
+	// lines of the current branch are parsed
+	value bool
+
+	// #else directive is found
+	otherwise bool
+}
+
+// conditions are the open #if blocks of a document, from the outermost one.
+type conditions []condition
+
+// conditionDirective returns the directive, if, else or endif, and the expression of a comment line
+// written as #if expression, #else or #endif, with the directive right after the number sign.
+// A comment like "#if needed, set X" whose expression is not a condition stays a comment.
+func conditionDirective(node Node) (string, string, bool) {
+
+	// not a comment
+	if node.Kind != NodeComment {
+		return "", "", false
+	}
+
+	// directive and expression, a comment like "# if ..." is not a directive
+	directive, expression, _ := strings.Cut(strings.TrimRightFunc(node.Comment, unicode.IsSpace), " ")
+
+	switch directive {
+
+	// directive with an expression
+	case "if":
+
+		// expression of the directive
+		expression = strings.TrimSpace(expression)
+
+		return directive, expression, conditionVariable.MatchString(expression) || conditionComparison.MatchString(expression)
+
+	// directives without an expression
+	case "else", "endif":
+		return directive, "", len(expression) == 0
+	}
+
+	return "", "", false
+}
+
+// active reports whether lines are parsed: every open block is in its true branch.
+func (c conditions) active() bool {
+
+	// iterating over open blocks
+	for _, current := range c {
+
+		// block is in its false branch
+		if !current.value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// apply opens, switches or closes a block by the directive of the line.
+func (c *conditions) apply(r *Resolver, line int, directive, expression string) error {
+
+	switch directive {
+
+	// open block
+	case "if":
+
+		// value of the expression
+		value, err := r.condition(expression)
+		if err != nil {
+			return err
+		}
+
+		// add block
+		*c = append(*c, condition{line: line, value: value})
+
+	// switch to the false branch
+	case "else":
+
+		// no block is open
+		if len(*c) == 0 {
+			return fmt.Errorf("#else without #if")
+		}
+
+		// current block
+		current := &(*c)[len(*c)-1]
+
+		// block already has an #else
+		if current.otherwise {
+			return fmt.Errorf("#else repeated in #if of line %d", current.line)
+		}
+
+		// switch branch
+		current.value, current.otherwise = !current.value, true
+
+	// close block
+	case "endif":
+
+		// no block is open
+		if len(*c) == 0 {
+			return fmt.Errorf("#endif without #if")
+		}
+
+		// remove block
+		*c = (*c)[:len(*c)-1]
+	}
+
+	return nil
+}
+
+// condition returns the value of the expression of #if: ${NAME} is true if the variable is set
+// to a value other than empty, 0 or false, NAME=value and NAME!=value compare the variable with
+// the value. GOOS and GOARCH are the platform the program runs on.
+func (r *Resolver) condition(expression string) (bool, error) {
+
+	// variable is set
+	if match := conditionVariable.FindStringSubmatch(expression); match != nil {
+
+		// value of the variable
+		value, _ := r.conditionValue(match[1])
+
+		return value != "" && value != "0" && !strings.EqualFold(value, "false"), nil
+	}
+
+	// variable is compared with a value
+	if match := conditionComparison.FindStringSubmatch(expression); match != nil {
+
+		// value of the variable
+		value, _ := r.conditionValue(match[1])
+
+		return (value == strings.TrimSpace(match[3])) == (match[2] == "="), nil
+	}
+
+	return false, fmt.Errorf("invalid condition '%s'", expression)
+}
+
+// conditionValue returns the value of the variable of a condition.
+func (r *Resolver) conditionValue(name string) (string, bool) {
+
+	switch name {
+
+	// operating system of the program
+	case "GOOS":
+		return runtime.GOOS, true
+
+	// architecture of the program
+	case "GOARCH":
+		return runtime.GOARCH, true
+	}
+
+	return r.lookup(name)
+}
@@ -0,0 +1,23 @@
+package parser
+
+// Environ returns exported and overloaded keys as KEY=value strings in the order of the file,
+// the shape of os.Environ used by exec.Cmd and the Env of Docker SDK container config.
+func (p Payloads) Environ() []string {
+
+	// environment list
+	environ := make([]string, 0, len(p))
+
+	// iterating over a list of payloads
+	for _, payload := range p {
+
+		// key is local to the file
+		if !payload.Export && !payload.Overload {
+			continue
+		}
+
+		// add key and value to list
+		environ = append(environ, payload.Key+"="+payload.Value)
+	}
+
+	return environ
+}
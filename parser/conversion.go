@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ConversionError is a value that can't be converted to the requested type, returned by Get
+// and the As methods of payloads; use errors.As to build messages for users.
+type ConversionError struct {
+
+	// key
+	Key string
+
+	// value as it is set
+	Raw string
+
+	// requested type, e.g. "int" or "time.Duration"
+	Target string
+
+	// file defining the value, empty if it comes from the environment or the file is unknown
+	File string
+
+	// line number in file, zero if unknown
+	Line int
+
+	// reason of the failure, nil if the value is just not of the type
+	Err error
+}
+
+// Error returns the error in the form "[file] line N: value 'abc' of key 'PORT' is not a valid int: reason".
+func (e *ConversionError) Error() string {
+
+	// location of the value
+	var location string
+
+	switch {
+
+	// file and line
+	case len(e.File) > 0:
+		location = fmt.Sprintf("[%s] line %d: ", e.File, e.Line)
+
+	// line only
+	case e.Line > 0:
+		location = fmt.Sprintf("line %d: ", e.Line)
+	}
+
+	// message
+	message := fmt.Sprintf("%svalue '%s' of key '%s' is not a valid %s", location, e.Raw, e.Key, e.Target)
+
+	// reason is known
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+
+	return message
+}
+
+// Unwrap returns the reason of the failure.
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// NumberError returns the reason of the failed number parsing without repeating the value,
+// e.g. "invalid syntax" or "value out of range".
+func NumberError(err error) error {
+
+	// error of number parsing
+	var numError *strconv.NumError
+	if errors.As(err, &numError) {
+		return numError.Err
+	}
+
+	return err
+}
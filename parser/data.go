@@ -0,0 +1,34 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// dataPrefix is the prefix of references to values of the data map.
+const dataPrefix = "data."
+
+// dataValue returns the value of the data map referenced as { data.name.field }, strings
+// are returned as is, anything else as JSON. It reports false if the variable is not a reference to the data map.
+func (r *Resolver) dataValue(variable string) (string, bool, error) {
+
+	// data is not set or the variable is not a reference to it
+	if r.Data == nil || !strings.HasPrefix(variable, dataPrefix) {
+		return "", false, nil
+	}
+
+	// encode data
+	encoded, err := json.Marshal(r.Data)
+	if err != nil {
+		return "", false, fmt.Errorf("data can't be encoded as JSON: %s", err)
+	}
+
+	// field value
+	value, err := jsonField(string(encoded), strings.Split(variable, "."))
+	if err != nil {
+		return "", false, err
+	}
+
+	return value, true, nil
+}
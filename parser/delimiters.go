@@ -0,0 +1,314 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// delimited replaces references written with custom delimiters with values of variables.
+type delimited struct {
+
+	// resolver of values
+	resolver *Resolver
+
+	// syntax of the values
+	syntax Syntax
+
+	// file name
+	filename string
+
+	// payload list
+	payloads []Payload
+
+	// position of payloads by key name
+	index map[string]int
+
+	// resolved values by key name
+	values map[string]string
+
+	// keys being resolved, to detect recursive references
+	active map[string]bool
+
+	// number of replaced references
+	expansions int
+}
+
+// expandDelimited replaces references written with custom delimiters and unescapes special characters.
+func (r *Resolver) expandDelimited(syn Syntax, filename string, payloads []Payload) ([]Payload, int, error) {
+
+	// resolver of references
+	d := &delimited{
+		resolver: r,
+		syntax:   syn,
+		filename: filename,
+		payloads: payloads,
+		index:    make(map[string]int),
+		values:   make(map[string]string),
+		active:   make(map[string]bool),
+	}
+
+	// iterating over a list of payloads
+	for i, payload := range payloads {
+
+		// remember position of the payload
+		d.index[r.indexKey(payload.Key)] = i
+	}
+
+	// iterating over a list of payloads
+	for i := range payloads {
+
+		// resolve value
+		value, err := d.resolve(i)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		// update value
+		payloads[i].Value = value
+	}
+
+	return payloads, d.expansions, nil
+}
+
+// resolve returns the value of the payload with references replaced.
+func (d *delimited) resolve(i int) (string, error) {
+
+	// payload
+	payload := d.payloads[i]
+
+	// value is already resolved
+	if value, ok := d.values[payload.Key]; ok {
+		return value, nil
+	}
+
+	// raw value is taken as it is written
+	if payload.Literal {
+		return payload.Value, nil
+	}
+
+	// key references itself directly or through other keys
+	if d.active[payload.Key] {
+		return "", fmt.Errorf("[%s] line %d: key '%s' is used recursively", PayloadFile(payload, d.filename), payload.Line, payload.Key)
+	}
+
+	// mark key as being resolved
+	d.active[payload.Key] = true
+
+	// deferred unmark of key
+	defer delete(d.active, payload.Key)
+
+	// expand value
+	value, err := d.expand(PayloadFile(payload, d.filename), payload.Line, payload.Value)
+	if err != nil {
+		return "", err
+	}
+
+	// remember resolved value
+	d.values[payload.Key] = value
+
+	return value, nil
+}
+
+// expand replaces references in the value and unescapes special characters.
+func (d *delimited) expand(file string, line int, value string) (string, error) {
+
+	// delimiters
+	open, close := d.syntax.Open, d.syntax.Close
+
+	// expanded value
+	var builder strings.Builder
+
+	// iteration over value
+	for i := 0; i < len(value); {
+
+		switch {
+
+		// special character
+		case value[i] == '\\' && i+1 < len(value):
+
+			switch value[i+1] {
+
+			// new line
+			case 'n':
+				builder.WriteByte('\n')
+
+			// horizontal tab
+			case 't':
+				builder.WriteByte('\t')
+
+			// backslash
+			case '\\':
+				builder.WriteByte('\\')
+
+			// double quote
+			case '"':
+				builder.WriteByte('"')
+
+			// any
+			default:
+				builder.WriteString(value[i : i+2])
+			}
+
+			// skip special character
+			i += 2
+
+		// literal opening delimiter
+		case strings.HasPrefix(value[i:], open+open):
+
+			// add opening delimiter
+			builder.WriteString(open)
+
+			// skip doubled delimiter
+			i += 2 * len(open)
+
+		// start of variable
+		case strings.HasPrefix(value[i:], open):
+
+			// end of variable
+			end := strings.Index(value[i+len(open):], close)
+
+			// closing delimiter is missing
+			if end < 0 {
+				return "", fmt.Errorf("[%s] line %d: can't find the closing delimiter '%s'", file, line, close)
+			}
+
+			// reference as it is written
+			reference := value[i : i+len(open)+end+len(close)]
+
+			// variable, operator and its argument
+			variable, operator, argument := splitReference(d.syntax, value[i+len(open):i+len(open)+end])
+
+			// empty variable name
+			if len(variable) == 0 {
+				return "", fmt.Errorf("[%s] line %d: variable name is empty", file, line)
+			}
+
+			// variable value
+			resolved, err := d.lookup(file, line, variable, operator, argument, reference)
+			if err != nil {
+				return "", err
+			}
+
+			// add variable value
+			builder.WriteString(resolved)
+
+			// increase number of expansions
+			d.expansions++
+
+			// skip reference
+			i += len(reference)
+
+		// any
+		default:
+
+			// add character
+			builder.WriteByte(value[i])
+
+			// next character
+			i++
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// lookup returns the value of the variable from the payload list, JSON values,
+// environment variables, the default value of the reference or according to the policy
+// for undefined variables, unless the reference requires the variable.
+func (d *delimited) lookup(file string, line int, variable, operator, argument, reference string) (string, error) {
+
+	// variable exists in the payload list
+	if i, ok := d.index[d.resolver.indexKey(variable)]; ok {
+		return d.resolve(i)
+	}
+
+	// secret from the provider
+	secret, ok, err := d.resolver.secret(file, line, variable)
+	if err != nil {
+		return "", fmt.Errorf("[%s] line %d: %s", file, line, err)
+	}
+
+	// provider exists
+	if ok {
+		return secret, nil
+	}
+
+	// value of the data map
+	field, ok, err := d.resolver.dataValue(variable)
+	if err != nil {
+		return "", fmt.Errorf("[%s] line %d: %s", file, line, err)
+	}
+
+	// data map is referenced
+	if ok {
+		return field, nil
+	}
+
+	// variable refers to a field of JSON value
+	if path := strings.Split(variable, "."); len(path) > 1 {
+
+		// JSON value
+		var data string
+
+		// JSON value status
+		var ok bool
+
+		// JSON value from the payload list
+		if i, exists := d.index[d.resolver.indexKey(path[0])]; exists {
+
+			// resolve JSON value
+			value, err := d.resolve(i)
+			if err != nil {
+				return "", err
+			}
+
+			// update JSON value
+			data, ok = value, true
+
+		} else {
+
+			// JSON value from environment variables
+			data, ok = d.resolver.lookup(path[0])
+		}
+
+		// JSON value exists
+		if ok {
+
+			// field value
+			field, err := jsonField(data, path)
+			if err != nil {
+				return "", fmt.Errorf("[%s] line %d: %s", file, line, err)
+			}
+
+			return field, nil
+		}
+	}
+
+	// variable value from environment variables
+	if value, ok := d.resolver.lookup(variable); ok {
+		return value, nil
+	}
+
+	// default value is written in the reference
+	if operator == DefaultOperator {
+		return d.expand(file, line, argument)
+	}
+
+	// error message is written in the reference
+	if operator == RequiredOperator {
+		return "", fmt.Errorf("[%s] line %d: %s", file, line, requiredError(variable, argument))
+	}
+
+	// value of the variable that does not exist
+	value, ok, err := d.resolver.undefined(variable)
+	if err != nil {
+		return "", fmt.Errorf("[%s] line %d: %s", file, line, err)
+	}
+
+	// reference is left as it is written
+	if !ok {
+		return reference, nil
+	}
+
+	return value, nil
+}
@@ -0,0 +1,177 @@
+package parser
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Dialect is a syntax of files with environment variables.
+type Dialect int
+
+const (
+
+	// DialectDefault is the native syntax with { KEY } references and backslash escapes.
+	DialectDefault Dialect = iota
+
+	// DialectKubernetes is the syntax of the container environment in Kubernetes:
+	// $(KEY) references, $$ escapes, unresolvable references are left literal.
+	DialectKubernetes
+
+	// DialectDocker is the strict syntax of docker and compose env files: KEY=value
+	// without spaces around the equal sign, values are taken as they are written,
+	// every key is exported.
+	DialectDocker
+
+	// DialectDotenvExpand is the syntax of dotenv-expand for files shared with Node.js services:
+	// $KEY and ${KEY:-default} references expanded from right to left, \$ escapes,
+	// missing variables become empty.
+	DialectDotenvExpand
+)
+
+// Syntax is the way lines and references are written.
+type Syntax struct {
+
+	// dialect
+	Dialect Dialect
+
+	// custom opening delimiter of references, empty for the dialect ones
+	Open string
+
+	// custom closing delimiter of references
+	Close string
+
+	// way literal curly braces are written in the default dialect
+	Braces BraceMode
+
+	// comments after values are stripped
+	Comments bool
+
+	// $KEY and ${KEY} references are resolved in the default dialect
+	Dollar bool
+
+	// quotes, multi-line values, include directives, sections and conditions are not recognized
+	Legacy bool
+}
+
+// DialectRules are the rules of a custom dialect: how lines are split into keys, values and directives,
+// how references are written and how they are replaced with values and special characters unescaped.
+// RegisterDialect adds custom dialects next to the built-in ones.
+type DialectRules interface {
+
+	// Name returns the name of the dialect.
+	Name() string
+
+	// ParseLine parses the line of the document, the line number and text are set by the caller.
+	ParseLine(line int, text string) Node
+
+	// References returns references to variables in the value starting at the column.
+	References(value string, column int) []Reference
+
+	// Expand replaces references with values and unescapes special characters,
+	// lookup returns variables of the environment.
+	Expand(filename string, payloads []Payload, lookup func(variable string) (string, bool)) ([]Payload, error)
+}
+
+// builtins are the names of the built-in dialects, the position in the list is the dialect.
+var builtins = []string{"default", "kubernetes", "docker", "dotenv-expand"}
+
+// dialects are the rules of registered custom dialects, they follow the built-in ones.
+var dialects []DialectRules
+
+// dialectsMu is the registry access synchronization.
+var dialectsMu sync.RWMutex
+
+// RegisterDialect adds a custom dialect to the registry and returns it for envfile.WithDialect,
+// the name must be unique.
+func RegisterDialect(rules DialectRules) (Dialect, error) {
+
+	// lock registry
+	dialectsMu.Lock()
+
+	// deferred unlock of registry
+	defer dialectsMu.Unlock()
+
+	// name is already used
+	if _, ok := lookupDialect(rules.Name()); ok {
+		return 0, fmt.Errorf("dialect '%s' is already registered", rules.Name())
+	}
+
+	// add dialect to registry
+	dialects = append(dialects, rules)
+
+	return Dialect(len(builtins) + len(dialects) - 1), nil
+}
+
+// LookupDialect returns the registered dialect by name, e.g. "docker".
+func LookupDialect(name string) (Dialect, bool) {
+
+	// lock registry
+	dialectsMu.RLock()
+
+	// deferred unlock of registry
+	defer dialectsMu.RUnlock()
+
+	return lookupDialect(name)
+}
+
+// lookupDialect returns the dialect by name, the registry is locked by the caller.
+func lookupDialect(name string) (Dialect, bool) {
+
+	// iterating over built-in dialects
+	for i, builtin := range builtins {
+
+		// dialect is found
+		if builtin == name {
+			return Dialect(i), true
+		}
+	}
+
+	// iterating over custom dialects
+	for i, rules := range dialects {
+
+		// dialect is found
+		if rules.Name() == name {
+			return Dialect(len(builtins) + i), true
+		}
+	}
+
+	return 0, false
+}
+
+// Custom returns the rules of the dialect registered by RegisterDialect.
+func (d Dialect) Custom() (DialectRules, bool) {
+
+	// built-in dialect
+	if int(d) < len(builtins) {
+		return nil, false
+	}
+
+	// lock registry
+	dialectsMu.RLock()
+
+	// deferred unlock of registry
+	defer dialectsMu.RUnlock()
+
+	// dialect is not registered
+	if int(d)-len(builtins) >= len(dialects) {
+		return nil, false
+	}
+
+	return dialects[int(d)-len(builtins)], true
+}
+
+// String returns the name of the dialect.
+func (d Dialect) String() string {
+
+	// built-in dialect
+	if d >= 0 && int(d) < len(builtins) {
+		return builtins[d]
+	}
+
+	// custom dialect
+	if rules, ok := d.Custom(); ok {
+		return rules.Name()
+	}
+
+	return "default"
+}
@@ -0,0 +1,5 @@
+// Package parser reads env files into documents and payloads, replaces references with values, and writes
+// them back and into other formats without touching the environment of the process or the file system, so programs
+// built for sandboxes and embedded targets can parse env files; package envfile loads them
+// on top of it.
+package parser
@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestImports tests that the package does not depend on the environment of the process.
+func TestImports(t *testing.T) {
+
+	// source files of the package
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("error listing source files: %v", err)
+	}
+
+	// iterating over source files
+	for _, filename := range files {
+
+		// tests are not part of the package
+		if strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
+
+		// imports of the file
+		file, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.ImportsOnly)
+		if err != nil {
+			t.Fatalf("error parsing %s: %v", filename, err)
+		}
+
+		// iterating over imports
+		for _, spec := range file.Imports {
+
+			// imported package
+			path, _ := strconv.Unquote(spec.Path.Value)
+
+			// package touches the environment or the file system of the process
+			if path == "os" || path == "os/exec" || path == "syscall" {
+				t.Errorf("expected %s not to import %s", filename, path)
+			}
+		}
+	}
+}
@@ -0,0 +1,621 @@
+package parser
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"unicode"
+)
+
+// NodeKind is a kind of line in the document.
+type NodeKind string
+
+const (
+
+	// NodeBlank is an empty line or a line of spaces.
+	NodeBlank NodeKind = "blank"
+
+	// NodeComment is a line starting with the number sign.
+	NodeComment NodeKind = "comment"
+
+	// NodeEntry is a line with a key and a value.
+	NodeEntry NodeKind = "entry"
+
+	// NodeInvalid is a line that can't be split into key and value.
+	NodeInvalid NodeKind = "invalid"
+
+	// NodeContinuation is a line of a multi-line value, including the closing one.
+	NodeContinuation NodeKind = "continuation"
+
+	// NodeInclude is a line including another file, the path is the value.
+	NodeInclude NodeKind = "include"
+
+	// NodeSection is a line starting the keys of a profile, the profile name is the value.
+	NodeSection NodeKind = "section"
+)
+
+// Span is a range of columns in the line, counted in bytes from zero, the end is exclusive.
+type Span struct {
+
+	// first column
+	Start int `json:"start"`
+
+	// column after the last one
+	End int `json:"end"`
+}
+
+// Reference is a reference to a variable in the value.
+type Reference struct {
+
+	// variable name
+	Name string `json:"name"`
+
+	// position of the reference including delimiters
+	Span Span `json:"span"`
+}
+
+// Node is a line of the document.
+type Node struct {
+
+	// kind of line
+	Kind NodeKind `json:"kind"`
+
+	// line number in file
+	Line int `json:"line"`
+
+	// line as it is written, without the line ending
+	Text string `json:"text"`
+
+	// comment text after the number sign, also of a comment after the value
+	Comment string `json:"comment,omitempty"`
+
+	// export status
+	Export bool `json:"export,omitempty"`
+
+	// overload status
+	Overload bool `json:"overload,omitempty"`
+
+	// conditional assignment status
+	Conditional bool `json:"conditional,omitempty"`
+
+	// raw directive status, also set for single-quoted values
+	Literal bool `json:"literal,omitempty"`
+
+	// quote enclosing the value, " or ', the value and its position exclude the quotes
+	Quote string `json:"quote,omitempty"`
+
+	// delimiter of the multi-line value written on the following lines, e.g. EOF for <<EOF
+	Heredoc string `json:"heredoc,omitempty"`
+
+	// key
+	Key string `json:"key,omitempty"`
+
+	// position of the key
+	KeySpan Span `json:"keySpan"`
+
+	// value as it is written
+	Value string `json:"value,omitempty"`
+
+	// position of the value
+	ValueSpan Span `json:"valueSpan"`
+
+	// references to variables in the value
+	References []Reference `json:"references,omitempty"`
+
+	// problem with the line
+	Error string `json:"error,omitempty"`
+}
+
+// Document is a parsed file with environment variables, keeping every line as it is written.
+type Document struct {
+
+	// file name
+	Name string `json:"name"`
+
+	// lines of the file
+	Nodes []Node `json:"nodes"`
+
+	// syntax of the file
+	Syntax Syntax `json:"-"`
+
+	// file system included files are read from, the disk if nil
+	FS fs.FS `json:"-"`
+
+	// line ending of the file, the first one found
+	newline string
+
+	// last line ends with the line ending
+	final bool
+}
+
+// EncodeAST encodes the document with comments, directives, references and positions as JSON,
+// nodes are in the order of lines.
+func EncodeAST(doc *Document) ([]byte, error) {
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// Err returns the problem with the first invalid line or nil.
+func (d *Document) Err() error {
+
+	// iterating over a list of nodes
+	for _, node := range d.Nodes {
+
+		// line has a problem
+		if len(node.Error) > 0 {
+			return fmt.Errorf("[%s] line %d: %s", d.Name, node.Line, node.Error)
+		}
+	}
+
+	return nil
+}
+
+// Read reads the document written with the syntax from the reader, the name is used in error messages.
+func Read(filename string, reader io.Reader, syn Syntax) (*Document, error) {
+
+	// document
+	doc := &Document{Name: filename, Syntax: syn}
+
+	// line by line file reading
+	scanner := bufio.NewScanner(reader)
+
+	// split lines regardless of the line ending, remembering the line ending of the file
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+
+		// next line
+		advance, token, err := ScanLines(data, atEOF)
+
+		// line is found
+		if token != nil {
+
+			// line ending after the line
+			ending := string(data[len(token):advance])
+
+			// first line ending of the file
+			if len(doc.newline) == 0 {
+				doc.newline = ending
+			}
+
+			// update status of the last line
+			doc.final = len(ending) > 0
+		}
+
+		return advance, token, err
+	})
+
+	// iterate through the lines of the file
+	for scanner.Scan() {
+
+		// add node to document
+		doc.Nodes = append(doc.Nodes, ParseLine(doc.Syntax, len(doc.Nodes)+1, scanner.Text()))
+	}
+
+	// mark lines of multi-line values
+	doc.markHeredocs()
+
+	return doc, scanner.Err()
+}
+
+// ParseLine parses the line of a document written with the syntax.
+func ParseLine(syn Syntax, line int, text string) Node {
+
+	// custom dialect has its own line rules
+	if rules, ok := syn.Dialect.Custom(); ok {
+
+		// parse line
+		node := rules.ParseLine(line, text)
+
+		// set line as it is written
+		node.Line, node.Text = line, text
+
+		return node
+	}
+
+	// node
+	node := Node{Line: line, Text: text}
+
+	// current line without leading spaces
+	current := strings.TrimLeftFunc(text, unicode.IsSpace)
+
+	// position of the current line
+	offset := len(text) - len(current)
+
+	// current line without trailing spaces, except for docker dialect keeping values as they are written
+	if syn.Dialect != DialectDocker {
+		current = strings.TrimRightFunc(current, unicode.IsSpace)
+	}
+
+	// blank line
+	if len(current) == 0 {
+		node.Kind = NodeBlank
+		return node
+	}
+
+	// comment
+	if strings.HasPrefix(current, "#") {
+		node.Kind = NodeComment
+		node.Comment = current[1:]
+		return node
+	}
+
+	// line with a key and a value
+	node.Kind = NodeEntry
+
+	// position of the equal sign
+	position := strings.Index(current, "=")
+
+	// docker dialect has its own line rules
+	if syn.Dialect == DialectDocker {
+
+		// every key is exported
+		node.Export = true
+
+		// key without value is taken from environment variables
+		if position < 0 {
+			position = len(current)
+		}
+
+		// set key name
+		node.Key = current[:position]
+		node.KeySpan = Span{offset, offset + position}
+
+		// spaces in key name or around the equal sign
+		if strings.IndexFunc(node.Key, unicode.IsSpace) >= 0 {
+			node.Error = fmt.Sprintf("key '%s' contains spaces", node.Key)
+			return node
+		}
+
+		// set value as it is written
+		if position < len(current) {
+			node.Value = current[position+1:]
+			node.ValueSpan = Span{offset + position + 1, len(text)}
+		}
+
+		return node
+	}
+
+	// include directive
+	if start, ok := includePath(current); position < 0 && ok && !syn.Legacy {
+
+		// position of the path
+		start, end := trimSpan(current, start, len(current))
+
+		// set include kind and path
+		node.Kind = NodeInclude
+		node.Value = current[start:end]
+		node.ValueSpan = Span{offset + start, offset + end}
+
+		// path enclosed in quotes
+		if _, ok := quotedValue(node.Value); ok {
+			node.Value = node.Value[1 : len(node.Value)-1]
+			node.ValueSpan = Span{node.ValueSpan.Start + 1, node.ValueSpan.End - 1}
+		}
+
+		return node
+	}
+
+	// section of a profile
+	if match := sectionHeader.FindStringSubmatch(current); position < 0 && match != nil && !syn.Legacy {
+
+		// set section kind and profile name
+		node.Kind = NodeSection
+		node.Value = match[1]
+		node.ValueSpan = Span{offset + 1, offset + 1 + len(match[1])}
+
+		return node
+	}
+
+	// could not split current line
+	if position < 0 {
+		node.Kind = NodeInvalid
+		node.Error = "can't split line into key and value"
+		return node
+	}
+
+	// position of the key
+	start, end := trimSpan(current, 0, position)
+
+	// export directive
+	if strings.HasPrefix(strings.ToLower(current[start:end]), "export") {
+
+		// update position of the key
+		start, end = trimSpan(current, start+6, end)
+
+		// set export status
+		node.Export = true
+	}
+
+	// overload directive
+	if strings.HasPrefix(strings.ToLower(current[start:end]), "overload") {
+
+		// update position of the key
+		start, end = trimSpan(current, start+8, end)
+
+		// set overload status
+		node.Overload = true
+	}
+
+	// raw directive followed by a space
+	if strings.HasPrefix(strings.ToLower(current[start:end]), "raw") && start+3 < end &&
+		unicode.IsSpace(rune(current[start+3])) && !syn.Legacy {
+
+		// update position of the key
+		start, end = trimSpan(current, start+3, end)
+
+		// set raw status
+		node.Literal = true
+	}
+
+	// conditional assignment operator
+	if strings.HasSuffix(current[start:end], "?") && !syn.Legacy {
+
+		// update position of the key
+		start, end = trimSpan(current, start, end-1)
+
+		// conditional key is exported only if it is not defined yet
+		node.Export = true
+
+		// set conditional status
+		node.Conditional = true
+
+		// conditional assignment can't be overloaded
+		if node.Overload {
+			node.Error = "conditional assignment '?=' can't be overloaded"
+		}
+	}
+
+	// set key name
+	node.Key = current[start:end]
+	node.KeySpan = Span{offset + start, offset + end}
+
+	// position of the value
+	start, end = trimSpan(current, position+1, len(current))
+
+	// set value
+	node.Value = current[start:end]
+	node.ValueSpan = Span{offset + start, offset + end}
+
+	// comment after the value
+	if i := inlineComment(node.Value); syn.Comments && i >= 0 {
+
+		// set comment text
+		node.Comment = node.Value[i+1:]
+
+		// value before the comment
+		start, end = trimSpan(current, start, start+i)
+		node.Value = current[start:end]
+		node.ValueSpan = Span{offset + start, offset + end}
+	}
+
+	// value enclosed in quotes
+	if quote, ok := quotedValue(node.Value); ok && syn.Escapes() && !node.Literal && !syn.Legacy {
+
+		// set quote
+		node.Quote = quote
+
+		// value without quotes
+		node.Value = node.Value[1 : len(node.Value)-1]
+		node.ValueSpan = Span{node.ValueSpan.Start + 1, node.ValueSpan.End - 1}
+
+		// single-quoted value is taken as it is written
+		node.Literal = quote == "'"
+	}
+
+	// raw value has no references
+	if node.Literal {
+		return node
+	}
+
+	// backslash at the end escapes nothing
+	if syn.Escapes() && trailingBackslash(node.Value) && !syn.Legacy {
+		node.Error = `value ends with an unescaped backslash, write '\\' for a literal one or use the raw directive`
+	}
+
+	// set references
+	node.References = ScanReferences(syn, node.Value, node.ValueSpan.Start)
+
+	return node
+}
+
+// trimSpan moves the start and end of the span in the text to exclude spaces.
+func trimSpan(text string, start, end int) (int, int) {
+
+	// text of the span
+	span := text[start:end]
+
+	// skip leading spaces
+	start += len(span) - len(strings.TrimLeftFunc(span, unicode.IsSpace))
+
+	// skip trailing spaces
+	end -= len(span) - len(strings.TrimRightFunc(span, unicode.IsSpace))
+
+	// span consists of spaces only
+	if end < start {
+		end = start
+	}
+
+	return start, end
+}
+
+// ScanReferences finds references to variables in the value written with the syntax starting at the column.
+func ScanReferences(syn Syntax, value string, column int) []Reference {
+
+	// custom dialect has its own reference syntax
+	if rules, ok := syn.Dialect.Custom(); ok {
+		return rules.References(value, column)
+	}
+
+	// $KEY and ${KEY} references of the default dialect
+	if syn.Dollar && syn.Dialect == DialectDefault {
+		return scanDollarReferences(syn, value, column)
+	}
+
+	// references list
+	var references []Reference
+
+	// references with custom delimiters
+	if len(syn.Open) > 0 {
+
+		// iteration over value
+		for i := 0; i < len(value); i++ {
+
+			switch {
+
+			// escaped character
+			case value[i] == '\\':
+				i++
+
+			// literal opening delimiter
+			case strings.HasPrefix(value[i:], syn.Open+syn.Open):
+				i += 2*len(syn.Open) - 1
+
+			// start of variable
+			case strings.HasPrefix(value[i:], syn.Open):
+
+				// end of variable
+				end := strings.Index(value[i+len(syn.Open):], syn.Close)
+
+				// closing delimiter is missing
+				if end < 0 {
+					return references
+				}
+
+				// position after the reference
+				after := i + len(syn.Open) + end + len(syn.Close)
+
+				// add reference to list
+				references = append(references, Reference{
+					Name: referenceName(value[i+len(syn.Open) : i+len(syn.Open)+end]),
+					Span: Span{column + i, column + after},
+				})
+
+				// skip variable
+				i = after - 1
+			}
+		}
+
+		return references
+	}
+
+	switch syn.Dialect {
+
+	// $(KEY) references
+	case DialectKubernetes:
+
+		// iteration over value
+		for i := 0; i < len(value)-1; i++ {
+
+			// not a dollar sign
+			if value[i] != '$' {
+				continue
+			}
+
+			// escaped dollar sign
+			if value[i+1] == '$' {
+				i++
+				continue
+			}
+
+			// not a start of variable
+			if value[i+1] != '(' {
+				continue
+			}
+
+			// end of variable
+			end := strings.IndexByte(value[i:], ')')
+
+			// closing parenthesis is missing
+			if end < 0 {
+				break
+			}
+
+			// add reference to list
+			references = append(references, Reference{
+				Name: value[i+2 : i+end],
+				Span: Span{column + i, column + i + end + 1},
+			})
+
+			// skip variable
+			i += end
+		}
+
+	// $KEY and ${KEY:-default} references
+	case DialectDotenvExpand:
+
+		// iteration over value
+		for i := 0; i < len(value); i++ {
+
+			// escaped character
+			if value[i] == '\\' {
+				i++
+				continue
+			}
+
+			// not a dollar sign
+			if value[i] != '$' {
+				continue
+			}
+
+			// reference at the dollar sign
+			variable, _, end := ParseDotenvReference(value, i)
+
+			// dollar sign is not followed by a variable name
+			if len(variable) == 0 {
+				continue
+			}
+
+			// add reference to list, references in the default are found by the next iterations
+			references = append(references, Reference{
+				Name: variable,
+				Span: Span{column + i, column + end},
+			})
+		}
+
+	// values are taken as they are written
+	case DialectDocker:
+
+	// { KEY } references
+	default:
+
+		// literal curly braces of the mode are rewritten as doubled ones, keeping positions
+		if syn.Braces != BracesDoubled {
+			value = positionalBraces(syn.Braces, value)
+		}
+
+		// iteration over value
+		for i := 0; i < len(value); i++ {
+
+			// escaped curly brace
+			if (value[i] == '{' || value[i] == '}') && i+1 < len(value) && value[i+1] == value[i] {
+				i++
+				continue
+			}
+
+			// not a start of variable
+			if value[i] != '{' {
+				continue
+			}
+
+			// end of variable
+			end := strings.IndexByte(value[i:], '}')
+
+			// closing curly brace is missing
+			if end < 0 {
+				break
+			}
+
+			// add reference to list
+			references = append(references, Reference{
+				Name: referenceName(value[i+1 : i+end]),
+				Span: Span{column + i, column + i + end + 1},
+			})
+
+			// skip variable
+			i += end
+		}
+	}
+
+	return references
+}
@@ -0,0 +1,52 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRead tests reading of a document from memory and writing it back.
+func TestRead(t *testing.T) {
+
+	// file content
+	content := "# comment\r\nexport URL = http://{ HOST }\r\nKEY = value # explains\r\n"
+
+	// read document with inline comments
+	doc, err := Read("memory", strings.NewReader(content), Syntax{Comments: true})
+	if err != nil {
+		t.Fatalf("error reading document: %v", err)
+	}
+
+	// entry with a reference
+	if entry := doc.Nodes[1]; !entry.Export || entry.Key != "URL" || len(entry.References) != 1 || entry.References[0].Name != "HOST" {
+		t.Errorf("expected exported URL referencing HOST, got %+v", entry)
+	}
+
+	// comment after the value is stripped with the syntax
+	if entry := doc.Nodes[2]; entry.Value != "value" || entry.Comment != " explains" {
+		t.Errorf("expected value 'value' with comment ' explains', got '%s' with '%s'", entry.Value, entry.Comment)
+	}
+
+	// document is written back as it is read
+	if text := string(doc.Bytes()); text != content {
+		t.Errorf("expected document to be written back as %q, got %q", content, text)
+	}
+}
+
+// TestDialectNames tests names of the built-in dialects.
+func TestDialectNames(t *testing.T) {
+
+	// iterating over built-in dialects
+	for _, dialect := range []Dialect{DialectDefault, DialectKubernetes, DialectDocker, DialectDotenvExpand} {
+
+		// dialect is not found by its name
+		if found, ok := LookupDialect(dialect.String()); !ok || found != dialect {
+			t.Errorf("expected dialect %s to be found by its name, got %d", dialect, found)
+		}
+
+		// built-in dialect has no custom rules
+		if _, ok := dialect.Custom(); ok {
+			t.Errorf("expected dialect %s to have no custom rules", dialect)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+)
+
+// dollarReference returns the variable name of the reference starting with the dollar sign
+// at the position and the position after the reference, the name is empty if there is no reference.
+func dollarReference(value string, position int) (string, int) {
+
+	// reference in curly braces
+	if strings.HasPrefix(value[position+1:], "{") {
+
+		// end of variable
+		end := strings.IndexByte(value[position:], '}')
+
+		// closing curly brace is missing
+		if end < 0 {
+			return "", position + 1
+		}
+
+		return strings.TrimSpace(value[position+2 : position+end]), position + end + 1
+	}
+
+	// end of variable name
+	end := position + 1
+	for end < len(value) && (value[end] == '_' || isLetter(value[end]) || end > position+1 && isDigit(value[end])) {
+		end++
+	}
+
+	return value[position+1 : end], end
+}
+
+// isLetter reports whether the byte is an ASCII letter.
+func isLetter(c byte) bool {
+	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z'
+}
+
+// isDigit reports whether the byte is an ASCII digit.
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// RewriteDollar rewrites $KEY and ${KEY} references of the value with the delimiters of the syntax,
+// and \$ as a dollar sign.
+func RewriteDollar(syn Syntax, value string) string {
+
+	// delimiters of references
+	open, close := "{", "}"
+	if len(syn.Open) > 0 {
+		open, close = syn.Open, syn.Close
+	}
+
+	// rewritten value
+	var builder strings.Builder
+
+	// iteration over value
+	for i := 0; i < len(value); i++ {
+
+		switch {
+
+		// escaped dollar sign
+		case value[i] == '\\' && i+1 < len(value) && value[i+1] == '$':
+			builder.WriteByte('$')
+			i++
+
+		// escaped character is kept for unescaping
+		case value[i] == '\\' && i+1 < len(value):
+			builder.WriteString(value[i : i+2])
+			i++
+
+		// reference
+		case value[i] == '$':
+
+			// variable of the reference
+			variable, end := dollarReference(value, i)
+
+			// dollar sign is not followed by a variable name
+			if len(variable) == 0 {
+				builder.WriteByte('$')
+				continue
+			}
+
+			// add reference with the delimiters
+			builder.WriteString(open + variable + close)
+
+			// skip reference
+			i = end - 1
+
+		// any
+		default:
+			builder.WriteByte(value[i])
+		}
+	}
+
+	return builder.String()
+}
+
+// dollarReferences finds $KEY and ${KEY} references in the value starting at the column and returns
+// them with the value where they are replaced with spaces, keeping positions of other references.
+func dollarReferences(value string, column int) ([]Reference, string) {
+
+	// references list
+	var references []Reference
+
+	// value without the references
+	rest := []byte(value)
+
+	// iteration over value
+	for i := 0; i < len(value); i++ {
+
+		switch {
+
+		// escaped dollar sign or backslash
+		case value[i] == '\\' && i+1 < len(value) && (value[i+1] == '$' || value[i+1] == '\\'):
+			i++
+
+		// reference
+		case value[i] == '$':
+
+			// variable of the reference
+			variable, end := dollarReference(value, i)
+
+			// dollar sign is not followed by a variable name
+			if len(variable) == 0 {
+				continue
+			}
+
+			// add reference to list
+			references = append(references, Reference{Name: referenceName(variable), Span: Span{column + i, column + end}})
+
+			// replace reference with spaces
+			copy(rest[i:end], strings.Repeat(" ", end-i))
+
+			// skip reference
+			i = end - 1
+		}
+	}
+
+	return references, string(rest)
+}
+
+// scanDollarReferences finds references of the default dialect in the value together with
+// $KEY and ${KEY} ones, in order of their positions.
+func scanDollarReferences(syn Syntax, value string, column int) []Reference {
+
+	// dollar references and the value without them
+	references, rest := dollarReferences(value, column)
+
+	// references of the syntax
+	syn.Dollar = false
+	references = append(references, ScanReferences(syn, rest, column)...)
+
+	// order of positions
+	sort.Slice(references, func(i, j int) bool {
+		return references[i].Span.Start < references[j].Span.Start
+	})
+
+	return references
+}
@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// EncodeDOT writes the dependency graph of the payloads in Graphviz DOT format:
+// an edge goes from the referencing key to the referenced variable,
+// variables taken from environment variables are drawn as dashed ellipses.
+// Nodes and edges follow the order of the payloads, so the output is the same on every run.
+func EncodeDOT(payloads []Payload, w io.Writer) error {
+
+	// buffered writer
+	writer := bufio.NewWriter(w)
+
+	// graph header
+	fmt.Fprintln(writer, "digraph envfile {")
+	fmt.Fprintln(writer, "\trankdir=LR;")
+	fmt.Fprintln(writer, "\tnode [shape=box];")
+
+	// iterating over a list of payloads
+	for _, payload := range payloads {
+
+		// key node
+		fmt.Fprintf(writer, "\t%q;\n", payload.Key)
+	}
+
+	// references by referencing key
+	references := References(payloads)
+
+	// external variables already written
+	external := make(map[string]bool)
+
+	// edges already written
+	edges := make(map[[2]string]bool)
+
+	// iterating over a list of payloads
+	for _, payload := range payloads {
+
+		// iterating over references of the value
+		for _, reference := range references[payload.Key] {
+
+			// referenced variable, without the path to a JSON field
+			variable := strings.SplitN(reference.Name, ".", 2)[0]
+
+			// variable taken from environment variables is written once
+			if !reference.Internal && !external[variable] {
+
+				// external node
+				fmt.Fprintf(writer, "\t%q [shape=ellipse, style=dashed];\n", variable)
+
+				// remember variable
+				external[variable] = true
+			}
+
+			// edge is already written
+			if edges[[2]string{payload.Key, variable}] {
+				continue
+			}
+
+			// dependency edge
+			fmt.Fprintf(writer, "\t%q -> %q;\n", payload.Key, variable)
+
+			// remember edge
+			edges[[2]string{payload.Key, variable}] = true
+		}
+	}
+
+	// graph footer
+	fmt.Fprintln(writer, "}")
+
+	return writer.Flush()
+}
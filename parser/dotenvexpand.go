@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseDotenvReference parses $KEY, ${KEY} or ${KEY:-default} at the dollar sign,
+// it returns the variable name, the default value and the position after the reference.
+func ParseDotenvReference(value string, cursor int) (string, string, int) {
+
+	// position after the dollar sign
+	position := cursor + 1
+
+	// opening curly brace
+	braced := position < len(value) && value[position] == '{'
+	if braced {
+		position++
+	}
+
+	// position after the variable name
+	end := position
+	for end < len(value) && IsWordByte(value[end]) {
+		end++
+	}
+
+	// variable name
+	variable := value[position:end]
+
+	// default value
+	var fallback string
+
+	// default value of the variable, it ends before a curly brace or a backslash
+	if strings.HasPrefix(value[end:], ":-") {
+
+		// position after the default value
+		stop := end + 2
+		for stop < len(value) && value[stop] != '}' && value[stop] != '\\' {
+			stop++
+		}
+
+		// set default value
+		fallback = value[end+2 : stop]
+
+		// update position
+		end = stop
+	}
+
+	// closing curly brace
+	if braced && end < len(value) && value[end] == '}' {
+		end++
+	}
+
+	return variable, fallback, end
+}
+
+// IsWordByte reports whether the byte is a letter, a digit or an underscore.
+func IsWordByte(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// dotenvExpandLimit is the maximum number of substitutions in one value, dotenv-expand
+// expands substituted values again, so a key referencing itself would never stop.
+const dotenvExpandLimit = 1000
+
+// expandDotenv expands $KEY and ${KEY:-default} references the way dotenv-expand does:
+// references are replaced from right to left, so defaults are expanded before they are used,
+// environment variables take precedence over keys of the file, missing and empty variables
+// become the default or an empty string, and \$ is a literal dollar sign.
+func (r *Resolver) expandDotenv(filename string, payloads []Payload) ([]Payload, error) {
+
+	// values of the file by key
+	parsed := make(map[string]string, len(payloads))
+
+	// iterating over a list of payloads
+	for _, payload := range payloads {
+
+		// add value as it is written
+		parsed[payload.Key] = payload.Value
+	}
+
+	// iterating over a list of payloads
+	for i, payload := range payloads {
+
+		// raw value is taken as it is written
+		if payload.Literal {
+			continue
+		}
+
+		// expand value
+		value, ok := expandDotenvValue(payload.Value, func(variable string) string {
+
+			// variable value from environment variables
+			if value, _ := r.lookup(variable); len(value) > 0 {
+				return value
+			}
+
+			return parsed[variable]
+		})
+
+		// substitutions never stop
+		if !ok {
+			return nil, fmt.Errorf("[%s] line %d: key '%s' is used recursively", PayloadFile(payload, filename), payload.Line, payload.Key)
+		}
+
+		// unescape dollar signs
+		value = strings.Replace(value, `\$`, "$", -1)
+
+		// update value for the keys below
+		parsed[payload.Key] = value
+
+		// update payload
+		payloads[i].Value = value
+	}
+
+	return payloads, nil
+}
+
+// expandDotenvValue replaces references in the value from right to left using the lookup function,
+// it returns false when the number of substitutions exceeds the limit.
+func expandDotenvValue(value string, lookup func(string) string) (string, bool) {
+
+	// position before which references are searched, dollar signs after it are plain text
+	limit := len(value)
+
+	// cycle of substitutions
+	for count := 0; count < dotenvExpandLimit; {
+
+		// position of the last dollar sign not escaped with a backslash
+		cursor := strings.LastIndexByte(value[:limit], '$')
+		for cursor > 0 && value[cursor-1] == '\\' {
+			cursor = strings.LastIndexByte(value[:cursor-1], '$')
+		}
+
+		// nothing to replace
+		if cursor < 0 {
+			return value, true
+		}
+
+		// reference at the dollar sign
+		variable, fallback, end := ParseDotenvReference(value, cursor)
+
+		// dollar sign is not followed by a variable name
+		if len(variable) == 0 {
+
+			// search before the dollar sign
+			limit = cursor
+
+			continue
+		}
+
+		// variable value, missing and empty variables take the default
+		resolved := lookup(variable)
+		if len(resolved) == 0 {
+			resolved = fallback
+		}
+
+		// replace reference
+		value = value[:cursor] + resolved + value[end:]
+
+		// increase number of substitutions
+		count++
+
+		// substituted value is expanded again
+		limit = cursor + len(resolved)
+	}
+
+	return value, false
+}
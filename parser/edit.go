@@ -0,0 +1,198 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Position is a place in the document: line number from one and column in bytes from zero.
+type Position struct {
+
+	// line number
+	Line int `json:"line"`
+
+	// column in the line
+	Column int `json:"column"`
+}
+
+// Range is a part of the document between two positions, the end is exclusive.
+type Range struct {
+
+	// first position
+	Start Position `json:"start"`
+
+	// position after the last one
+	End Position `json:"end"`
+}
+
+// ApplyEdit replaces the range of the document with the text, re-parsing only the affected lines.
+// It returns keys whose values may have changed: keys defined on the affected lines and
+// keys referencing them directly or through other keys, in alphabetical order.
+func (d *Document) ApplyEdit(r Range, text string) ([]string, error) {
+
+	// range is outside of the document or reversed
+	if err := d.checkRange(r); err != nil {
+		return nil, err
+	}
+
+	// keys changed by the edit
+	changed := make(map[string]bool)
+
+	// text of the affected lines before and after the edit
+	var before, after string
+
+	// iterating over the affected lines
+	for i := r.Start.Line; i <= r.End.Line && i <= len(d.Nodes); i++ {
+
+		// current node
+		node := d.Nodes[i-1]
+
+		// key is defined on the affected line
+		if node.Kind == NodeEntry {
+			changed[node.Key] = true
+		}
+
+		// text before the edit
+		if i == r.Start.Line {
+			before = node.Text[:r.Start.Column]
+		}
+
+		// text after the edit
+		if i == r.End.Line {
+			after = node.Text[r.End.Column:]
+		}
+	}
+
+	// new lines replacing the affected ones
+	lines := strings.Split(strings.Replace(before+text+after, "\r\n", "\n", -1), "\n")
+
+	// new nodes
+	nodes := make([]Node, len(lines))
+
+	// iterating over new lines
+	for i, line := range lines {
+
+		// parse line
+		nodes[i] = ParseLine(d.Syntax, r.Start.Line+i, line)
+
+		// key is defined on the new line
+		if nodes[i].Kind == NodeEntry {
+			changed[nodes[i].Key] = true
+		}
+	}
+
+	// position after the affected lines
+	end := r.End.Line
+	if end > len(d.Nodes) {
+		end = len(d.Nodes)
+	}
+
+	// nodes after the affected lines
+	rest := append([]Node(nil), d.Nodes[end:]...)
+
+	// shift of line numbers after the affected lines
+	shift := r.Start.Line + len(nodes) - 1 - end
+
+	// iterating over nodes after the affected lines
+	for i := range rest {
+
+		// update line number
+		rest[i].Line += shift
+	}
+
+	// replace the affected lines
+	d.Nodes = append(append(d.Nodes[:r.Start.Line-1], nodes...), rest...)
+
+	// mark lines of multi-line values again, the edit may open or close one
+	d.markHeredocs()
+
+	return d.dependents(changed), nil
+}
+
+// checkRange reports whether the range can be applied to the document.
+func (d *Document) checkRange(r Range) error {
+
+	// reversed range
+	if r.End.Line < r.Start.Line || (r.End.Line == r.Start.Line && r.End.Column < r.Start.Column) {
+		return fmt.Errorf("[%s] invalid range: end is before start", d.Name)
+	}
+
+	// iterating over the positions of the range
+	for _, position := range []Position{r.Start, r.End} {
+
+		// line is outside of the document, the line after the last one is allowed for appending
+		if position.Line < 1 || position.Line > len(d.Nodes)+1 {
+			return fmt.Errorf("[%s] invalid range: line %d is outside of the document", d.Name, position.Line)
+		}
+
+		// length of the line
+		length := 0
+		if position.Line <= len(d.Nodes) {
+			length = len(d.Nodes[position.Line-1].Text)
+		}
+
+		// column is outside of the line
+		if position.Column < 0 || position.Column > length {
+			return fmt.Errorf("[%s] invalid range: column %d is outside of line %d",
+				d.Name, position.Column, position.Line)
+		}
+	}
+
+	return nil
+}
+
+// dependents extends the keys with the keys referencing them directly or through other keys.
+func (d *Document) dependents(keys map[string]bool) []string {
+
+	// cycle of adding referencing keys until nothing is added
+	for added := true; added; {
+
+		// nothing is added yet
+		added = false
+
+		// iterating over a list of nodes
+		for _, node := range d.Nodes {
+
+			// node is not an entry or the key is already in the list
+			if node.Kind != NodeEntry || keys[node.Key] {
+				continue
+			}
+
+			// iterating over references of the value
+			for _, reference := range node.References {
+
+				// referenced variable, without the path to a JSON field
+				variable := strings.SplitN(reference.Name, ".", 2)[0]
+
+				// key references a changed key
+				if keys[variable] {
+
+					// add key to list
+					keys[node.Key] = true
+
+					// update status
+					added = true
+
+					// exit loop
+					break
+				}
+			}
+		}
+	}
+
+	// keys list
+	list := make([]string, 0, len(keys))
+
+	// iterating over keys
+	for key := range keys {
+
+		// add key to list
+		list = append(list, key)
+	}
+
+	// sort keys
+	sort.Strings(list)
+
+	return list
+}
@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// EncodeEnvrc writes payloads as a direnv .envrc: exported and overloaded keys are exported,
+// conditional keys are exported only if they are not set yet, other keys stay shell variables.
+// Values are single-quoted, so the shell takes them as they are.
+func EncodeEnvrc(payloads []Payload, w io.Writer) error {
+
+	// buffered writer
+	writer := bufio.NewWriter(w)
+
+	// iterating over a list of payloads
+	for _, payload := range payloads {
+
+		// single-quoted value
+		value := "'" + strings.Replace(payload.Value, "'", `'\''`, -1) + "'"
+
+		switch {
+
+		// key is exported only if it is not set yet
+		case payload.Conditional:
+			fmt.Fprintf(writer, "[ -n \"${%s+x}\" ] || export %s=%s\n", payload.Key, payload.Key, value)
+
+		// key is exported
+		case payload.Export || payload.Overload:
+			fmt.Fprintf(writer, "export %s=%s\n", payload.Key, value)
+
+		// shell variable
+		default:
+			fmt.Fprintf(writer, "%s=%s\n", payload.Key, value)
+		}
+	}
+
+	return writer.Flush()
+}
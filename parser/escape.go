@@ -0,0 +1,85 @@
+package parser
+
+import "strings"
+
+// Escapes reports whether backslash escapes are processed in values of the syntax:
+// in the default dialect, with or without custom delimiters.
+func (s Syntax) Escapes() bool {
+	return s.Dialect == DialectDefault
+}
+
+// quotedValue returns the quote enclosing the value: a double-quoted value ends with a quote
+// that is not escaped, a single-quoted one ends with any single quote.
+func quotedValue(value string) (string, bool) {
+
+	// value is too short to be enclosed
+	if len(value) < 2 {
+		return "", false
+	}
+
+	switch {
+
+	// single-quoted value
+	case value[0] == '\'' && value[len(value)-1] == '\'':
+		return "'", true
+
+	// double-quoted value, the closing quote is not escaped
+	case value[0] == '"' && value[len(value)-1] == '"' && !trailingBackslash(value[1:len(value)-1]):
+		return `"`, true
+	}
+
+	return "", false
+}
+
+// trailingBackslash reports whether the value ends with a backslash that does not escape anything.
+func trailingBackslash(value string) bool {
+
+	// number of backslashes at the end of the value
+	count := len(value) - len(strings.TrimRight(value, `\`))
+
+	return count%2 == 1
+}
+
+// UnknownEscapes returns escape sequences of the value that are not special characters
+// of the syntax, they are kept as they are written.
+func UnknownEscapes(syn Syntax, value string) []string {
+
+	// sequences list
+	var sequences []string
+
+	// iteration over value
+	for i := 0; i+1 < len(value); i++ {
+
+		// not a backslash
+		if value[i] != '\\' {
+			continue
+		}
+
+		switch value[i+1] {
+
+		// new line, horizontal tab, backslash and double quote
+		case 'n', 't', '\\', '"':
+
+		// dollar sign escaped with a backslash when $KEY references are resolved
+		case '$':
+			if !syn.Dollar {
+				sequences = append(sequences, value[i:i+2])
+			}
+
+		// curly braces escaped with a backslash in the default dialect
+		case '{', '}':
+			if len(syn.Open) > 0 || syn.Braces == BracesDoubled {
+				sequences = append(sequences, value[i:i+2])
+			}
+
+		// any
+		default:
+			sequences = append(sequences, value[i:i+2])
+		}
+
+		// skip escaped character
+		i++
+	}
+
+	return sequences
+}
@@ -0,0 +1,517 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// unescape special characters
+var unescape = regexp.MustCompile(`\\.`)
+
+// Expand replaces references in the payloads with values following the syntax and unescapes special
+// characters, it returns the number of replaced references. Keys of the payloads come first, then
+// secrets, the data map, fields of JSON values and variables of the environment.
+func (r *Resolver) Expand(syn Syntax, filename string, payloads []Payload) ([]Payload, int, error) {
+
+	// custom dialect has its own expansion rules
+	if rules, ok := syn.Dialect.Custom(); ok {
+
+		// payloads with references replaced
+		payloads, err := rules.Expand(filename, payloads, r.lookup)
+
+		return payloads, 0, err
+	}
+
+	// kubernetes dialect has its own expansion rules
+	if syn.Dialect == DialectKubernetes {
+		return r.expandKubernetes(payloads), 0, nil
+	}
+
+	// dotenv-expand dialect has its own expansion rules
+	if syn.Dialect == DialectDotenvExpand {
+
+		// payloads with references replaced
+		payloads, err := r.expandDotenv(filename, payloads)
+
+		return payloads, 0, err
+	}
+
+	// docker dialect keeps values as they are written
+	if syn.Dialect == DialectDocker {
+		return payloads, 0, nil
+	}
+
+	// $KEY and ${KEY} references are resolved
+	if syn.Dollar {
+
+		// iterating over a list of payloads
+		for i, payload := range payloads {
+
+			// references are rewritten with the delimiters, raw value is taken as it is written
+			if !payload.Literal {
+				payloads[i].Value = RewriteDollar(syn, payload.Value)
+			}
+		}
+	}
+
+	// references with custom delimiters
+	if len(syn.Open) > 0 {
+		return r.expandDelimited(syn, filename, payloads)
+	}
+
+	// iterating over a list of payloads
+	for i, payload := range payloads {
+
+		// literal curly braces of the mode are rewritten as doubled ones, raw value is taken as it is written
+		if !payload.Literal {
+			payloads[i].Value = EscapeBraces(syn.Braces, payload.Value)
+		}
+	}
+
+	// number of replaced references
+	var expansions int
+
+	// cycle of changing variables to their values
+	for {
+
+		// temporary storage of variable names with their positions
+		temp := make(map[string][][2]int)
+
+		// iterating over a list of payloads
+		for _, payload := range payloads {
+
+			// raw value is taken as it is written
+			if payload.Literal {
+				continue
+			}
+
+			// previous character
+			var previous rune
+
+			// parts list
+			var parts []string
+
+			// character list
+			var chars []rune
+
+			// iteration over value
+			for _, current := range payload.Value {
+
+				// start of variable
+				if previous != '{' && current == '{' {
+
+					// list of characters is not empty
+					if len(chars) > 0 {
+
+						// combine characters and add to parts list
+						parts = append(parts, string(chars))
+
+						// clear the list of characters
+						chars = nil
+					}
+				}
+
+				// end of variable
+				if previous == '}' && current != '}' {
+
+					// list of characters is not empty
+					if len(chars) > 0 {
+
+						// combine characters and add to parts list
+						parts = append(parts, string(chars))
+
+						// clear the list of characters
+						chars = nil
+					}
+				}
+
+				// add the current character to the character list
+				chars = append(chars, current)
+
+				// change the previous character to the current one
+				previous = current
+			}
+
+			// list of characters is not empty
+			if len(chars) > 0 {
+
+				// combine characters and add to parts list
+				parts = append(parts, string(chars))
+			}
+
+			// offset to get the position of the variable in the original value
+			var offset int
+
+			// iteration by parts
+			for i, part := range parts {
+
+				// number of opening curly braces
+				opening := strings.Count(part, "{")
+
+				// number of closing curly braces
+				closing := strings.Count(part, "}")
+
+				// an even number of opening curly braces status
+				evenOpening := (opening % 2) == 0
+
+				// an even number of closing curly braces status
+				evenClosing := (closing % 2) == 0
+
+				// only open curly braces were found and their number is odd
+				if !evenOpening && evenClosing {
+
+					// there are more opening curly braces than closing curly braces
+					if opening > closing {
+
+						// current part is the last and is equal to the opening curly brace
+						if (i == len(parts)-1) && (part == "{") {
+							return nil, 0, fmt.Errorf("[%s] line %d: excess opening curly brace '{' in at the end",
+								PayloadFile(payload, filename), payload.Line)
+						}
+
+						return nil, 0, fmt.Errorf("[%s] line %d: can't find the closing curly brace '}'",
+							PayloadFile(payload, filename), payload.Line)
+					}
+
+					// there are fewer opening curly braces than closing curly braces
+					if opening < closing {
+						return nil, 0, fmt.Errorf("[%s] line %d: excess closing curly brace '}'", PayloadFile(payload, filename), payload.Line)
+					}
+				}
+
+				// only close curly braces were found and their number is odd
+				if evenOpening && !evenClosing {
+
+					// there are more opening curly braces than closing curly braces
+					if opening > closing {
+						return nil, 0, fmt.Errorf("[%s] line %d: excess opening curly brace '{'", PayloadFile(payload, filename), payload.Line)
+					}
+
+					// there are fewer opening curly braces than closing curly braces
+					if opening < closing {
+
+						// current part is the first and is equal to the closing curly brace
+						if (i == 0) && (part == "}") {
+							return nil, 0, fmt.Errorf("[%s] line %d: excess closing curly brace '}' at the beginning",
+								PayloadFile(payload, filename), payload.Line)
+						}
+
+						return nil, 0, fmt.Errorf("[%s] line %d: can't find the opening curly brace '{'",
+							PayloadFile(payload, filename), payload.Line)
+					}
+				}
+
+				// found open and close curly braces and their number is odd
+				if !evenOpening && !evenClosing {
+
+					// start of variable
+					start := offset + opening
+
+					// end of variable
+					end := offset + (len(part) - closing)
+
+					// variable
+					variable, _, _ := splitReference(syn, payload.Value[start:end])
+
+					// empty variable name
+					if len(variable) == 0 {
+						return nil, 0, fmt.Errorf("[%s] line %d: variable name is empty", PayloadFile(payload, filename), payload.Line)
+					}
+
+					// variable name is the same as the name of the current key
+					if r.sameKey(payload.Key, variable) {
+						return nil, 0, fmt.Errorf("[%s] line %d: key '%s' is used recursively",
+							PayloadFile(payload, filename), payload.Line, payload.Key)
+					}
+
+					// add a variable and its position to temporary storage
+					temp[payload.Key] = append(temp[payload.Key], [2]int{
+
+						// start of variable
+						start,
+
+						// end of variable
+						end,
+					})
+				}
+
+				// increase offset by part length
+				offset += len(part)
+			}
+		}
+
+		// temporary storage is not empty
+		if len(temp) > 0 {
+
+			// values before the cycle, to detect references to JSON fields that are never replaced
+			before := make([]string, len(payloads))
+			for i, payload := range payloads {
+				before[i] = payload.Value
+			}
+
+			// iterating over temporary storage
+			for variable, positions := range temp {
+
+				// iterating over a list of payloads
+				for i, payload := range payloads {
+
+					// key exists in the list of payloads
+					if payload.Key == variable {
+
+						// current line number
+						line := payload.Line
+
+						// iterating over variable positions
+						for i := len(positions) - 1; i >= 0; i-- {
+
+							// variable position
+							position := positions[i]
+
+							// start of variable
+							start := position[0]
+
+							// end of variable
+							end := position[1]
+
+							// variable, operator and its argument
+							variable, operator, argument := splitReference(syn, payload.Value[start:end])
+
+							// character list
+							var chars []rune
+
+							// add everything before the variable to the character list
+							chars = append(chars, []rune(payload.Value[:start-1])...)
+
+							// variable value
+							var value *string
+
+							// iterating over a list of payloads
+							for _, payload := range payloads {
+
+								// variable exists in the list of payloads
+								if r.sameKey(payload.Key, variable) {
+
+									// update variable value
+									value = &payload.Value
+
+									// raw value is escaped to be inserted as it is written
+									if payload.Literal {
+
+										// escaped value
+										escaped := strings.NewReplacer("\\", "\\\\", "{", "{{", "}", "}}").Replace(payload.Value)
+
+										// update variable value
+										value = &escaped
+									}
+
+									// exit loop
+									break
+								}
+							}
+
+							// variable is a URI of a secret
+							if value == nil {
+
+								// secret from the provider
+								secret, ok, err := r.secret(PayloadFile(payload, filename), line, variable)
+								if err != nil {
+									return nil, 0, fmt.Errorf("[%s] line %d: %s", PayloadFile(payload, filename), line, err)
+								}
+
+								// provider exists
+								if ok {
+
+									// escaped secret
+									escaped := strings.NewReplacer("\\", "\\\\", "{", "{{", "}", "}}").Replace(secret)
+
+									// update variable value
+									value = &escaped
+								}
+							}
+
+							// variable refers to the data map
+							if value == nil {
+
+								// value of the data map
+								field, ok, err := r.dataValue(variable)
+								if err != nil {
+									return nil, 0, fmt.Errorf("[%s] line %d: %s", PayloadFile(payload, filename), line, err)
+								}
+
+								// data map is referenced
+								if ok {
+
+									// escaped value
+									escaped := strings.NewReplacer("\\", "\\\\", "{", "{{", "}", "}}").Replace(field)
+
+									// update variable value
+									value = &escaped
+								}
+							}
+
+							// variable refers to a field of JSON value that still has references,
+							// the reference is left to be replaced in the next cycle
+							if value == nil && strings.Contains(variable, ".") && r.pendingJSON(syn, variable, payloads) {
+
+								// reference as it is written
+								reference := payload.Value[start-1 : end+1]
+
+								// update variable value
+								value = &reference
+							}
+
+							// variable refers to a field of JSON value
+							if value == nil && strings.Contains(variable, ".") {
+
+								// field value
+								field, ok, err := r.extractJSON(variable, payloads)
+								if err != nil {
+									return nil, 0, fmt.Errorf("[%s] line %d: %s", PayloadFile(payload, filename), line, err)
+								}
+
+								// JSON value exists
+								if ok {
+
+									// update variable value
+									value = &field
+								}
+							}
+
+							// variable value is missing
+							if value == nil {
+
+								// variable value from environment variables
+								value, ok := r.lookup(variable)
+
+								// variable does not exist, default value is written in the reference
+								if !ok && operator == DefaultOperator {
+
+									// update variable value
+									value = argument
+
+								} else if !ok && operator == RequiredOperator {
+
+									// error message is written in the reference
+									return nil, 0, fmt.Errorf("[%s] line %d: %s", PayloadFile(payload, filename), line, requiredError(variable, argument))
+
+								} else if !ok {
+
+									// value of the variable that does not exist
+									undefined, ok, err := r.undefined(variable)
+									if err != nil {
+										return nil, 0, fmt.Errorf("[%s] line %d: %s", PayloadFile(payload, filename), line, err)
+									}
+
+									// reference is escaped to be left as it is written
+									if !ok {
+										undefined = strings.NewReplacer("{", "{{", "}", "}}").Replace(payload.Value[start-1 : end+1])
+									}
+
+									// update variable value
+									value = undefined
+								}
+
+								// add variable value to character list
+								chars = append(chars, []rune(value)...)
+
+								// increase number of expansions
+								expansions++
+
+							} else {
+
+								// add variable value to character list
+								chars = append(chars, []rune(*value)...)
+
+								// increase number of expansions, unless the reference is left for the next cycle
+								if *value != payload.Value[start-1:end+1] {
+									expansions++
+								}
+							}
+
+							// add everything after the variable to the character list
+							chars = append(chars, []rune(payload.Value[end+1:])...)
+
+							// combine characters and update value
+							payload.Value = string(chars)
+						}
+					}
+
+					// update payload
+					payloads[i] = payload
+				}
+			}
+
+			// values are not changed by the cycle
+			unchanged := true
+			for i, payload := range payloads {
+				unchanged = unchanged && payload.Value == before[i]
+			}
+
+			// iterating over a list of payloads
+			for _, payload := range payloads {
+
+				// references are left for the next cycle, but none is replaced: JSON values reference each other
+				if _, ok := temp[payload.Key]; ok && unchanged {
+					return nil, 0, fmt.Errorf("[%s] line %d: key '%s' is used recursively", PayloadFile(payload, filename), payload.Line, payload.Key)
+				}
+			}
+
+		} else {
+
+			// exit loop
+			break
+		}
+	}
+
+	// iterating over a list of payloads
+	for i, payload := range payloads {
+
+		// raw value is taken as it is written
+		if payload.Literal {
+			continue
+		}
+
+		// unescape the open curly brace
+		payload.Value = strings.ReplaceAll(payload.Value, "{{", "{")
+
+		// unescape the close curly brace
+		payload.Value = strings.ReplaceAll(payload.Value, "}}", "}")
+
+		// unescape the special characters
+		payload.Value = unescape.ReplaceAllStringFunc(payload.Value, func(match string) string {
+
+			switch strings.TrimPrefix(match, "\\") {
+
+			// new line
+			case "n":
+				return "\n"
+
+			// horizontal tab
+			case "t":
+				return "\t"
+
+			// backslash
+			case "\\":
+				return "\\"
+
+			// double quote, kept as it is written by the legacy syntax
+			case `"`:
+				if syn.Legacy {
+					return match
+				}
+
+				return `"`
+
+			// any
+			default:
+				return match
+			}
+		})
+
+		// update payload
+		payloads[i] = payload
+	}
+
+	return payloads, expansions, nil
+}
@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// hclEscape escapes the value for a quoted HCL string, template sequences included.
+var hclEscape = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+	"${", "$${",
+	"%{", "%%{",
+)
+
+// EncodeHCL writes exported and overloaded keys as the env stanza of a Nomad job file:
+// env { KEY = "value" }, with equal signs aligned the way hclfmt does.
+func EncodeHCL(payloads []Payload, w io.Writer) error {
+
+	// keys written to the stanza
+	var keys []Payload
+
+	// width of the longest key
+	var width int
+
+	// iterating over a list of payloads
+	for _, payload := range payloads {
+
+		// key is local to the file
+		if !payload.Export && !payload.Overload {
+			continue
+		}
+
+		// key starting with a digit is not an HCL identifier
+		if payload.Key[0] >= '0' && payload.Key[0] <= '9' {
+			return fmt.Errorf("line %d: key '%s' is not a valid HCL identifier", payload.Line, payload.Key)
+		}
+
+		// update width
+		if len(payload.Key) > width {
+			width = len(payload.Key)
+		}
+
+		// add key to list
+		keys = append(keys, payload)
+	}
+
+	// buffered writer
+	writer := bufio.NewWriter(w)
+
+	// stanza header
+	fmt.Fprintln(writer, "env {")
+
+	// iterating over keys
+	for _, payload := range keys {
+
+		// attribute
+		fmt.Fprintf(writer, "  %-*s = \"%s\"\n", width, payload.Key, hclEscape.Replace(payload.Value))
+	}
+
+	// stanza footer
+	fmt.Fprintln(writer, "}")
+
+	return writer.Flush()
+}
@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// heredocStart is the value starting a multi-line value, <<EOF or <<'EOF' for a literal one.
+var heredocStart = regexp.MustCompile(`^<<(?:([A-Za-z_][A-Za-z0-9_]*)|'([A-Za-z_][A-Za-z0-9_]*)')$`)
+
+// markHeredocs turns the lines of multi-line values into continuation lines: the lines after
+// KEY = <<EOF up to the line consisting of EOF. Lines are parsed again first, so the document
+// can be marked again after an edit.
+func (d *Document) markHeredocs() {
+
+	// legacy syntax has no multi-line values
+	if d.Syntax.Legacy {
+		return
+	}
+
+	// multi-line values exist in the default dialect only
+	if !d.Syntax.Escapes() {
+		return
+	}
+
+	// iterating over a list of nodes
+	for i, node := range d.Nodes {
+
+		// line was a continuation line or started a multi-line value
+		if node.Kind == NodeContinuation || len(node.Heredoc) > 0 {
+			d.Nodes[i] = ParseLine(d.Syntax, node.Line, node.Text)
+		}
+	}
+
+	// iterating over a list of nodes
+	for i := 0; i < len(d.Nodes); i++ {
+
+		// current node
+		node := &d.Nodes[i]
+
+		// line without a multi-line value
+		match := heredocStart.FindStringSubmatch(node.Value)
+		if node.Kind != NodeEntry || len(node.Error) > 0 || node.Literal || len(node.Quote) > 0 || match == nil {
+			continue
+		}
+
+		// delimiter of the value, quoted delimiter makes the value literal
+		node.Heredoc, node.Literal = match[1]+match[2], len(match[2]) > 0
+
+		// value has no references on the line
+		node.References = nil
+
+		// closing line is found
+		closed := false
+
+		// iterating over lines of the value
+		for j := i + 1; j < len(d.Nodes) && !closed; j++ {
+
+			// closing line
+			closed = strings.TrimSpace(d.Nodes[j].Text) == node.Heredoc
+
+			// continuation line
+			d.Nodes[j] = Node{Kind: NodeContinuation, Line: d.Nodes[j].Line, Text: d.Nodes[j].Text}
+		}
+
+		// closing line is missing
+		if !closed {
+			node.Error = "multi-line value is not closed with '" + node.Heredoc + "'"
+		}
+	}
+}
+
+// HeredocValue returns the multi-line value of the key on the line: lines up to the closing one
+// joined by new lines.
+func (d *Document) HeredocValue(i int) string {
+
+	// lines of the value
+	var lines []string
+
+	// iterating over continuation lines
+	for j := i + 1; j < len(d.Nodes) && d.Nodes[j].Kind == NodeContinuation; j++ {
+
+		// closing line
+		if strings.TrimSpace(d.Nodes[j].Text) == d.Nodes[i].Heredoc {
+			break
+		}
+
+		// add line
+		lines = append(lines, d.Nodes[j].Text)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// includeDirectives are the directives pulling keys of another file into the file.
+var includeDirectives = []string{"include", "source"}
+
+// includePath returns the position of the path of the include directive in the line
+// without leading and trailing spaces, e.g. include ../shared/base.envfile.
+func includePath(line string) (int, bool) {
+
+	// iterating over directives
+	for _, directive := range includeDirectives {
+
+		// directive followed by a space
+		if len(line) > len(directive) && strings.EqualFold(line[:len(directive)], directive) &&
+			unicode.IsSpace(rune(line[len(directive)])) {
+			return len(directive), true
+		}
+	}
+
+	return 0, false
+}
+
+// IncludedPath returns the path of the file included by the node of the document,
+// a relative path is relative to the including file.
+func (doc *Document) IncludedPath(node Node) string {
+
+	// path of the file system of the document
+	if doc.FS != nil {
+		return path.Join(path.Dir(doc.Name), node.Value)
+	}
+
+	// absolute path
+	if filepath.IsAbs(node.Value) {
+		return node.Value
+	}
+
+	return filepath.Join(filepath.Dir(doc.Name), node.Value)
+}
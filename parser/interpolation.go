@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+
+	// DefaultOperator separates the variable name of a reference from the value used when
+	// the variable does not exist, as in { KEY:-default }.
+	DefaultOperator = ":-"
+
+	// RequiredOperator separates the variable name of a reference from the error message used when
+	// the variable does not exist, as in { KEY:?set it to the database address }.
+	RequiredOperator = ":?"
+)
+
+// SplitReference splits the text of a reference between its delimiters into the variable name,
+// the operator and its argument, the operator is empty for a plain reference.
+func SplitReference(text string) (string, string, string) {
+
+	// position of the first operator
+	position, operator := -1, ""
+
+	// iterating over operators
+	for _, current := range []string{DefaultOperator, RequiredOperator} {
+
+		// operator is found before the others
+		if i := strings.Index(text, current); i >= 0 && (position < 0 || i < position) {
+			position, operator = i, current
+		}
+	}
+
+	// plain reference
+	if position < 0 {
+		return strings.TrimSpace(text), "", ""
+	}
+
+	return strings.TrimSpace(text[:position]), operator, strings.TrimSpace(text[position+len(operator):])
+}
+
+// referenceName returns the variable name of the reference text between its delimiters.
+func referenceName(text string) string {
+
+	// variable name
+	name, _, _ := SplitReference(text)
+
+	return name
+}
+
+// requiredError returns the error of the required variable that does not exist with the message
+// written in the reference.
+func requiredError(variable, message string) error {
+
+	// reference without a message
+	if len(message) == 0 {
+		return fmt.Errorf("variable '%s' is required", variable)
+	}
+
+	return fmt.Errorf("variable '%s' is required: %s", variable, message)
+}
+
+// splitReference splits the text of a reference following the syntax, references of the legacy
+// syntax have no operators.
+func splitReference(syn Syntax, text string) (string, string, string) {
+
+	// whole text is the variable name
+	if syn.Legacy {
+		return strings.TrimSpace(text), "", ""
+	}
+
+	return SplitReference(text)
+}
@@ -0,0 +1,162 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// extractJSON returns the field of the JSON value referenced as { KEY.field.0.field },
+// escaped to be inserted into another value. It reports false if the key does not exist.
+func (r *Resolver) extractJSON(reference string, payloads []Payload) (string, bool, error) {
+
+	// key name and path to the field
+	path := strings.Split(reference, ".")
+
+	// JSON value
+	var data *string
+
+	// iterating over a list of payloads
+	for _, payload := range payloads {
+
+		// key exists in the list of payloads
+		if r.sameKey(payload.Key, path[0]) {
+
+			// JSON value
+			value := payload.Value
+
+			// unescape curly braces written in file
+			if !payload.Literal {
+				value = strings.NewReplacer("{{", "{", "}}", "}").Replace(value)
+			}
+
+			// update JSON value
+			data = &value
+
+			// exit loop
+			break
+		}
+	}
+
+	// JSON value is missing
+	if data == nil {
+
+		// JSON value from environment variables
+		value, ok := r.lookup(path[0])
+
+		// variable does not exist
+		if !ok {
+			return "", false, nil
+		}
+
+		// update JSON value
+		data = &value
+	}
+
+	// field value
+	value, err := jsonField(*data, path)
+	if err != nil {
+		return "", false, err
+	}
+
+	// escape the special characters and curly braces
+	return strings.NewReplacer("\\", "\\\\", "{", "{{", "}", "}}").Replace(value), true, nil
+}
+
+// pendingJSON reports whether the JSON value referenced as { KEY.field } is a key
+// of the payload list whose value still has references to be replaced.
+func (r *Resolver) pendingJSON(syn Syntax, reference string, payloads []Payload) bool {
+
+	// key name
+	key := strings.SplitN(reference, ".", 2)[0]
+
+	// syntax of the values being expanded, $KEY references are already rewritten with the delimiters
+	syn.Dollar = false
+
+	// iterating over a list of payloads
+	for _, payload := range payloads {
+
+		// key exists in the list of payloads
+		if r.sameKey(payload.Key, key) {
+			return !payload.Literal && len(ScanReferences(syn, payload.Value, 0)) > 0
+		}
+	}
+
+	return false
+}
+
+// jsonField returns the field of the JSON data by the path, where the first element
+// is the name of the variable holding the data; strings are returned as is, anything else as JSON.
+func jsonField(data string, path []string) (string, error) {
+
+	// decoded JSON value
+	var field interface{}
+
+	// decode JSON value
+	if err := json.Unmarshal([]byte(data), &field); err != nil {
+		return "", fmt.Errorf("variable '%s' is not valid JSON: %s", path[0], err)
+	}
+
+	// iterating over the path to the field
+	for i, name := range path[1:] {
+
+		// path to the current field
+		current := strings.Join(path[:i+2], ".")
+
+		switch node := field.(type) {
+
+		// object
+		case map[string]interface{}:
+
+			// field of object
+			value, ok := node[name]
+
+			// field does not exist
+			if !ok {
+				return "", fmt.Errorf("field '%s' does not exist", current)
+			}
+
+			// update field
+			field = value
+
+		// array
+		case []interface{}:
+
+			// index of array element
+			index, err := strconv.Atoi(name)
+
+			// index is not a number or out of range
+			if err != nil || index < 0 || index >= len(node) {
+				return "", fmt.Errorf("field '%s' does not exist", current)
+			}
+
+			// update field
+			field = node[index]
+
+		// scalar
+		default:
+			return "", fmt.Errorf("field '%s' does not exist", current)
+		}
+	}
+
+	// field value
+	var value string
+
+	// string field is taken as is, anything else as JSON
+	if text, ok := field.(string); ok {
+		value = text
+	} else {
+
+		// encode field
+		encoded, err := json.Marshal(field)
+		if err != nil {
+			return "", err
+		}
+
+		// update field value
+		value = string(encoded)
+	}
+
+	return value, nil
+}
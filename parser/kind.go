@@ -0,0 +1,159 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kind is a type of value recognized from its literal.
+type Kind int
+
+const (
+
+	// KindString is any value that is not recognized as a typed literal.
+	KindString Kind = iota
+
+	// KindBool is a true or false literal.
+	KindBool
+
+	// KindInt is an integer literal.
+	KindInt
+
+	// KindFloat is a floating point literal.
+	KindFloat
+)
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+
+	switch k {
+
+	// boolean
+	case KindBool:
+		return "bool"
+
+	// integer
+	case KindInt:
+		return "int"
+
+	// floating point
+	case KindFloat:
+		return "float"
+
+	// any
+	default:
+		return "string"
+	}
+}
+
+var (
+
+	// integer literal
+	intLiteral = regexp.MustCompile(`^[+-]?\d+$`)
+
+	// floating point literal
+	floatLiteral = regexp.MustCompile(`^[+-]?(\d+\.\d*|\.\d+|\d+)([eE][+-]?\d+)?$`)
+
+	// number with a leading zero, as in 007 or 00.5
+	leadingZero = regexp.MustCompile(`^[+-]?0\d`)
+)
+
+// KindOf recognizes the type of value from its literal. Numbers with leading zeros and numbers
+// out of the range of int64 and float64 are strings, since they can't be typed without changing them.
+func KindOf(value string) Kind {
+
+	// true or false literal
+	if strings.EqualFold(value, "true") || strings.EqualFold(value, "false") {
+		return KindBool
+	}
+
+	// number with a leading zero, e.g. a code or a file mode
+	if leadingZero.MatchString(value) {
+		return KindString
+	}
+
+	// integer literal
+	if intLiteral.MatchString(value) {
+
+		// integer is out of range
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return KindString
+		}
+
+		return KindInt
+	}
+
+	// floating point literal
+	if floatLiteral.MatchString(value) {
+
+		// number is out of range
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return KindString
+		}
+
+		return KindFloat
+	}
+
+	return KindString
+}
+
+// AsBool returns the value as a boolean.
+func (p Payload) AsBool() (bool, error) {
+
+	// value is not a boolean literal
+	if p.Kind != KindBool {
+		return false, &ConversionError{Key: p.Key, Raw: p.Value, Target: "bool", Line: p.Line}
+	}
+
+	return strings.EqualFold(p.Value, "true"), nil
+}
+
+// AsInt returns the value as an integer.
+func (p Payload) AsInt() (int64, error) {
+
+	// value is not an integer literal
+	if p.Kind != KindInt {
+		return 0, &ConversionError{Key: p.Key, Raw: p.Value, Target: "int64", Line: p.Line}
+	}
+
+	return strconv.ParseInt(p.Value, 10, 64)
+}
+
+// AsFloat returns the value as a floating point number, integers included.
+func (p Payload) AsFloat() (float64, error) {
+
+	// value is not a number literal
+	if p.Kind != KindInt && p.Kind != KindFloat {
+		return 0, &ConversionError{Key: p.Key, Raw: p.Value, Target: "float64", Line: p.Line}
+	}
+
+	return strconv.ParseFloat(p.Value, 64)
+}
+
+// Typed returns the value as bool, int64, float64 or string according to its kind,
+// e.g. to export it as JSON with native types.
+func (p Payload) Typed() interface{} {
+
+	switch p.Kind {
+
+	// boolean
+	case KindBool:
+		value, _ := p.AsBool()
+		return value
+
+	// integer
+	case KindInt:
+		value, _ := p.AsInt()
+		return value
+
+	// floating point
+	case KindFloat:
+		value, _ := p.AsFloat()
+		return value
+
+	// any
+	default:
+		return p.Value
+	}
+}
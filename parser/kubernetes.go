@@ -0,0 +1,110 @@
+package parser
+
+import "strings"
+
+// expandKubernetes expands $(KEY) references the way Kubernetes does for the container environment.
+func (r *Resolver) expandKubernetes(payloads []Payload) []Payload {
+
+	// iterating over a list of payloads
+	for i, payload := range payloads {
+
+		// raw value is taken as it is written
+		if payload.Literal {
+			continue
+		}
+
+		// expand value using the keys defined above the current one
+		payload.Value = expandKubernetesValue(payload.Value, func(variable string) (string, bool) {
+
+			// iterating over a list of previous payloads
+			for _, pld := range payloads[:i] {
+
+				// variable exists in the list of previous payloads
+				if pld.Key == variable {
+					return pld.Value, true
+				}
+			}
+
+			// variable value from environment variables
+			return r.lookup(variable)
+		})
+
+		// update payload
+		payloads[i] = payload
+	}
+
+	return payloads
+}
+
+// expandKubernetesValue replaces $(KEY) references in the value using the lookup function.
+func expandKubernetesValue(value string, lookup func(string) (string, bool)) string {
+
+	// expanded value
+	var builder strings.Builder
+
+	// position of the text not yet written to the expanded value
+	var checkpoint int
+
+	// iteration over value
+	for cursor := 0; cursor < len(value); cursor++ {
+
+		// dollar sign is not the last character
+		if value[cursor] == '$' && cursor+1 < len(value) {
+
+			// write everything before the dollar sign
+			builder.WriteString(value[checkpoint:cursor])
+
+			// text after the dollar sign
+			rest := value[cursor+1:]
+
+			switch rest[0] {
+
+			// escaped dollar sign
+			case '$':
+				builder.WriteByte('$')
+
+			// start of variable
+			case '(':
+
+				// end of variable
+				end := strings.IndexByte(rest, ')')
+
+				// closing parenthesis is missing, the text is left as is
+				if end < 0 {
+					builder.WriteString("$(")
+					break
+				}
+
+				// variable
+				variable := rest[1:end]
+
+				// variable value
+				if resolved, ok := lookup(variable); ok {
+					builder.WriteString(resolved)
+				} else {
+
+					// unresolvable reference is left literal
+					builder.WriteString("$(" + variable + ")")
+				}
+
+				// skip variable name and closing parenthesis
+				cursor += end
+
+			// any
+			default:
+				builder.WriteString(value[cursor : cursor+2])
+			}
+
+			// skip the character after the dollar sign
+			cursor++
+
+			// update checkpoint
+			checkpoint = cursor + 1
+		}
+	}
+
+	// write the rest of the value
+	builder.WriteString(value[checkpoint:])
+
+	return builder.String()
+}
@@ -0,0 +1,26 @@
+package parser
+
+import "bytes"
+
+// ScanLines is a split function for bufio.Scanner returning lines without
+// the line ending, accepting LF, CRLF and repeated trailing CR characters.
+func ScanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+
+	// no data at the end of input
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	// position of the line feed
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, bytes.TrimRight(data[:i], "\r"), nil
+	}
+
+	// last line without line feed
+	if atEOF {
+		return len(data), bytes.TrimRight(data, "\r"), nil
+	}
+
+	// request more data
+	return 0, nil, nil
+}
@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// marshalEscapes escapes the characters of values that Parse unescapes.
+var marshalEscapes = strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\t", "\\t", "{", "{{", "}", "}}")
+
+// Marshal writes the payloads as a file of the default dialect, so that envfile.Parse returns the same keys,
+// values and export, overload and conditional statuses. Backslashes, new lines, tabs and curly braces
+// are escaped, values with leading or trailing spaces, looking quoted or like an inline comment are
+// double-quoted, so they are read the same with envfile.WithInlineComments. Carriage returns can't be written.
+func Marshal(payloads []Payload) ([]byte, error) {
+
+	// content of the file
+	var buffer bytes.Buffer
+
+	// iterating over a list of payloads
+	for _, payload := range payloads {
+
+		// line of the payload
+		line, err := marshalLine(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		// add line
+		buffer.WriteString(line + "\n")
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// MarshalMap writes the keys and values as exported keys of a file of the default dialect
+// in alphabetical order, see Marshal.
+func MarshalMap(values map[string]string) ([]byte, error) {
+
+	// keys of the map
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	// alphabetical order
+	sort.Strings(keys)
+
+	// payloads of the keys
+	payloads := make([]Payload, len(keys))
+
+	// iterating over keys
+	for i, key := range keys {
+		payloads[i] = Payload{Line: i + 1, Export: true, Key: key, Value: values[key]}
+	}
+
+	return Marshal(payloads)
+}
+
+// marshalLine returns the line of the payload without the line ending.
+func marshalLine(payload Payload) (string, error) {
+
+	// key is not valid
+	if !validation.MatchString(payload.Key) {
+		return "", fmt.Errorf("key '%s' is not valid", payload.Key)
+	}
+
+	// escaped value
+	value := marshalEscapes.Replace(payload.Value)
+
+	// value can't be written
+	if strings.ContainsRune(value, '\r') {
+		return "", fmt.Errorf("value of key '%s' has carriage returns that can't be written", payload.Key)
+	}
+
+	// value with leading or trailing spaces, looking quoted or like an inline comment is enclosed in double quotes
+	if _, quoted := quotedValue(value); quoted || value != strings.TrimSpace(value) || inlineComment(value) >= 0 {
+		value = `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+	}
+
+	// conditional assignment can't be overloaded
+	if payload.Conditional && payload.Overload {
+		return "", fmt.Errorf("key '%s': conditional assignment '?=' can't be overloaded", payload.Key)
+	}
+
+	// line of the payload
+	var line strings.Builder
+
+	// export directive
+	if payload.Export && !payload.Conditional {
+		line.WriteString("export ")
+	}
+
+	// overload directive
+	if payload.Overload {
+		line.WriteString("overload ")
+	}
+
+	// key
+	line.WriteString(payload.Key)
+
+	// conditional assignment operator
+	if payload.Conditional {
+		line.WriteString(" ?=")
+	} else {
+		line.WriteString(" =")
+	}
+
+	// value
+	if len(value) > 0 {
+		line.WriteString(" " + value)
+	}
+
+	return line.String(), nil
+}
@@ -0,0 +1,99 @@
+package parser
+
+import "regexp"
+
+// Payload structure.
+type Payload struct {
+
+	// line number in file
+	Line int
+
+	// export status
+	Export bool
+
+	// overload status
+	Overload bool
+
+	// conditional assignment status, KEY ?= value is set only if not defined yet
+	Conditional bool
+
+	// raw directive status, the value is taken without escape processing and interpolation
+	Literal bool
+
+	// key
+	Key string
+
+	// value
+	Value string
+
+	// value as it is written in file
+	Raw string
+
+	// quote enclosing the value in file, " or ', empty if the value is not quoted
+	Quote string
+
+	// type of value recognized from its literal
+	Kind Kind
+
+	// file the key is written in, set for keys of included files only
+	File string
+}
+
+// key name validation
+var validation = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// ValidKey reports whether the key name has only letters, digits and underscores.
+func ValidKey(key string) bool {
+	return validation.MatchString(key)
+}
+
+// Payloads is a list of payloads in the order their lines are written in the file,
+// a joined list (KEY[]) takes the position of its first item. Keys are unique.
+type Payloads []Payload
+
+// Index returns payloads by key.
+func (p Payloads) Index() map[string]Payload {
+
+	// payloads by key
+	index := make(map[string]Payload, len(p))
+
+	// iterating over a list of payloads
+	for _, payload := range p {
+
+		// add payload to index
+		index[payload.Key] = payload
+	}
+
+	return index
+}
+
+// Keys returns keys of the payloads in the order they are written in the file.
+func (p Payloads) Keys() []string {
+
+	// keys list
+	keys := make([]string, 0, len(p))
+
+	// iterating over a list of payloads
+	for _, payload := range p {
+
+		// add key to list
+		keys = append(keys, payload.Key)
+	}
+
+	return keys
+}
+
+// Lookup returns the payload with the key and whether it exists.
+func (p Payloads) Lookup(key string) (Payload, bool) {
+
+	// iterating over a list of payloads
+	for _, payload := range p {
+
+		// payload is found
+		if payload.Key == key {
+			return payload, true
+		}
+	}
+
+	return Payload{}, false
+}
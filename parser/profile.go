@@ -0,0 +1,6 @@
+package parser
+
+import "regexp"
+
+// sectionHeader is a line starting the keys of a profile, e.g. [production].
+var sectionHeader = regexp.MustCompile(`^\[([A-Za-z0-9_.-]+)\]$`)
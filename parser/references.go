@@ -0,0 +1,67 @@
+package parser
+
+import "strings"
+
+// Ref is a reference to a variable in the value of a key.
+type Ref struct {
+
+	// referenced variable
+	Name string `json:"name"`
+
+	// line number of the referencing key
+	Line int `json:"line"`
+
+	// position of the reference in the value as it is written, including curly braces
+	Span Span `json:"span"`
+
+	// variable is a key of the same payload list, otherwise it is taken from environment variables
+	Internal bool `json:"internal"`
+}
+
+// References returns { VAR } references of the payloads by referencing key.
+func References(payloads []Payload) map[string][]Ref {
+	return Syntax{}.References(payloads)
+}
+
+// References returns references of the payloads written with the syntax by referencing key.
+func (s Syntax) References(payloads []Payload) map[string][]Ref {
+
+	// keys of the payload list
+	keys := make(map[string]bool)
+
+	// iterating over a list of payloads
+	for _, payload := range payloads {
+
+		// add key to list
+		keys[payload.Key] = true
+	}
+
+	// references by referencing key
+	refs := make(map[string][]Ref)
+
+	// iterating over a list of payloads
+	for _, payload := range payloads {
+
+		// raw value has no references
+		if payload.Literal {
+			continue
+		}
+
+		// iterating over references of the value
+		for _, reference := range ScanReferences(s, payload.Raw, 0) {
+
+			// referenced variable, without the path to a JSON field
+			variable := strings.SplitN(reference.Name, ".", 2)[0]
+
+			// add reference to list
+			refs[payload.Key] = append(refs[payload.Key], Ref{
+				Name:     reference.Name,
+				Line:     payload.Line,
+				Span:     reference.Span,
+				Internal: keys[variable],
+			})
+		}
+	}
+
+	return refs
+}
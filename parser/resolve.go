@@ -0,0 +1,498 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Resolver turns documents into payloads and replaces references with values. Everything outside
+// the document is taken from its fields: variables of the environment, included files, secrets and
+// values of variables that do not exist, so parsing never touches the process or the disk by itself.
+// The zero Resolver resolves references with keys of the document only.
+type Resolver struct {
+
+	// profile whose section is applied, keys of sections are skipped if empty
+	Profile string
+
+	// parsing goes on after a line with a problem, the errors of the lines are joined
+	ContinueOnError bool
+
+	// list items are joined into one value with the separator instead of indexed KEY_0, KEY_1 keys
+	Join bool
+
+	// separator of joined list items
+	Separator string
+
+	// keys differing only in case are the same key
+	CaseInsensitive bool
+
+	// values referenced as { data.name }
+	Data map[string]interface{}
+
+	// Lookup returns the variable of the environment and whether it exists, nothing exists if nil.
+	Lookup func(variable string) (string, bool)
+
+	// Include returns the document of the file included by the document, the path is relative
+	// to the working directory or to the root of the file system of the document. Include
+	// directives are errors if nil.
+	Include func(doc *Document, path string) (*Document, error)
+
+	// Secret returns the value of a URI of a secret referenced in the line of the file and whether
+	// its scheme is known, URIs are not secrets if nil.
+	Secret func(file string, line int, uri string) (string, bool, error)
+
+	// Undefined returns the value of a variable that does not exist, false leaves the reference
+	// as it is written. References to variables that do not exist are errors if nil.
+	Undefined func(variable string) (string, bool, error)
+}
+
+// Parse reads the document written with the syntax from the reader and returns its payloads
+// with references replaced and types of values recognized, the name is used in error messages.
+func (r *Resolver) Parse(filename string, reader io.Reader, syn Syntax) (Payloads, error) {
+
+	// read document
+	doc, err := Read(filename, reader, syn)
+	if err != nil {
+		return nil, err
+	}
+
+	// payloads of the document
+	payloads, err := r.Payloads(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	// replace references with values
+	payloads, _, err = r.Expand(syn, filename, payloads)
+	if err != nil {
+		return nil, err
+	}
+
+	// iterating over a list of payloads
+	for i := range payloads {
+
+		// quoted value is a string, whatever it looks like
+		if len(payloads[i].Quote) > 0 {
+			continue
+		}
+
+		// recognize type of value
+		payloads[i].Kind = KindOf(payloads[i].Value)
+	}
+
+	return payloads, nil
+}
+
+// Payloads converts the document into payloads leaving values as they are written: lines of
+// false #if branches and of sections of other profiles are skipped, list items are indexed or
+// joined, keys of included files are added in place of the include directives, and keys are
+// checked to be valid and unique.
+func (r *Resolver) Payloads(doc *Document) (Payloads, error) {
+	return r.documentPayloads(doc, []string{filepath.Clean(doc.Name)})
+}
+
+// documentPayloads converts the document into payloads leaving values as they are written,
+// the chain is the files being included, starting with the parsed one.
+func (r *Resolver) documentPayloads(doc *Document, chain []string) ([]Payload, error) {
+
+	// file name
+	filename := doc.Name
+
+	// payload list
+	var payloads []Payload
+
+	// errors of the lines
+	var errs []error
+
+	// number of items by list name
+	items := make(map[string]int)
+
+	// position of joined lists in the payload list by list name
+	lists := make(map[string]int)
+
+	// profile of the current section, empty outside sections
+	section := ""
+
+	// positions of payloads written in sections
+	sectioned := make(map[int]bool)
+
+	// open #if blocks
+	var blocks conditions
+
+	// iterating over document lines
+	for i, node := range doc.Nodes {
+
+		// line number
+		line := node.Line
+
+		// conditional directive
+		if directive, expression, ok := conditionDirective(node); ok && !doc.Syntax.Legacy {
+
+			// open, switch or close block
+			if err := blocks.apply(r, line, directive, expression); err != nil {
+
+				// add error of the line
+				errs = append(errs, fmt.Errorf("[%s] line %d: %s", filename, line, err))
+
+				// next line is checked when collecting all errors
+				if !r.ContinueOnError {
+					break
+				}
+			}
+
+			continue
+		}
+
+		// line of a false branch
+		if !blocks.active() {
+			continue
+		}
+
+		// ignore blank lines, comments and lines of multi-line values
+		if node.Kind == NodeBlank || node.Kind == NodeComment || node.Kind == NodeContinuation {
+			continue
+		}
+
+		// multi-line value
+		if len(node.Heredoc) > 0 && len(node.Error) == 0 {
+			node.Value = doc.HeredocValue(i)
+		}
+
+		// invalid line
+		if len(node.Error) > 0 {
+
+			// add error of the line
+			errs = append(errs, fmt.Errorf("[%s] line %d: %s", filename, line, node.Error))
+
+			// next line is checked when collecting all errors
+			if r.ContinueOnError {
+				continue
+			}
+
+			break
+		}
+
+		// start of a section
+		if node.Kind == NodeSection {
+			section = node.Value
+			continue
+		}
+
+		// line of a section of another profile
+		if len(section) > 0 && section != r.Profile {
+			continue
+		}
+
+		// included file
+		if node.Kind == NodeInclude {
+
+			// payloads of the included file
+			included, err := r.include(doc, node, chain)
+
+			// iterating over included payloads
+			for _, payload := range included {
+
+				// key already exists in the payload list
+				if err == nil && slices.ContainsFunc(payloads, func(pld Payload) bool { return r.sameKey(pld.Key, payload.Key) }) {
+					err = fmt.Errorf("[%s] line %d: duplicate key '%s'", payload.File, payload.Line, payload.Key)
+				}
+			}
+
+			// file can't be included
+			if err != nil {
+
+				// add error of the line
+				errs = append(errs, err)
+
+				// next line is checked when collecting all errors
+				if r.ContinueOnError {
+					continue
+				}
+
+				break
+			}
+
+			// add included payloads to list
+			payloads = append(payloads, included...)
+
+			continue
+		}
+
+		// payload
+		payload := Payload{
+			Line:        line,
+			Export:      node.Export,
+			Overload:    node.Overload,
+			Conditional: node.Conditional,
+			Literal:     node.Literal,
+			Key:         node.Key,
+			Value:       node.Value,
+			Raw:         node.Value,
+			Quote:       node.Quote,
+		}
+
+		// docker key without value is taken from environment variables
+		if doc.Syntax.Dialect == DialectDocker && !strings.Contains(node.Text, "=") {
+
+			// value from environment variables
+			value, ok := r.lookup(payload.Key)
+
+			// ignore keys missing in environment variables
+			if !ok {
+				continue
+			}
+
+			// set value
+			payload.Value = value
+		}
+
+		// list item
+		if strings.HasSuffix(payload.Key, "[]") && !doc.Syntax.Legacy {
+
+			// list name
+			name := strings.TrimSpace(strings.TrimSuffix(payload.Key, "[]"))
+
+			// empty list name
+			if len(name) == 0 {
+
+				// add error of the line
+				errs = append(errs, fmt.Errorf("[%s] line %d: list name is empty", filename, line))
+
+				// next line is checked when collecting all errors
+				if r.ContinueOnError {
+					continue
+				}
+
+				break
+			}
+
+			// list items are joined into one value
+			if r.Join {
+
+				// list already exists in the payload list
+				if index, ok := lists[name]; ok {
+
+					// add item to the list value
+					payloads[index].Value += r.Separator + payload.Value
+					payloads[index].Raw += r.Separator + payload.Raw
+
+					continue
+				}
+
+				// remember position of the list
+				lists[name] = len(payloads)
+
+				// update key name
+				payload.Key = name
+
+			} else {
+
+				// update key name with the item index
+				payload.Key = fmt.Sprintf("%s_%d", name, items[name])
+
+				// increase number of items
+				items[name]++
+			}
+		}
+
+		// problem with the key
+		var problem error
+
+		switch {
+
+		// empty key name
+		case len(payload.Key) == 0:
+			problem = fmt.Errorf("[%s] line %d: key name is empty", filename, line)
+
+		// invalid key name
+		case !validation.MatchString(payload.Key):
+			problem = fmt.Errorf("[%s] line %d: invalid key name '%s'", filename, line, payload.Key)
+		}
+
+		// position of the key written outside sections that is replaced by the key of the section
+		replaced := -1
+
+		// iterating over a list of payloads
+		for j, pld := range payloads {
+
+			// key of the section replaces the key written outside sections
+			if problem == nil && r.sameKey(pld.Key, payload.Key) && len(section) > 0 && !sectioned[j] {
+				replaced = j
+				continue
+			}
+
+			// key already exists in the payload list
+			if problem == nil && r.sameKey(pld.Key, payload.Key) {
+				problem = fmt.Errorf("[%s] line %d: duplicate key '%s'", filename, line, payload.Key)
+			}
+		}
+
+		// key can't be added
+		if problem != nil {
+
+			// add error of the line
+			errs = append(errs, problem)
+
+			// next line is checked when collecting all errors
+			if r.ContinueOnError {
+				continue
+			}
+
+			break
+		}
+
+		// replace key written outside sections
+		if replaced >= 0 {
+			payloads[replaced] = payload
+			sectioned[replaced] = true
+			continue
+		}
+
+		// remember key written in a section
+		if len(section) > 0 {
+			sectioned[len(payloads)] = true
+		}
+
+		// add payload to list
+		payloads = append(payloads, payload)
+	}
+
+	// iterating over blocks that are not closed
+	for _, block := range blocks {
+
+		// add error of the block
+		if len(errs) == 0 || r.ContinueOnError {
+			errs = append(errs, fmt.Errorf("[%s] line %d: #if is not closed with #endif", filename, block.line))
+		}
+	}
+
+	// errors are found
+	if len(errs) > 0 {
+		return nil, JoinErrors(errs)
+	}
+
+	return payloads, nil
+}
+
+// include returns the payloads of the file included by the node of the document, the chain is
+// the files being included, starting with the parsed one, to detect cycles.
+func (r *Resolver) include(doc *Document, node Node, chain []string) ([]Payload, error) {
+
+	// path of the included file
+	path := doc.IncludedPath(node)
+
+	// file is already being included
+	if slices.Contains(chain, filepath.Clean(path)) {
+		return nil, fmt.Errorf("[%s] line %d: include cycle %s -> %s", doc.Name, node.Line, strings.Join(chain, " -> "), path)
+	}
+
+	// files can't be included
+	if r.Include == nil {
+		return nil, fmt.Errorf("[%s] line %d: can't include '%s': files are not available", doc.Name, node.Line, node.Value)
+	}
+
+	// read included document
+	included, err := r.Include(doc, path)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] line %d: can't include '%s': %s", doc.Name, node.Line, node.Value, err)
+	}
+
+	// files included by the included file are read from the same file system
+	included.FS = doc.FS
+
+	// payloads of the included file
+	payloads, err := r.documentPayloads(included, append(chain, filepath.Clean(path)))
+	if err != nil {
+		return nil, err
+	}
+
+	// iterating over a list of payloads
+	for i := range payloads {
+
+		// set file of the payload
+		payloads[i].File = PayloadFile(payloads[i], path)
+	}
+
+	return payloads, nil
+}
+
+// PayloadFile returns the file the payload is written in: the parsed file, unless the payload comes from an included one.
+func PayloadFile(payload Payload, filename string) string {
+
+	// payload of an included file
+	if len(payload.File) > 0 {
+		return payload.File
+	}
+
+	return filename
+}
+
+// lookup returns the variable of the environment and whether it exists.
+func (r *Resolver) lookup(variable string) (string, bool) {
+
+	// environment is not available
+	if r.Lookup == nil {
+		return "", false
+	}
+
+	return r.Lookup(variable)
+}
+
+// secret returns the value of a URI of a secret referenced in the line of the file and whether its scheme is known.
+func (r *Resolver) secret(file string, line int, uri string) (string, bool, error) {
+
+	// secrets are not available
+	if r.Secret == nil {
+		return "", false, nil
+	}
+
+	return r.Secret(file, line, uri)
+}
+
+// undefined returns the value of the variable that does not exist, false leaves the reference as it is written.
+func (r *Resolver) undefined(variable string) (string, bool, error) {
+
+	// variables that do not exist are errors
+	if r.Undefined == nil {
+		return "", false, fmt.Errorf("variable '%s' does not exist", variable)
+	}
+
+	return r.Undefined(variable)
+}
+
+// sameKey reports whether the names are the same key.
+func (r *Resolver) sameKey(a, b string) bool {
+
+	// case-insensitive keys
+	if r.CaseInsensitive {
+		return strings.EqualFold(a, b)
+	}
+
+	return a == b
+}
+
+// indexKey returns the name of the key used in indexes: upper-cased with case-insensitive keys.
+func (r *Resolver) indexKey(key string) string {
+
+	// case-insensitive keys
+	if r.CaseInsensitive {
+		return strings.ToUpper(key)
+	}
+
+	return key
+}
+
+// JoinErrors returns the only error or the errors joined, one per line.
+func JoinErrors(errs []error) error {
+
+	// only error is returned as it is
+	if len(errs) == 1 {
+		return errs[0]
+	}
+
+	return errors.Join(errs...)
+}
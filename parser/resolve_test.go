@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestResolverParse tests parsing with includes, sections and references without the loader.
+func TestResolverParse(t *testing.T) {
+
+	// included files by path
+	files := map[string]string{"base.envfile": "HOST = localhost\n"}
+
+	// resolver taking files and variables from memory
+	resolver := &Resolver{
+		Profile: "prod",
+		Lookup: func(variable string) (string, bool) {
+			return map[string]string{"USER": "admin"}[variable], variable == "USER"
+		},
+		Include: func(doc *Document, path string) (*Document, error) {
+
+			// content of the file
+			content, ok := files[path]
+			if !ok {
+				return nil, fmt.Errorf("file does not exist")
+			}
+
+			return Read(path, strings.NewReader(content), doc.Syntax)
+		},
+	}
+
+	// file content
+	content := "include base.envfile\nURL = http://{ USER }@{ HOST }:{ PORT:-80 }\nPORT = 8080\n[prod]\nPORT = 443\n"
+
+	// parse file
+	payloads, err := resolver.Parse("app.envfile", strings.NewReader(content), Syntax{})
+	if err != nil {
+		t.Fatalf("error parsing file: %v", err)
+	}
+
+	// values by key
+	values := make(map[string]string)
+	for _, payload := range payloads {
+		values[payload.Key] = payload.Value
+	}
+
+	// references are replaced with keys of the included file, the section and the environment
+	if values["URL"] != "http://admin@localhost:443" || values["HOST"] != "localhost" {
+		t.Errorf("expected URL http://admin@localhost:443 and HOST localhost, got %v", values)
+	}
+
+	// type of the value is recognized
+	if kind := payloads[len(payloads)-1].Kind; kind != KindInt {
+		t.Errorf("expected PORT to be an integer, got %v", kind)
+	}
+
+	// references to variables that do not exist are errors without a policy
+	if _, err := resolver.Parse("app.envfile", strings.NewReader("URL = { MISSING }\n"), Syntax{}); err == nil || !strings.Contains(err.Error(), "variable 'MISSING' does not exist") {
+		t.Errorf("expected error of the missing variable, got %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package parser
+
+import "bytes"
+
+// Bytes returns the text of the document: lines as they are written, separated by the line ending
+// of the file, LF if it has none. Untouched lines of a well-formed file, with the same line ending
+// everywhere, are byte-identical to the original ones.
+func (d *Document) Bytes() []byte {
+
+	// line ending of the file
+	newline := d.newline
+	if len(newline) == 0 {
+		newline = "\n"
+	}
+
+	// text of the document
+	var buffer bytes.Buffer
+
+	// iterating over a list of nodes
+	for i, node := range d.Nodes {
+
+		// line ending of the previous line
+		if i > 0 {
+			buffer.WriteString(newline)
+		}
+
+		// add line
+		buffer.WriteString(node.Text)
+	}
+
+	// last line ends with the line ending
+	if d.final && len(d.Nodes) > 0 {
+		buffer.WriteString(newline)
+	}
+
+	return buffer.Bytes()
+}
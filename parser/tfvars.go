@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TFVarsEncoder writes payloads as terraform variables.
+type TFVarsEncoder struct {
+
+	// write numbers and booleans without quotes instead of strings
+	Typed bool
+
+	// split values containing the separator into lists of strings, disabled if empty
+	ListSeparator string
+}
+
+// EncodeTFVars writes payloads as terraform variables with lower-cased names,
+// numbers and booleans are written without quotes.
+func EncodeTFVars(payloads []Payload, w io.Writer) error {
+	return (&TFVarsEncoder{Typed: true}).Encode(payloads, w)
+}
+
+// Encode writes payloads as terraform variables with lower-cased names in the order of the file,
+// with equal signs aligned the way terraform fmt does.
+func (e *TFVarsEncoder) Encode(payloads []Payload, w io.Writer) error {
+
+	// width of the longest name
+	var width int
+
+	// iterating over a list of payloads
+	for _, payload := range payloads {
+
+		// name starting with a digit is not a terraform identifier
+		if payload.Key[0] >= '0' && payload.Key[0] <= '9' {
+			return fmt.Errorf("line %d: key '%s' is not a valid terraform variable name", payload.Line, payload.Key)
+		}
+
+		// update width
+		if len(payload.Key) > width {
+			width = len(payload.Key)
+		}
+	}
+
+	// buffered writer
+	writer := bufio.NewWriter(w)
+
+	// iterating over a list of payloads
+	for _, payload := range payloads {
+
+		// variable
+		fmt.Fprintf(writer, "%-*s = %s\n", width, strings.ToLower(payload.Key), e.value(payload))
+	}
+
+	return writer.Flush()
+}
+
+// value returns the value of the payload as a terraform literal.
+func (e *TFVarsEncoder) value(payload Payload) string {
+
+	// list of strings
+	if len(e.ListSeparator) > 0 && strings.Contains(payload.Value, e.ListSeparator) {
+
+		// list items
+		items := strings.Split(payload.Value, e.ListSeparator)
+
+		// iterating over list items
+		for i, item := range items {
+
+			// quote item
+			items[i] = `"` + hclEscape.Replace(strings.TrimSpace(item)) + `"`
+		}
+
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+
+	// typed literal
+	if e.Typed {
+
+		switch payload.Kind {
+
+		// boolean in lower case
+		case KindBool:
+			return strings.ToLower(payload.Value)
+
+		// number as it is written
+		case KindInt, KindFloat:
+			return payload.Value
+		}
+	}
+
+	return `"` + hclEscape.Replace(payload.Value) + `"`
+}
@@ -0,0 +1,185 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// TokenKind is a kind of token of a line.
+type TokenKind string
+
+const (
+
+	// TokenDirective is the export, overload or raw directive before the key, or the include directive.
+	TokenDirective TokenKind = "directive"
+
+	// TokenKey is the key.
+	TokenKey TokenKind = "key"
+
+	// TokenOperator is the assignment operator, = or ?=.
+	TokenOperator TokenKind = "operator"
+
+	// TokenValue is a part of the value outside of references.
+	TokenValue TokenKind = "value"
+
+	// TokenReference is a reference to a variable including its delimiters.
+	TokenReference TokenKind = "reference"
+
+	// TokenComment is a comment line or a comment after the value, including the number sign.
+	TokenComment TokenKind = "comment"
+
+	// TokenSection is a section line including its square brackets.
+	TokenSection TokenKind = "section"
+)
+
+// Token is a part of a line with its position, tokens of a line do not overlap and are in order of columns.
+type Token struct {
+
+	// kind of token
+	Kind TokenKind `json:"kind"`
+
+	// text as it is written
+	Text string `json:"text"`
+
+	// position in the line
+	Span Span `json:"span"`
+}
+
+// Tokenize splits the line written with the syntax into tokens for syntax highlighters and formatters.
+// Blank lines have no tokens. Tokens found so far are returned with the problem of the line.
+func Tokenize(syn Syntax, line string) ([]Token, error) {
+
+	// parse line
+	node := ParseLine(syn, 1, line)
+
+	// tokens list
+	var tokens []Token
+
+	switch node.Kind {
+
+	// blank line
+	case NodeBlank:
+		return nil, nil
+
+	// comment line
+	case NodeComment:
+
+		// position of the comment
+		start, end := trimSpan(line, 0, len(line))
+
+		return []Token{{Kind: TokenComment, Text: line[start:end], Span: Span{start, end}}}, nil
+
+	// section with the profile name
+	case NodeSection:
+
+		// position of the section
+		start, end := trimSpan(line, 0, len(line))
+
+		return []Token{{Kind: TokenSection, Text: line[start:end], Span: Span{start, end}}}, nil
+
+	// include directive with the path
+	case NodeInclude:
+
+		// position of the directive
+		directive := wordSpans(line, 0, len(line))[0]
+
+		// position of the path including its quotes
+		start, end := trimSpan(line, directive.End, len(line))
+
+		return []Token{
+			{Kind: TokenDirective, Text: line[directive.Start:directive.End], Span: directive},
+			{Kind: TokenValue, Text: line[start:end], Span: Span{start, end}},
+		}, nil
+
+	// line that can't be split
+	case NodeInvalid:
+		return nil, errors.New(node.Error)
+	}
+
+	// key is not found
+	if node.KeySpan.End <= node.KeySpan.Start {
+		return nil, errors.New(node.Error)
+	}
+
+	// iterating over words before the key
+	for _, span := range wordSpans(line, len(line)-len(strings.TrimLeftFunc(line, unicode.IsSpace)), node.KeySpan.Start) {
+
+		// add directive
+		tokens = append(tokens, Token{Kind: TokenDirective, Text: line[span.Start:span.End], Span: span})
+	}
+
+	// add key
+	tokens = append(tokens, Token{Kind: TokenKey, Text: node.Key, Span: node.KeySpan})
+
+	// operator between the key and the value
+	if start, end := trimSpan(line, node.KeySpan.End, node.ValueSpan.Start); start < end {
+		tokens = append(tokens, Token{Kind: TokenOperator, Text: line[start:end], Span: Span{start, end}})
+	}
+
+	// start of the value part before the next reference
+	position := node.ValueSpan.Start
+
+	// iterating over references
+	for _, reference := range node.References {
+
+		// value before the reference
+		if position < reference.Span.Start {
+			tokens = append(tokens, Token{Kind: TokenValue, Text: line[position:reference.Span.Start], Span: Span{position, reference.Span.Start}})
+		}
+
+		// add reference
+		tokens = append(tokens, Token{Kind: TokenReference, Text: line[reference.Span.Start:reference.Span.End], Span: reference.Span})
+
+		// value continues after the reference
+		position = reference.Span.End
+	}
+
+	// rest of the value
+	if position < node.ValueSpan.End {
+		tokens = append(tokens, Token{Kind: TokenValue, Text: line[position:node.ValueSpan.End], Span: Span{position, node.ValueSpan.End}})
+	}
+
+	// comment after the value
+	if span, ok := commentSpan(node); ok && syn.Comments {
+		tokens = append(tokens, Token{Kind: TokenComment, Text: line[span.Start:span.End], Span: span})
+	}
+
+	// problem with the line
+	if len(node.Error) > 0 {
+		return tokens, errors.New(node.Error)
+	}
+
+	return tokens, nil
+}
+
+// wordSpans returns the positions of words separated by spaces between the columns of the text.
+func wordSpans(text string, start, end int) []Span {
+
+	// spans list
+	var spans []Span
+
+	// iterating over the columns
+	for i := start; i < end; {
+
+		// skip spaces
+		if unicode.IsSpace(rune(text[i])) {
+			i++
+			continue
+		}
+
+		// end of the word
+		j := i
+		for j < end && !unicode.IsSpace(rune(text[j])) {
+			j++
+		}
+
+		// add word
+		spans = append(spans, Span{i, j})
+
+		// next word
+		i = j
+	}
+
+	return spans
+}
@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// IsURLKey reports whether the key holds a URL judging by its name, e.g. DATABASE_URL or DB_URI.
+func IsURLKey(key string) bool {
+
+	// key in upper case
+	upper := strings.ToUpper(key)
+
+	return strings.HasSuffix(upper, "_URL") || strings.HasSuffix(upper, "_URI") || upper == "URL" || upper == "URI"
+}
+
+// ParseURL parses the absolute URL, a scheme is required and a host is required unless
+// the URL is opaque, e.g. mailto:admin@example.com, or a file URL.
+func ParseURL(value string) (*url.URL, error) {
+
+	// parse URL, the error is not returned as it contains the value with credentials
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, errors.New("invalid URL Syntax")
+	}
+
+	// scheme is missing
+	if len(u.Scheme) == 0 {
+		return nil, errors.New("scheme is missing")
+	}
+
+	// host is missing
+	if len(u.Host) == 0 && len(u.Opaque) == 0 && u.Scheme != "file" {
+		return nil, errors.New("host is missing")
+	}
+
+	return u, nil
+}
+
+// AsURL returns the value as an absolute URL.
+func (p Payload) AsURL() (*url.URL, error) {
+
+	// parse URL
+	u, err := ParseURL(p.Value)
+	if err != nil {
+		return nil, fmt.Errorf("line %d: value of key '%s' is not a URL: %s", p.Line, p.Key, err)
+	}
+
+	return u, nil
+}
+
+// BuildURL composes the URL from parts escaping the credentials and the path, so passwords with
+// special characters do not break it, e.g. BuildURL("postgres", user, password, host+":"+port, name).
+// Credentials are omitted if the username is empty, the password is omitted if it is empty.
+func BuildURL(scheme, username, password, host, path string) string {
+
+	// URL
+	u := url.URL{Scheme: scheme, Host: host}
+
+	// credentials
+	if len(username) > 0 {
+
+		// username only
+		u.User = url.User(username)
+
+		// username and password
+		if len(password) > 0 {
+			u.User = url.UserPassword(username, password)
+		}
+	}
+
+	// path
+	if len(path) > 0 {
+		u.Path = "/" + strings.TrimPrefix(path, "/")
+	}
+
+	return u.String()
+}
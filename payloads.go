@@ -0,0 +1,7 @@
+package envfile
+
+import "github.com/afonichev/envfile/parser"
+
+// Payloads is a list of payloads in the order their lines are written in the file,
+// a joined list (KEY[]) takes the position of its first item. Keys are unique.
+type Payloads = parser.Payloads
@@ -0,0 +1,65 @@
+package envfile
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// TestPayloadsOrder tests that payloads keep the order of the file and can be looked up by key.
+func TestPayloadsOrder(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "ZETA = 1\nALPHA = { ZETA }\nLIST[] = a\nMIDDLE = 2\nLIST[] = b\n")
+
+	// parse file
+	payloads, err := Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// keys are not in the order of the file
+	if keys := payloads.Keys(); !reflect.DeepEqual(keys, []string{"ZETA", "ALPHA", "LIST_0", "MIDDLE", "LIST_1"}) {
+		t.Errorf("unexpected order of keys: %v", keys)
+	}
+
+	// payload is not found by key
+	if payload, ok := payloads.Index()["ALPHA"]; !ok || payload.Value != "1" {
+		t.Errorf("expected ALPHA to be 1, got %+v", payload)
+	}
+
+	// payload is not found by lookup
+	if payload, ok := payloads.Lookup("MIDDLE"); !ok || payload.Line != 4 {
+		t.Errorf("expected MIDDLE on line 4, got %+v", payload)
+	}
+
+	// missing key is found
+	if _, ok := payloads.Lookup("MISSING"); ok {
+		t.Error("MISSING doesn't exist but lookup found it")
+	}
+
+	// encoded graphs of the same payloads
+	var first, second bytes.Buffer
+
+	// encode graph twice
+	if err := EncodeDOT(payloads, &first); err != nil {
+		t.Fatalf("error encoding graph: %v", err)
+	}
+	if err := EncodeDOT(payloads, &second); err != nil {
+		t.Fatalf("error encoding graph: %v", err)
+	}
+
+	// output is different between runs
+	if first.String() != second.String() {
+		t.Error("encoded graphs of the same payloads are different")
+	}
+
+	// encoded content of the same file
+	encoded, _ := Encode([]byte("KEY = value\n"))
+	again, _ := Encode([]byte("KEY = value\n"))
+
+	// output is different between runs
+	if encoded != again {
+		t.Error("encoded strings of the same content are different")
+	}
+}
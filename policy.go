@@ -0,0 +1,43 @@
+package envfile
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxExecLength is the maximum length of KEY=value in the environment of a new process,
+// the per-string limit of Linux; Windows allows 32767 characters for the whole block.
+const maxExecLength = 128 * 1024
+
+// WithValuePolicy sets the function checking values of keys before Load sets them,
+// a returned error stops loading and is reported with the key and its line.
+func WithValuePolicy(policy func(key, value string) error) Option {
+	return func(l *Loader) {
+
+		// set policy
+		l.policy = policy
+	}
+}
+
+// ExecPolicy is a value policy for keys passed to processes started with exec:
+// values must not contain NUL characters or line breaks and KEY=value must fit the OS limit.
+func ExecPolicy(key, value string) error {
+
+	// NUL character
+	if strings.IndexByte(value, 0) >= 0 {
+		return errors.New("value contains a NUL character")
+	}
+
+	// line break
+	if strings.ContainsAny(value, "\r\n") {
+		return errors.New("value contains a line break")
+	}
+
+	// length including the equal sign and the terminating NUL character
+	if length := len(key) + len(value) + 2; length > maxExecLength {
+		return fmt.Errorf("value is too long: %d bytes, the limit is %d", length, maxExecLength)
+	}
+
+	return nil
+}
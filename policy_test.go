@@ -0,0 +1,45 @@
+package envfile
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestValuePolicy tests rejection of values by the policy before they are set.
+func TestValuePolicy(t *testing.T) {
+
+	// deferred removal of environment variables
+	defer os.Unsetenv("ENVFILE_POLICY_VALID")
+	defer os.Unsetenv("ENVFILE_POLICY_MULTILINE")
+
+	// file content
+	filename := createFile(t, "export ENVFILE_POLICY_VALID = value\nexport ENVFILE_POLICY_MULTILINE = first\\nsecond\n")
+
+	// load file
+	err := NewLoader(WithValuePolicy(ExecPolicy)).Load(filename)
+
+	// error doesn't name the key
+	if err == nil || err.Error() != "["+filename+"] line 2: key 'ENVFILE_POLICY_MULTILINE': value contains a line break" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// rejected value is set
+	if _, ok := os.LookupEnv("ENVFILE_POLICY_MULTILINE"); ok {
+		t.Error("value is rejected but it is set")
+	}
+
+	// iterating over values rejected by the exec policy
+	for _, value := range []string{"a\x00b", "a\rb", strings.Repeat("a", maxExecLength)} {
+
+		// value is accepted
+		if err := ExecPolicy("KEY", value); err == nil {
+			t.Errorf("%.10q: value is invalid but policy didn't return an error", value)
+		}
+	}
+
+	// valid value is rejected
+	if err := ExecPolicy("KEY", "value with spaces"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
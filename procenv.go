@@ -0,0 +1,32 @@
+package envfile
+
+import "os"
+
+// FromPID reads the environment of the running process, see Loader.FromPID.
+func FromPID(pid int) (Payloads, error) {
+	return NewLoader().FromPID(pid)
+}
+
+// FromPID reads the environment of the running process as it was when the process started,
+// from /proc/<pid>/environ on Linux, so the process can be compared with the file it is expected
+// to run with. Reading the environment of a process of another user requires privileges.
+// Errors are reported for the environ file of the process, by the number of the record.
+func (l *Loader) FromPID(pid int) (Payloads, error) {
+
+	// file with the environment of the process
+	filename, err := environPath(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	// open file
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// deferred file close
+	defer file.Close()
+
+	return l.parseEnviron(filename, file)
+}
@@ -0,0 +1,8 @@
+package envfile
+
+import "fmt"
+
+// environPath returns the file with the environment of the process.
+func environPath(pid int) (string, error) {
+	return fmt.Sprintf("/proc/%d/environ", pid), nil
+}
@@ -0,0 +1,55 @@
+package envfile
+
+import (
+	"os/exec"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// TestFromPID tests reading of the environment of a running process.
+func TestFromPID(t *testing.T) {
+
+	// process with a known environment
+	cmd := exec.Command("sleep", "10")
+	cmd.Env = []string{"APP_NAME=service", "BASH_FUNC_greet%%=() {  echo hello\n}", "APP_URL=http://localhost/?a=b"}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("can't start process: %v", err)
+	}
+
+	// deferred stop of the process
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	// environment of the process, empty until the kernel finishes exec
+	var payloads Payloads
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(10 * time.Millisecond) {
+		var err error
+		if payloads, err = FromPID(cmd.Process.Pid); err != nil {
+			t.Fatalf("error reading environment of the process: %v", err)
+		}
+		if len(payloads) > 0 || time.Now().After(deadline) {
+			break
+		}
+	}
+
+	// expected payloads
+	expected := Payloads{
+		{Line: 1, Export: true, Literal: true, Key: "APP_NAME", Value: "service", Raw: "service", Kind: parser.KindOf("service")},
+		{Line: 3, Export: true, Literal: true, Key: "APP_URL", Value: "http://localhost/?a=b", Raw: "http://localhost/?a=b", Kind: parser.KindOf("http://localhost/?a=b")},
+	}
+
+	// payloads are different from expected
+	if !reflect.DeepEqual(payloads, expected) {
+		t.Errorf("expected %+v, got %+v", expected, payloads)
+	}
+
+	// process does not exist
+	if _, err := FromPID(-1); err == nil {
+		t.Error("process does not exist but reading didn't return an error")
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package envfile
+
+import "fmt"
+
+// environPath reports that the environment of a process can't be read on this system.
+func environPath(pid int) (string, error) {
+	return "", fmt.Errorf("process %d: reading the environment of a process is supported on Linux only", pid)
+}
@@ -0,0 +1,19 @@
+package envfile
+
+// WithProfile selects the profile whose section is applied, as in:
+//
+//	PORT = 8080
+//
+//	[production]
+//	PORT = 80
+//
+// Keys outside any section apply to all profiles, a key of the selected section replaces the key
+// of the same name written outside sections. Sections of other profiles are skipped, so without
+// the option only keys outside sections are parsed.
+func WithProfile(name string) Option {
+	return func(l *Loader) {
+
+		// set profile
+		l.profile = name
+	}
+}
@@ -0,0 +1,50 @@
+package envfile
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestProfile tests keys of sections applied with the selected profile.
+func TestProfile(t *testing.T) {
+
+	// file with keys of all profiles and sections of two profiles
+	filename := createFile(t, "HOST = localhost\nPORT = 8080\n\n[production]\nHOST = example.com\nTLS = on\n\n[development]\nDEBUG = on\n")
+
+	// expected values by profile
+	profiles := map[string]map[string]string{
+		"":            {"HOST": "localhost", "PORT": "8080"},
+		"production":  {"HOST": "example.com", "PORT": "8080", "TLS": "on"},
+		"development": {"HOST": "localhost", "PORT": "8080", "DEBUG": "on"},
+	}
+
+	// iterating over profiles
+	for profile, expected := range profiles {
+
+		// parse file with the profile
+		payloads, err := NewLoader(WithProfile(profile)).Parse(filename)
+		if err != nil {
+			t.Fatalf("error parsing env file: %v", err)
+		}
+
+		// all keys of the profile are parsed
+		if len(payloads) != len(expected) {
+			t.Errorf("expected %d keys of profile '%s', got %d", len(expected), profile, len(payloads))
+		}
+
+		// iterating over payloads
+		for _, payload := range payloads {
+
+			// value is different from expected
+			if value, ok := expected[payload.Key]; !ok || payload.Value != value {
+				t.Errorf("expected %s of profile '%s' to be '%s', got '%s'", payload.Key, profile, value, payload.Value)
+			}
+		}
+	}
+
+	// key written twice in the section of the profile
+	_, err := NewLoader(WithProfile("production")).Parse(createFile(t, "[production]\nHOST = a\nHOST = b\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3: duplicate key 'HOST'") {
+		t.Errorf("expected duplicate key error, got %v", err)
+	}
+}
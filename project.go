@@ -0,0 +1,124 @@
+package envfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ProjectRoot is the name of the project directory in the map returned by LoadProject.
+const ProjectRoot = "."
+
+// projectFiles are the env files of a project directory in order of precedence, the last wins.
+var projectFiles = []string{".envfile", ".envfile.local"}
+
+// LoadProject reads the env files of a project: .envfile and .envfile.local in the directory
+// and in every subdirectory having them, one per service. It returns exported and overloaded
+// keys by service name, the directory itself is named ProjectRoot. Every service gets the keys
+// of the root overridden by its own, .envfile.local overrides .envfile. The environment of
+// the process is not changed.
+func LoadProject(dir string) (map[string]map[string]string, error) {
+	return NewLoader().LoadProject(dir)
+}
+
+// LoadProject reads the env files of a project, see the package-level LoadProject.
+func (l *Loader) LoadProject(dir string) (map[string]map[string]string, error) {
+
+	// keys of the root
+	root, found, err := l.loadLayers(dir, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// keys by service
+	services := make(map[string]map[string]string)
+
+	// root has env files
+	if found {
+		services[ProjectRoot] = root
+	}
+
+	// entries of the directory
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// sort entries
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	// iterating over entries of the directory
+	for _, entry := range entries {
+
+		// entry is not a directory
+		if !entry.IsDir() {
+			continue
+		}
+
+		// keys of the service on top of the root ones
+		values, found, err := l.loadLayers(filepath.Join(dir, entry.Name()), root)
+		if err != nil {
+			return nil, err
+		}
+
+		// directory has env files
+		if found {
+			services[entry.Name()] = values
+		}
+	}
+
+	return services, nil
+}
+
+// loadLayers reads the env files of the directory on top of the base keys,
+// it reports whether any of the files exists.
+func (l *Loader) loadLayers(dir string, base map[string]string) (map[string]string, bool, error) {
+
+	// keys
+	values := make(map[string]string, len(base))
+
+	// iterating over base keys
+	for key, value := range base {
+
+		// copy key
+		values[key] = value
+	}
+
+	// any of the files exists
+	var found bool
+
+	// iterating over env files of the directory
+	for _, name := range projectFiles {
+
+		// file name
+		filename := filepath.Join(dir, name)
+
+		// parse file
+		payloads, err := l.Parse(filename)
+
+		// file does not exist
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		// parse error
+		if err != nil {
+			return nil, false, err
+		}
+
+		// update status
+		found = true
+
+		// iterating over a list of payloads
+		for _, payload := range payloads {
+
+			// key is exported or overloaded
+			if payload.Export || payload.Overload {
+				values[payload.Key] = payload.Value
+			}
+		}
+	}
+
+	return values, found, nil
+}
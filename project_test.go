@@ -0,0 +1,77 @@
+package envfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestLoadProject tests reading env files of a project with services.
+func TestLoadProject(t *testing.T) {
+
+	// project directory
+	dir, err := ioutil.TempDir("", "project")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v", err)
+	}
+
+	// deferred removal of the project directory
+	defer os.RemoveAll(dir)
+
+	// files of the project
+	files := map[string]string{
+		".envfile":              "export HOST = localhost\nexport PORT = 80\nLOCAL = local\n",
+		".envfile.local":        "export PORT = 8080\n",
+		"api/.envfile":          "export NAME = api\nexport HOST = api.local\n",
+		"worker/.envfile":       "export NAME = worker\n",
+		"worker/.envfile.local": "export NAME = local worker\n",
+		"docs/README.md":        "not a service\n",
+	}
+
+	// iterating over files
+	for name, content := range files {
+
+		// file name
+		filename := filepath.Join(dir, name)
+
+		// create directory
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatalf("error creating directory: %v", err)
+		}
+
+		// write file
+		if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatalf("error writing file: %v", err)
+		}
+	}
+
+	// load project
+	services, err := LoadProject(dir)
+	if err != nil {
+		t.Fatalf("error loading project: %v", err)
+	}
+
+	// expected keys by service
+	expected := map[string]map[string]string{
+		ProjectRoot: {"HOST": "localhost", "PORT": "8080"},
+		"api":       {"HOST": "api.local", "PORT": "8080", "NAME": "api"},
+		"worker":    {"HOST": "localhost", "PORT": "8080", "NAME": "local worker"},
+	}
+
+	// keys are different from expected
+	if !reflect.DeepEqual(services, expected) {
+		t.Errorf("expected %v, got %v", expected, services)
+	}
+
+	// invalid file of a service
+	if err := ioutil.WriteFile(filepath.Join(dir, "api", ".envfile.local"), []byte("invalid\n"), 0644); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+
+	// invalid file is loaded
+	if _, err := LoadProject(dir); err == nil {
+		t.Error("service file is invalid but load didn't return an error")
+	}
+}
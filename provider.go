@@ -0,0 +1,87 @@
+package envfile
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SecretProvider resolves references to secrets written as URIs, e.g. { op://vault/item/field }.
+type SecretProvider interface {
+
+	// Scheme returns the URI scheme handled by the provider, e.g. "op".
+	Scheme() string
+
+	// Resolve returns the secret referenced by the URI.
+	Resolve(uri string) (string, error)
+}
+
+// LeasedProvider is a provider of secrets that expire, e.g. Vault leases or rotating database credentials.
+// Keys referencing such secrets get the TTL in the result of Load and are refreshed by Refresh.
+type LeasedProvider interface {
+	SecretProvider
+
+	// ResolveLease returns the secret referenced by the URI and how long it stays valid, zero if it doesn't expire.
+	ResolveLease(uri string) (string, time.Duration, error)
+}
+
+// WithProvider adds the provider of secrets referenced by URIs with its scheme,
+// they are resolved before environment variables.
+func WithProvider(provider SecretProvider) Option {
+	return func(l *Loader) {
+
+		// providers are not set yet
+		if l.providers == nil {
+			l.providers = make(map[string]SecretProvider)
+		}
+
+		// add provider
+		l.providers[provider.Scheme()] = provider
+	}
+}
+
+// provide returns the secret if the variable is a URI with the scheme of a provider,
+// it reports false if there is no such provider. Leases are recorded for the file and line.
+func (l *Loader) provide(filename string, line int, variable string) (string, bool, error) {
+
+	// position of the scheme separator
+	position := strings.Index(variable, "://")
+	if position < 0 {
+		return "", false, nil
+	}
+
+	// provider of the scheme
+	provider, ok := l.providers[variable[:position]]
+	if !ok {
+		return "", false, nil
+	}
+
+	// provider of expiring secrets
+	if leased, ok := provider.(LeasedProvider); ok {
+
+		// resolve secret with its lease
+		value, ttl, err := leased.ResolveLease(variable)
+		if err != nil {
+			return "", false, fmt.Errorf("can't resolve '%s': %s", variable, err)
+		}
+
+		// record lease of the line
+		l.addLease(filename, line, ttl)
+
+		// record provider call
+		l.count(filename, Stats{ProviderCalls: 1})
+
+		return value, true, nil
+	}
+
+	// resolve secret
+	value, err := provider.Resolve(variable)
+	if err != nil {
+		return "", false, fmt.Errorf("can't resolve '%s': %s", variable, err)
+	}
+
+	// record provider call
+	l.count(filename, Stats{ProviderCalls: 1})
+
+	return value, true, nil
+}
@@ -0,0 +1,43 @@
+package envfile
+
+import "fmt"
+
+// Read returns the keys of the files as they would be in the environment after the package-level Load,
+// see Loader.Read.
+func Read(filenames ...string) (map[string]string, error) {
+	return std.Read(filenames...)
+}
+
+// Read returns the exported and overloaded keys of the files with the values they would have after Load,
+// without changing the environment: a key already set in the environment keeps its value unless it is
+// overloaded. The map can be passed to subprocesses or libraries. The value policy is checked as by Load.
+func (l *Loader) Read(filenames ...string) (map[string]string, error) {
+
+	// values of the files
+	values, err := l.ParseValues(filenames...)
+	if err != nil {
+		return nil, err
+	}
+
+	// keys and values
+	env := make(map[string]string, len(values.keys))
+
+	// iterating over keys of the files
+	for _, key := range values.keys {
+
+		// effective value of the key
+		current := values.values[values.indexKey(key)]
+
+		// value of the file is rejected by the policy
+		if l.policy != nil && len(current.file) > 0 {
+			if err := l.policy(key, current.value); err != nil {
+				return nil, fmt.Errorf("[%s] line %d: key '%s': %s", current.file, current.line, key, err)
+			}
+		}
+
+		// add key
+		env[key] = current.value
+	}
+
+	return env, nil
+}
@@ -0,0 +1,48 @@
+package envfile
+
+import (
+	"os"
+	"testing"
+)
+
+// TestRead tests reading of keys without changing the environment.
+func TestRead(t *testing.T) {
+
+	// variables of the environment
+	os.Setenv("READ_KEPT", "env")
+	defer os.Unsetenv("READ_KEPT")
+	os.Setenv("READ_OVERLOADED", "env")
+	defer os.Unsetenv("READ_OVERLOADED")
+
+	// file with exported, overloaded and local keys
+	filename := createFile(t, "export READ_KEPT = file\noverload READ_OVERLOADED = file\nexport READ_NEW = file\nREAD_LOCAL = file\n")
+
+	// read file
+	env, err := Read(filename)
+	if err != nil {
+		t.Fatalf("error reading env file: %v", err)
+	}
+
+	// expected keys and values
+	expected := map[string]string{"READ_KEPT": "env", "READ_OVERLOADED": "file", "READ_NEW": "file"}
+
+	// number of keys
+	if len(env) != len(expected) {
+		t.Errorf("expected %v, got %v", expected, env)
+	}
+
+	// iterating over expected keys
+	for key, value := range expected {
+		if env[key] != value {
+			t.Errorf("expected %s to be %s, got %s", key, value, env[key])
+		}
+	}
+
+	// environment is not changed
+	if value := os.Getenv("READ_OVERLOADED"); value != "env" {
+		t.Errorf("expected READ_OVERLOADED to stay env, got %s", value)
+	}
+	if _, ok := os.LookupEnv("READ_NEW"); ok {
+		t.Error("expected READ_NEW not to be set")
+	}
+}
@@ -0,0 +1,33 @@
+package envfile
+
+import (
+	"io"
+	"strings"
+)
+
+// readerName is used in error messages instead of the file name.
+const readerName = "reader"
+
+// ParseReader parses content with environment variables from the reader, e.g. a network connection,
+// an archive or an in-memory buffer, payloads are in the order they are written.
+func ParseReader(r io.Reader) (Payloads, error) {
+	return NewLoader().ParseReader(r)
+}
+
+// ParseReader parses content with environment variables from the reader, payloads are in the order
+// they are written. Errors are reported for the "reader" file.
+func (l *Loader) ParseReader(r io.Reader) (Payloads, error) {
+	return l.parseFrom(readerName, r)
+}
+
+// ParseString parses content with environment variables, e.g. a response of a secrets service,
+// payloads are in the order they are written.
+func ParseString(content string) (Payloads, error) {
+	return NewLoader().ParseString(content)
+}
+
+// ParseString parses content with environment variables, payloads are in the order they are written.
+// Errors are reported for the "reader" file.
+func (l *Loader) ParseString(content string) (Payloads, error) {
+	return l.ParseReader(strings.NewReader(content))
+}
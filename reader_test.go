@@ -0,0 +1,46 @@
+package envfile
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestParseReader tests parsing of content from the reader.
+func TestParseReader(t *testing.T) {
+
+	// parse content
+	payloads, err := ParseReader(strings.NewReader("HOST = localhost\nexport URL = http://{ HOST }\n"))
+	if err != nil {
+		t.Fatalf("error parsing content: %v", err)
+	}
+
+	// value with the replaced reference
+	if payload, _ := payloads.Lookup("URL"); payload.Value != "http://localhost" || payload.Line != 2 {
+		t.Errorf("expected URL on line 2 to be http://localhost, got %+v", payload)
+	}
+
+	// error names the reader
+	if _, err := ParseReader(strings.NewReader("invalid line\n")); err == nil || err.Error() != "[reader] line 1: can't split line into key and value" {
+		t.Errorf("expected error of the reader, got %v", err)
+	}
+}
+
+// TestParseString tests parsing of content from the string.
+func TestParseString(t *testing.T) {
+
+	// variable of the environment
+	os.Setenv("ENVFILE_PARSE_STRING", "value")
+	defer os.Unsetenv("ENVFILE_PARSE_STRING")
+
+	// parse content with the reference to the environment
+	payloads, err := ParseString("export NAME = { ENVFILE_PARSE_STRING }")
+	if err != nil {
+		t.Fatalf("error parsing content: %v", err)
+	}
+
+	// exported payload with the value of the environment
+	if payload, _ := payloads.Lookup("NAME"); payload.Value != "value" || !payload.Export {
+		t.Errorf("expected exported NAME to be value, got %+v", payload)
+	}
+}
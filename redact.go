@@ -0,0 +1,157 @@
+package envfile
+
+import "unicode/utf8"
+
+// partialVisible is the number of trailing characters shown by RedactPartial.
+const partialVisible = 4
+
+// partialLength is the minimum length of values shown partially, shorter ones are fully masked.
+const partialLength = 12
+
+// Redactor hides values of keys holding secrets in reports, hovers and other output.
+type Redactor interface {
+
+	// Redact returns the value of the key holding a secret as it is shown.
+	Redact(key, value string) string
+}
+
+// RedactorFunc is a function used as a Redactor.
+type RedactorFunc func(key, value string) string
+
+// Redact returns the value of the key holding a secret as it is shown.
+func (f RedactorFunc) Redact(key, value string) string {
+	return f(key, value)
+}
+
+var (
+
+	// RedactMask replaces the whole value with asterisks, it is the default.
+	RedactMask Redactor = RedactorFunc(func(key, value string) string {
+		return redacted
+	})
+
+	// RedactPartial keeps the last four characters after asterisks, e.g. ******9a2f, so a secret
+	// can be told apart from another one; values shorter than twelve characters are fully masked.
+	RedactPartial Redactor = RedactorFunc(func(key, value string) string {
+
+		// value is too short to show a part of it
+		if utf8.RuneCountInString(value) < partialLength {
+			return redacted
+		}
+
+		// characters of the value
+		chars := []rune(value)
+
+		return redacted + string(chars[len(chars)-partialVisible:])
+	})
+
+	// RedactHash replaces the value with the beginning of its SHA-256 hash, e.g. sha256:1f2e3d4c,
+	// so equal values are recognized across files and processes without revealing them.
+	RedactHash Redactor = RedactorFunc(func(key, value string) string {
+		return "sha256:" + hashValue(value)[:8]
+	})
+)
+
+// WithRedactor sets the way values of keys holding secrets are hidden by Report, Redact and
+// Result.Redacted, RedactMask by default. Keys are judged by IsSecret before the redactor is used.
+func WithRedactor(redactor Redactor) Option {
+	return func(l *Loader) {
+
+		// set redactor
+		l.redactor = redactor
+	}
+}
+
+// Redact hides the value of the key that looks like it holds a secret, see Loader.Redact.
+func Redact(key, value string) string {
+	return std.Redact(key, value)
+}
+
+// Redact hides the value of the key that looks like it holds a secret with the redactor of the loader.
+func (l *Loader) Redact(key, value string) string {
+	return redact(l.redactor, key, value)
+}
+
+// Redacted returns a copy of the result with values of keys holding secrets hidden by the redactor,
+// RedactMask if it is nil, e.g. to write the result to logs.
+func (r *Result) Redacted(redactor Redactor) *Result {
+
+	// copy of the result
+	result := &Result{
+		Files:      append([]string(nil), r.Files...),
+		Entries:    make([]Entry, len(r.Entries)),
+		Duplicates: redactDuplicates(r.Duplicates, r.Entries),
+		Stats:      r.Stats,
+	}
+
+	// iterating over a list of entries
+	for i, entry := range r.Entries {
+
+		// hide secret value
+		entry.Value = redact(redactor, entry.Key, entry.Value)
+
+		// set entry
+		result.Entries[i] = entry
+	}
+
+	return result
+}
+
+// redactDuplicates returns a copy of the duplicates without hashes of values of keys holding secrets,
+// a hash of a short secret is enough to find it by trying values. Keys are judged with their loaded values.
+func redactDuplicates(duplicates []Duplicate, entries []Entry) []Duplicate {
+
+	// loaded values by key
+	values := make(map[string]string)
+	for _, entry := range entries {
+		values[entry.Key] = entry.Value
+	}
+
+	// copy of the duplicates
+	list := make([]Duplicate, 0, len(duplicates))
+
+	// iterating over a list of duplicates
+	for _, duplicate := range duplicates {
+
+		// key holds a secret
+		if IsSecret(duplicate.Key, values[duplicate.Key]) {
+
+			// copy of the definitions
+			definitions := make([]Definition, len(duplicate.Definitions))
+
+			// iterating over definitions
+			for i, def := range duplicate.Definitions {
+
+				// hide hash of the value
+				def.Hash = ""
+
+				// set definition
+				definitions[i] = def
+			}
+
+			// update definitions
+			duplicate.Definitions = definitions
+		}
+
+		// add duplicate to list
+		list = append(list, duplicate)
+	}
+
+	return list
+}
+
+// redact hides the value of the key holding a secret with the redactor, RedactMask if it is nil.
+func redact(redactor Redactor, key, value string) string {
+
+	// key does not hold a secret or the value is empty
+	if len(value) == 0 || !IsSecret(key, value) {
+		return value
+	}
+
+	// default redactor
+	if redactor == nil {
+		redactor = RedactMask
+	}
+
+	return redactor.Redact(key, value)
+}
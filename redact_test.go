@@ -0,0 +1,85 @@
+package envfile
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// TestRedactors tests the built-in ways of hiding secrets.
+func TestRedactors(t *testing.T) {
+
+	// expected values by redactor
+	cases := []struct {
+		redactor Redactor
+		key      string
+		value    string
+		expected string
+	}{
+		{nil, "DB_PASSWORD", "qwerty-secret-9a2f", redacted},
+		{RedactMask, "DB_PASSWORD", "qwerty-secret-9a2f", redacted},
+		{RedactPartial, "DB_PASSWORD", "qwerty-secret-9a2f", redacted + "9a2f"},
+		{RedactPartial, "DB_PASSWORD", "qwerty", redacted},
+		{RedactHash, "DB_PASSWORD", "qwerty", "sha256:" + hashValue("qwerty")[:8]},
+		{RedactHash, "HOST", "localhost", "localhost"},
+		{RedactorFunc(func(key, value string) string { return "<" + key + ">" }), "API_TOKEN", "abc", "<API_TOKEN>"},
+	}
+
+	// iterating over cases
+	for _, c := range cases {
+
+		// value is shown as expected
+		if value := NewLoader(WithRedactor(c.redactor)).Redact(c.key, c.value); value != c.expected {
+			t.Errorf("%s = %s: expected %q, got %q", c.key, c.value, c.expected, value)
+		}
+	}
+}
+
+// TestRedactedResult tests hiding of secrets in the result and the report.
+func TestRedactedResult(t *testing.T) {
+
+	// deferred removal of environment variables
+	defer os.Unsetenv("ENVFILE_REDACT_HOST")
+	defer os.Unsetenv("ENVFILE_REDACT_PASSWORD")
+
+	// file content
+	filename := createFile(t, "export ENVFILE_REDACT_HOST = localhost\nexport ENVFILE_REDACT_PASSWORD = qwerty-secret-9a2f\n")
+
+	// loader showing the end of secrets
+	loader := NewLoader(WithRedactor(RedactPartial))
+
+	// load file
+	if err := loader.Load(filename); err != nil {
+		t.Fatalf("error loading env file: %v", err)
+	}
+
+	// result with hidden secrets
+	result := loader.Result().Redacted(RedactHash)
+	if result.Entries[0].Value != "localhost" || result.Entries[1].Value != "sha256:"+hashValue("qwerty-secret-9a2f")[:8] {
+		t.Errorf("unexpected entries: %+v", result.Entries)
+	}
+
+	// result of the loader is unchanged
+	if loader.Result().Entries[1].Value != "qwerty-secret-9a2f" {
+		t.Errorf("result of the loader is changed: %+v", loader.Result().Entries)
+	}
+
+	// render report
+	data, err := loader.Report()
+	if err != nil {
+		t.Fatalf("error rendering report: %v", err)
+	}
+
+	// decoded report
+	var rep report
+
+	// decode report
+	if err := json.Unmarshal(data, &rep); err != nil {
+		t.Fatalf("error decoding report: %v", err)
+	}
+
+	// secret is hidden by the redactor of the loader
+	if rep.Entries[1].Value != redacted+"9a2f" {
+		t.Errorf("expected %q, got %q", redacted+"9a2f", rep.Entries[1].Value)
+	}
+}
@@ -0,0 +1,38 @@
+package envfile
+
+import "os"
+
+// reexecVariable marks the process re-executed by ReExec, so it doesn't re-execute itself again.
+const reexecVariable = "ENVFILE_REEXEC"
+
+// ReExec re-executes the current binary with the environment after the package-level Load,
+// see Loader.ReExec.
+func ReExec(filenames ...string) error {
+	return std.ReExec(filenames...)
+}
+
+// ReExec re-executes the current binary with the same arguments and the environment computed by ApplyEnviron,
+// so the whole environment is in place before any init code of the new process runs. The process is replaced
+// on Unix, on Windows the binary is started as a child and the process exits with its exit code.
+// ReExec returns on error only, or immediately in the re-executed process marked with ENVFILE_REEXEC.
+func (l *Loader) ReExec(filenames ...string) error {
+
+	// process is already re-executed
+	if _, ok := os.LookupEnv(reexecVariable); ok {
+		return nil
+	}
+
+	// environment after loading
+	environ, err := l.ApplyEnviron(filenames...)
+	if err != nil {
+		return err
+	}
+
+	// path of the current binary
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	return reexec(executable, os.Args, append(environ, reexecVariable+"=1"))
+}
@@ -0,0 +1,10 @@
+//go:build !windows && !js && !wasip1
+
+package envfile
+
+import "syscall"
+
+// reexec replaces the current process with the binary.
+func reexec(executable string, args []string, environ []string) error {
+	return syscall.Exec(executable, args, environ)
+}
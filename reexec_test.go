@@ -0,0 +1,55 @@
+package envfile
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestReExec tests re-execution of the test binary with the environment of the file.
+func TestReExec(t *testing.T) {
+
+	// re-executed helper process prints the variable
+	if filename := os.Getenv("ENVFILE_REEXEC_TEST_FILE"); len(filename) > 0 {
+
+		// re-execute with the file
+		if err := ReExec(filename); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		fmt.Print("NAME=" + os.Getenv("ENVFILE_REEXEC_TEST_NAME"))
+		os.Exit(0)
+	}
+
+	// file with the variable
+	filename := createFile(t, "export ENVFILE_REEXEC_TEST_NAME = value")
+
+	// run helper process
+	cmd := exec.Command(os.Args[0], "-test.run=^TestReExec$")
+	cmd.Env = append(os.Environ(), "ENVFILE_REEXEC_TEST_FILE="+filename)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("error running helper process: %v: %s", err, output)
+	}
+
+	// variable is set before the helper process starts
+	if !strings.Contains(string(output), "NAME=value") {
+		t.Errorf("expected NAME=value in the output, got %q", output)
+	}
+}
+
+// TestReExecMarked tests that the re-executed process doesn't re-execute again.
+func TestReExecMarked(t *testing.T) {
+
+	// process is marked as re-executed
+	os.Setenv(reexecVariable, "1")
+	defer os.Unsetenv(reexecVariable)
+
+	// nothing is done, not even reading the file
+	if err := ReExec("does-not-exist.envfile"); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build js || wasip1
+
+package envfile
+
+import "errors"
+
+// reexec reports that js/wasm and wasip1 can't run another binary.
+func reexec(executable string, args []string, environ []string) error {
+	return errors.New("re-executing is not supported on this platform")
+}
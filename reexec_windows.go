@@ -0,0 +1,35 @@
+package envfile
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// reexec runs the binary as a child sharing standard streams and exits with its exit code,
+// Windows has no way to replace the current process.
+func reexec(executable string, args []string, environ []string) error {
+
+	// child process with the same arguments
+	cmd := exec.Command(executable, args[1:]...)
+	cmd.Env = environ
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// run child process
+	if err := cmd.Run(); err != nil {
+
+		// child process exited with error
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+
+		return err
+	}
+
+	os.Exit(0)
+
+	return nil
+}
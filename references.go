@@ -0,0 +1,11 @@
+package envfile
+
+import "github.com/afonichev/envfile/parser"
+
+// Ref is a reference to a variable in the value of a key.
+type Ref = parser.Ref
+
+// References returns { VAR } references of the payloads by referencing key.
+func References(payloads []Payload) map[string][]Ref {
+	return parser.References(payloads)
+}
@@ -0,0 +1,39 @@
+package envfile
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+// TestReferences tests extraction of references.
+func TestReferences(t *testing.T) {
+
+	// set environment variable for the test
+	os.Setenv("ENVFILE_REFERENCES_HOST", "localhost")
+
+	// deferred removal of the environment variable
+	defer os.Unsetenv("ENVFILE_REFERENCES_HOST")
+
+	// file content
+	filename := createFile(t, "PORT = 80\nexport URL = http://{ ENVFILE_REFERENCES_HOST }:{PORT}\nLITERAL = {{ PORT }}\nraw RAW = { PORT }\nQUOTED = '{ PORT }'\n")
+
+	// parse file
+	payloads, err := Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// expected references
+	expected := map[string][]Ref{
+		"URL": {
+			{Name: "ENVFILE_REFERENCES_HOST", Line: 2, Span: Span{Start: 7, End: 34}, Internal: false},
+			{Name: "PORT", Line: 2, Span: Span{Start: 35, End: 41}, Internal: true},
+		},
+	}
+
+	// references are different from expected
+	if references := References(payloads); !reflect.DeepEqual(references, expected) {
+		t.Errorf("expected references %+v, got %+v", expected, references)
+	}
+}
@@ -0,0 +1,230 @@
+package envfile
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// Refresh keeps the keys of the last package-level Load that reference leased secrets up to date,
+// see Loader.Refresh.
+func Refresh(ctx context.Context, callback func(key, value string)) error {
+	return std.Refresh(ctx, callback)
+}
+
+// refreshMargin is the divisor of the TTL giving the time left when leased keys are refreshed,
+// a fifth, so rotated values are set before the leases of the old ones expire.
+const refreshMargin = 5
+
+// Refresh keeps the keys of the last Load that reference secrets of a LeasedProvider up to date: when 80% of
+// the shortest TTL of the result has passed, the files are parsed again and changed values are set to the environment,
+// the callback, if any, is called for every one of them. Keys kept from the environment are not touched.
+// Refresh blocks until the context is done or no key is leased, so it is usually run in a goroutine.
+func (l *Loader) Refresh(ctx context.Context, callback func(key, value string)) error {
+
+	for {
+
+		// result of the last loading
+		result := l.Result()
+		if result == nil {
+			return nil
+		}
+
+		// time until the first lease expires
+		ttl := shortestTTL(result)
+		if ttl == 0 {
+			return nil
+		}
+
+		// timer of the lease, fired before the lease expires
+		timer := time.NewTimer(ttl - ttl/refreshMargin)
+
+		select {
+
+		// refresh is cancelled
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+
+		// lease is about to expire
+		case <-timer.C:
+		}
+
+		// resolve leased keys again
+		if err := l.refresh(result, callback); err != nil {
+			return err
+		}
+	}
+}
+
+// refresh parses the files of the result again, sets changed values of leased keys and stores the updated result.
+func (l *Loader) refresh(result *Result, callback func(key, value string)) error {
+
+	// updated result
+	refreshed := &Result{Files: result.Files, Duplicates: result.Duplicates, Stats: result.Stats}
+
+	// payloads and leases of the files by file name
+	parsed := make(map[string]Payloads)
+	leases := make(map[string]map[int]time.Duration)
+
+	// iterating over loaded files
+	for _, filename := range result.Files {
+
+		// file is already parsed
+		if _, ok := leases[filename]; ok {
+			continue
+		}
+
+		// parse file
+		payloads, err := l.Parse(filename)
+		if err != nil {
+			return err
+		}
+
+		// store leases of the file
+		leases[filename] = l.takeLeases(filename)
+
+		// iterating over a list of payloads
+		for _, payload := range payloads {
+
+			// store payload by the file it is written in, entries of included keys name the included file
+			parsed[parser.PayloadFile(payload, filename)] = append(parsed[parser.PayloadFile(payload, filename)], payload)
+
+			// store leases of the included file
+			if _, ok := leases[parser.PayloadFile(payload, filename)]; !ok {
+				leases[payload.File] = l.takeLeases(payload.File)
+			}
+		}
+	}
+
+	// iterating over entries
+	for _, entry := range result.Entries {
+
+		// key is not leased or is kept from the environment
+		if entry.TTL == 0 || entry.Status == StatusKept {
+			refreshed.Entries = append(refreshed.Entries, entry)
+			continue
+		}
+
+		// iterating over payloads of the file
+		for _, payload := range parsed[entry.File] {
+
+			// payload of another line
+			if payload.Line != entry.Line || payload.Key != entry.Key {
+				continue
+			}
+
+			// new lease of the line
+			entry.TTL = leases[entry.File][entry.Line]
+
+			// value is not rotated
+			if payload.Value == entry.Value {
+				break
+			}
+
+			// value is rejected by the policy
+			if l.policy != nil {
+				if err := l.policy(payload.Key, payload.Value); err != nil {
+					return fmt.Errorf("[%s] line %d: key '%s': %s", entry.File, payload.Line, payload.Key, err)
+				}
+			}
+
+			// set new value to environment variable
+			if err := l.setenv(payload.Key, payload.Value); err != nil {
+				return fmt.Errorf("[%s] %s", entry.File, err)
+			}
+
+			// update entry
+			entry.Value = payload.Value
+			entry.Status = StatusSet
+
+			// write audit record
+			if err := l.writeAudit(entry); err != nil {
+				return fmt.Errorf("[%s] can't write audit record: %s", entry.File, err)
+			}
+
+			// notify about the new value
+			if callback != nil {
+				callback(payload.Key, payload.Value)
+			}
+
+			break
+		}
+
+		// add entry to result
+		refreshed.Entries = append(refreshed.Entries, entry)
+	}
+
+	// store result
+	l.setResult(refreshed)
+
+	return nil
+}
+
+// shortestTTL returns the shortest TTL of the entries, zero if no entry is leased.
+func shortestTTL(result *Result) time.Duration {
+
+	// shortest TTL
+	var shortest time.Duration
+
+	// iterating over entries
+	for _, entry := range result.Entries {
+
+		// entry is leased and expires earlier
+		if entry.TTL > 0 && entry.Status != StatusKept && (shortest == 0 || entry.TTL < shortest) {
+			shortest = entry.TTL
+		}
+	}
+
+	return shortest
+}
+
+// addLease records the lease of the secret used in the line of the file, the shortest one is kept.
+func (l *Loader) addLease(filename string, line int, ttl time.Duration) {
+
+	// secret doesn't expire
+	if ttl <= 0 {
+		return
+	}
+
+	// lock leases
+	l.mu.Lock()
+
+	// deferred unlock of leases
+	defer l.mu.Unlock()
+
+	// leases are not set yet
+	if l.leases == nil {
+		l.leases = make(map[string]map[int]time.Duration)
+	}
+
+	// leases of the file are not set yet
+	if l.leases[filename] == nil {
+		l.leases[filename] = make(map[int]time.Duration)
+	}
+
+	// lease is shorter than the recorded one
+	if current, ok := l.leases[filename][line]; !ok || ttl < current {
+		l.leases[filename][line] = ttl
+	}
+}
+
+// takeLeases returns and forgets the leases recorded for the lines of the file.
+func (l *Loader) takeLeases(filename string) map[int]time.Duration {
+
+	// lock leases
+	l.mu.Lock()
+
+	// deferred unlock of leases
+	defer l.mu.Unlock()
+
+	// leases of the file
+	leases := l.leases[filename]
+
+	// forget leases
+	delete(l.leases, filename)
+
+	return leases
+}
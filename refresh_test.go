@@ -0,0 +1,162 @@
+package envfile
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// rotatingProvider returns a new password every time the secret is resolved.
+type rotatingProvider struct {
+
+	// number of resolved secrets
+	count atomic.Int32
+
+	// TTL of the secrets, 20ms if zero
+	ttl time.Duration
+}
+
+// Scheme returns the scheme of the provider.
+func (p *rotatingProvider) Scheme() string {
+	return "rotating"
+}
+
+// Resolve returns the next password.
+func (p *rotatingProvider) Resolve(uri string) (string, error) {
+	return fmt.Sprintf("password-%d", p.count.Add(1)), nil
+}
+
+// ResolveLease returns the next password valid for a short time.
+func (p *rotatingProvider) ResolveLease(uri string) (string, time.Duration, error) {
+
+	// next password
+	value, err := p.Resolve(uri)
+
+	// TTL is not set
+	if p.ttl == 0 {
+		return value, 20 * time.Millisecond, err
+	}
+
+	return value, p.ttl, err
+}
+
+// TestRefresh tests refreshing of keys referencing leased secrets.
+func TestRefresh(t *testing.T) {
+
+	// loader with the provider of expiring secrets
+	loader := NewLoader(WithProvider(&rotatingProvider{}))
+
+	// file with leased and plain keys
+	filename := createFile(t, "export REFRESH_PASSWORD = { rotating://db/password }\nexport REFRESH_HOST = localhost\n")
+
+	// deferred unset of the variables
+	defer os.Unsetenv("REFRESH_PASSWORD")
+	defer os.Unsetenv("REFRESH_HOST")
+
+	// load file
+	if err := loader.Load(filename); err != nil {
+		t.Fatalf("error loading env file: %v", err)
+	}
+
+	// TTL of the leased key only
+	if entries := loader.Result().Entries; entries[0].TTL != 20*time.Millisecond || entries[1].TTL != 0 {
+		t.Errorf("expected TTL of REFRESH_PASSWORD only, got %+v", entries)
+	}
+
+	// context of the refresher
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// refreshed values
+	values := make(chan string, 1)
+
+	// refresh in the background
+	done := make(chan error)
+	go func() {
+		done <- loader.Refresh(ctx, func(key, value string) {
+			if key == "REFRESH_PASSWORD" {
+				select {
+				case values <- value:
+				default:
+				}
+			}
+		})
+	}()
+
+	// rotated value
+	select {
+	case value := <-values:
+		if value != "password-2" {
+			t.Errorf("expected password-2, got %s", value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("value was not refreshed")
+	}
+
+	// stop refresher
+	cancel()
+
+	// refresher is cancelled
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	// environment is updated
+	if value := os.Getenv("REFRESH_PASSWORD"); value == "password-1" {
+		t.Errorf("expected rotated value, got %s", value)
+	}
+}
+
+// TestRefreshBeforeExpiry tests that leased keys are refreshed before their leases expire.
+func TestRefreshBeforeExpiry(t *testing.T) {
+
+	// TTL of the secrets
+	ttl := 500 * time.Millisecond
+
+	// loader with the provider of expiring secrets
+	loader := NewLoader(WithProvider(&rotatingProvider{ttl: ttl}))
+
+	// file with a leased key
+	filename := createFile(t, "export EXPIRY_PASSWORD = { rotating://db/password }\n")
+
+	// deferred unset of the variable
+	defer os.Unsetenv("EXPIRY_PASSWORD")
+
+	// load file
+	start := time.Now()
+	if err := loader.Load(filename); err != nil {
+		t.Fatalf("error loading env file: %v", err)
+	}
+
+	// context of the refresher
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// deferred stop of the refresher
+	defer cancel()
+
+	// times of the refreshes
+	refreshed := make(chan time.Time, 1)
+
+	// refresh in the background
+	go loader.Refresh(ctx, func(key, value string) {
+		select {
+		case refreshed <- time.Now():
+		default:
+		}
+	})
+
+	// time of the first refresh
+	select {
+	case at := <-refreshed:
+
+		// value is refreshed after the lease expired
+		if elapsed := at.Sub(start); elapsed >= ttl {
+			t.Errorf("expected refresh before the lease of %s expires, got it after %s", ttl, elapsed)
+		}
+
+	case <-time.After(2 * ttl):
+		t.Fatal("value was not refreshed")
+	}
+}
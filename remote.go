@@ -0,0 +1,102 @@
+package envfile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// Remote is a service keeping environment variables of projects, e.g. Doppler or Infisical.
+type Remote interface {
+
+	// Fetch returns the key/value pairs of the configuration of the project.
+	Fetch(project, config string) (map[string]string, error)
+}
+
+// WithRemote registers the remote service for names like scheme://project/config,
+// which can be passed to Load and Parse among file names to be merged with local files
+// under the usual precedence. Every key of the service is exported, values are taken as they are.
+func WithRemote(scheme string, remote Remote) Option {
+	return func(l *Loader) {
+
+		// remote services are not set yet
+		if l.remotes == nil {
+			l.remotes = make(map[string]Remote)
+		}
+
+		// add remote service
+		l.remotes[scheme] = remote
+	}
+}
+
+// remote returns the service and the project and configuration if the name is a remote one.
+func (l *Loader) remote(name string) (Remote, string, string, bool) {
+
+	// position of the scheme separator
+	position := strings.Index(name, "://")
+	if position < 0 {
+		return nil, "", "", false
+	}
+
+	// remote service of the scheme
+	remote, ok := l.remotes[name[:position]]
+	if !ok {
+		return nil, "", "", false
+	}
+
+	// project and configuration
+	project, config := name[position+3:], ""
+	if slash := strings.LastIndexByte(project, '/'); slash >= 0 {
+		project, config = project[:slash], project[slash+1:]
+	}
+
+	return remote, project, config, true
+}
+
+// fetch reads payloads from the remote service in alphabetical order of keys.
+func fetch(name string, remote Remote, project, config string) ([]Payload, error) {
+
+	// key/value pairs of the configuration
+	values, err := remote.Fetch(project, config)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %s", name, err)
+	}
+
+	// keys list
+	keys := make([]string, 0, len(values))
+
+	// iterating over keys
+	for key := range values {
+
+		// invalid key name
+		if !parser.ValidKey(key) {
+			return nil, fmt.Errorf("[%s] invalid key name '%s'", name, key)
+		}
+
+		// add key to list
+		keys = append(keys, key)
+	}
+
+	// sort keys
+	sort.Strings(keys)
+
+	// payload list
+	payloads := make([]Payload, 0, len(keys))
+
+	// iterating over keys
+	for _, key := range keys {
+
+		// add payload to list
+		payloads = append(payloads, Payload{
+			Export:  true,
+			Literal: true,
+			Key:     key,
+			Value:   values[key],
+			Raw:     values[key],
+		})
+	}
+
+	return payloads, nil
+}
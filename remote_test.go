@@ -0,0 +1,69 @@
+package envfile
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// TestLoadRemote tests merging of a remote configuration with local files.
+func TestLoadRemote(t *testing.T) {
+
+	// deferred removal of environment variables
+	defer os.Unsetenv("ENVFILE_REMOTE_HOST")
+	defer os.Unsetenv("ENVFILE_REMOTE_TOKEN")
+
+	// fake Doppler API
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		// token is missing
+		if token, _, _ := r.BasicAuth(); token != "dp.st.token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		// unknown configuration
+		if r.URL.Query().Get("project") != "api" || r.URL.Query().Get("config") != "prd" {
+			http.NotFound(w, r)
+			return
+		}
+
+		// secrets
+		w.Write([]byte(`{"ENVFILE_REMOTE_TOKEN": "{secret}", "ENVFILE_REMOTE_HOST": "remote"}`))
+	}))
+
+	// deferred server close
+	defer server.Close()
+
+	// loader with the remote service
+	loader := NewLoader(WithRemote("doppler", &Doppler{Token: "dp.st.token", Host: server.URL}))
+
+	// local file defining the host first
+	filename := createFile(t, "export ENVFILE_REMOTE_HOST = local\n")
+
+	// load local file and remote configuration
+	if err := loader.Load(filename, "doppler://api/prd"); err != nil {
+		t.Fatalf("error loading env files: %v", err)
+	}
+
+	// expected key/value pairs
+	pairs := map[string]string{
+		"ENVFILE_REMOTE_HOST":  "local",
+		"ENVFILE_REMOTE_TOKEN": "{secret}",
+	}
+
+	// iterating over expected key/value pairs
+	for key, value := range pairs {
+
+		// value from environment is different from expected
+		if actual := os.Getenv(key); actual != value {
+			t.Errorf("expected %s to be %s, got %s", key, value, actual)
+		}
+	}
+
+	// unknown configuration
+	if _, err := loader.Parse("doppler://api/dev"); err == nil {
+		t.Error("configuration doesn't exist but parse didn't return an error")
+	}
+}
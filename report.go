@@ -0,0 +1,212 @@
+package envfile
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// report is a diagnostic report on loaded configuration.
+type report struct {
+
+	// loaded files in order of loading
+	Files []string `json:"files"`
+
+	// processed keys in order of loading
+	Entries []Entry `json:"entries"`
+
+	// keys defined in more than one file
+	Duplicates []Duplicate `json:"duplicates"`
+
+	// differences between files and the environment
+	Drift []Drift `json:"drift"`
+}
+
+// reportCache is the effective definitions of the files of a loading, kept for reports
+// until the files change.
+type reportCache struct {
+
+	// result of the loading
+	result *Result
+
+	// states of the local files when they were parsed by file name
+	stamps map[string]fileStamp
+
+	// effective definitions of the files
+	definitions []definition
+}
+
+// Report renders the result of the last loading, provenance of keys and drift
+// against the environment as JSON, hiding values of keys holding secrets with the redactor.
+// Files are parsed for the drift once per loading and again only when a local file changes,
+// so serving the report doesn't run commands or call providers and remotes on every request.
+func (l *Loader) Report() ([]byte, error) {
+
+	// report with empty lists instead of nulls
+	rep := report{
+		Files:      []string{},
+		Entries:    []Entry{},
+		Duplicates: []Duplicate{},
+		Drift:      []Drift{},
+	}
+
+	// result of the last loading
+	if result := l.Result(); result != nil {
+
+		// set files
+		rep.Files = append(rep.Files, result.Files...)
+
+		// iterating over a list of entries
+		for _, entry := range result.Entries {
+
+			// hide secret value
+			entry.Value = l.Redact(entry.Key, entry.Value)
+
+			// add entry to report
+			rep.Entries = append(rep.Entries, entry)
+		}
+
+		// set duplicates without hashes of secrets
+		rep.Duplicates = append(rep.Duplicates, redactDuplicates(result.Duplicates, result.Entries)...)
+
+		// effective definitions of the files, parsed again only when they change
+		definitions, err := l.reportDefinitions(result)
+		if err != nil {
+			return nil, err
+		}
+
+		// compare files against the environment
+		drifts := l.drifts(definitions)
+
+		// iterating over a list of differences
+		for _, drift := range drifts {
+
+			// hide expected secret value
+			drift.Expected = l.Redact(drift.Key, drift.Expected)
+
+			// hide actual secret value
+			drift.Actual = l.Redact(drift.Key, drift.Actual)
+
+			// add difference to report
+			rep.Drift = append(rep.Drift, drift)
+		}
+	}
+
+	return json.MarshalIndent(rep, "", "  ")
+}
+
+// reportDefinitions returns the effective definitions of the files of the result, parsing them
+// only if they are not cached for the result or a local file or a file it includes has changed
+// since they were parsed.
+func (l *Loader) reportDefinitions(result *Result) ([]definition, error) {
+
+	// lock cache
+	l.mu.Lock()
+
+	// cached definitions and states of the loaded files
+	cache, loaded := l.reported, l.stamps
+
+	// unlock cache
+	l.mu.Unlock()
+
+	// definitions of the result are cached
+	if cache != nil && cache.result == result {
+
+		// changed status of the local files
+		changed := false
+
+		// iterating over states of the files
+		for filename, stamp := range cache.stamps {
+
+			// file is changed or can't be checked
+			if changed, _ = stamp.changed(filename); changed {
+				break
+			}
+		}
+
+		// files are unchanged
+		if !changed {
+			return cache.definitions, nil
+		}
+	}
+
+	// states of the local files, taken before parsing, so changes made meanwhile are detected
+	stamps := make(map[string]fileStamp)
+
+	// iterating over loaded files
+	for _, filename := range result.Files {
+
+		// file is local
+		if _, ok := loaded[filename]; !ok {
+			continue
+		}
+
+		// state of the file, errors are reported by parsing
+		if stamp, err := stampFile(filename); err == nil {
+			stamps[filename] = stamp
+		}
+	}
+
+	// effective definitions of the files and the files they include
+	definitions, included, err := l.effectiveDefinitions(result.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	// iterating over loaded files
+	for _, filename := range result.Files {
+
+		// file is local
+		if _, ok := loaded[filename]; !ok {
+			continue
+		}
+
+		// iterating over files included by the file, known only after parsing
+		for _, include := range included[filename] {
+
+			// state of the included file
+			if stamp, err := stampFile(include); err == nil {
+				stamps[include] = stamp
+			}
+		}
+	}
+
+	// lock cache
+	l.mu.Lock()
+
+	// deferred unlock of cache
+	defer l.mu.Unlock()
+
+	// update cache
+	l.reported = &reportCache{result: result, stamps: stamps, definitions: definitions}
+
+	return definitions, nil
+}
+
+// Handler returns an HTTP handler serving the report, e.g. as a /debug/config endpoint.
+func (l *Loader) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		// render report
+		data, err := l.Report()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		// set content type
+		w.Header().Set("Content-Type", "application/json")
+
+		// write report
+		w.Write(data)
+	})
+}
+
+// Report renders the report on the last package-level Load as JSON.
+func Report() ([]byte, error) {
+	return std.Report()
+}
+
+// Handler returns an HTTP handler serving the report on the last package-level Load.
+func Handler() http.Handler {
+	return std.Handler()
+}
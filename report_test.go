@@ -0,0 +1,221 @@
+package envfile
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestReport tests rendering of the diagnostic report.
+func TestReport(t *testing.T) {
+
+	// deferred removal of environment variables
+	defer os.Unsetenv("ENVFILE_REPORT_HOST")
+	defer os.Unsetenv("ENVFILE_REPORT_PASSWORD")
+
+	// file content
+	filename := createFile(t, "export ENVFILE_REPORT_HOST = localhost\nexport ENVFILE_REPORT_PASSWORD = qwerty\n")
+
+	// loader
+	loader := NewLoader()
+
+	// load file
+	if err := loader.Load(filename); err != nil {
+		t.Fatalf("error loading env file: %v", err)
+	}
+
+	// request the report
+	recorder := httptest.NewRecorder()
+	loader.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/config", nil))
+
+	// secret value is visible
+	if strings.Contains(recorder.Body.String(), "qwerty") {
+		t.Error("report contains secret value")
+	}
+
+	// decoded report
+	var rep report
+
+	// decode report
+	if err := json.Unmarshal(recorder.Body.Bytes(), &rep); err != nil {
+		t.Fatalf("error decoding report: %v", err)
+	}
+
+	// number of entries is different from expected
+	if len(rep.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(rep.Entries))
+	}
+
+	// value or status of entry is different from expected
+	if rep.Entries[0].Value != "localhost" || rep.Entries[0].Status != StatusSet {
+		t.Errorf("unexpected entry: %+v", rep.Entries[0])
+	}
+}
+
+// TestReportCached tests that files are parsed for the report once per loading and again only when they change.
+func TestReportCached(t *testing.T) {
+
+	// deferred removal of environment variables
+	defer os.Unsetenv("ENVFILE_REPORT_CACHED_PASSWORD")
+	defer os.Unsetenv("ENVFILE_REPORT_CACHED_HOST")
+
+	// file with a secret of the provider
+	filename := createFile(t, "export ENVFILE_REPORT_CACHED_PASSWORD = { rotating://db }\n")
+
+	// provider counting resolved secrets
+	provider := &rotatingProvider{}
+
+	// loader with the provider
+	loader := NewLoader(WithProvider(provider))
+
+	// load file
+	if err := loader.Load(filename); err != nil {
+		t.Fatalf("error loading env file: %v", err)
+	}
+
+	// render reports
+	for i := 0; i < 3; i++ {
+		if _, err := loader.Report(); err != nil {
+			t.Fatalf("error rendering report: %v", err)
+		}
+	}
+
+	// secret is resolved by loading and by the first report only
+	if count := provider.count.Load(); count != 2 {
+		t.Errorf("expected 2 resolved secrets, got %d", count)
+	}
+
+	// drift against the current environment is reported without parsing
+	os.Unsetenv("ENVFILE_REPORT_CACHED_PASSWORD")
+	data, err := loader.Report()
+	if err != nil {
+		t.Fatalf("error rendering report: %v", err)
+	}
+
+	// decoded report
+	var rep report
+
+	// decode report
+	if err := json.Unmarshal(data, &rep); err != nil {
+		t.Fatalf("error decoding report: %v", err)
+	}
+
+	// missing key is not reported
+	if len(rep.Drift) != 1 || rep.Drift[0].Kind != DriftMissing || provider.count.Load() != 2 {
+		t.Errorf("expected missing key without parsing, got %+v", rep.Drift)
+	}
+
+	// change the file
+	if err := ioutil.WriteFile(filename, []byte("export ENVFILE_REPORT_CACHED_HOST = localhost\n"), 0644); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+
+	// changed file is parsed again
+	if data, err = loader.Report(); err != nil {
+		t.Fatalf("error rendering report: %v", err)
+	}
+	if !strings.Contains(string(data), "ENVFILE_REPORT_CACHED_HOST") {
+		t.Errorf("expected drift of the changed file, got %s", data)
+	}
+}
+
+// TestReportDuplicateSecrets tests that hashes of values of duplicate keys holding secrets are not reported.
+func TestReportDuplicateSecrets(t *testing.T) {
+
+	// deferred removal of environment variables
+	defer os.Unsetenv("ENVFILE_REPORT_DUPLICATE_HOST")
+	defer os.Unsetenv("ENVFILE_REPORT_DUPLICATE_PASSWORD")
+
+	// files defining the same keys
+	first := createFile(t, "export ENVFILE_REPORT_DUPLICATE_HOST = localhost\nexport ENVFILE_REPORT_DUPLICATE_PASSWORD = 1234\n")
+	second := createFile(t, "overload ENVFILE_REPORT_DUPLICATE_HOST = db\noverload ENVFILE_REPORT_DUPLICATE_PASSWORD = 4321\n")
+
+	// loader
+	loader := NewLoader()
+
+	// load files
+	if err := loader.Load(first, second); err != nil {
+		t.Fatalf("error loading env files: %v", err)
+	}
+
+	// render report
+	data, err := loader.Report()
+	if err != nil {
+		t.Fatalf("error rendering report: %v", err)
+	}
+
+	// decoded report
+	var rep report
+
+	// decode report
+	if err := json.Unmarshal(data, &rep); err != nil {
+		t.Fatalf("error decoding report: %v", err)
+	}
+
+	// number of duplicates is different from expected
+	if len(rep.Duplicates) != 2 {
+		t.Fatalf("expected 2 duplicates, got %+v", rep.Duplicates)
+	}
+
+	// hash of a value that is not a secret is reported
+	if hash := rep.Duplicates[0].Definitions[0].Hash; hash != hashValue("localhost") {
+		t.Errorf("expected hash of the host, got %q", hash)
+	}
+
+	// hash of a secret is not reported
+	for _, def := range rep.Duplicates[1].Definitions {
+		if len(def.Hash) > 0 {
+			t.Errorf("expected no hash of the password, got %q", def.Hash)
+		}
+	}
+
+	// result of loading keeps the hashes
+	if hash := loader.Result().Duplicates[1].Definitions[0].Hash; hash != hashValue("1234") {
+		t.Errorf("expected hash of the password in the result, got %q", hash)
+	}
+}
+
+// TestReportCachedInclude tests that files are parsed for the report again when an included file changes.
+func TestReportCachedInclude(t *testing.T) {
+
+	// deferred removal of environment variables
+	defer os.Unsetenv("ENVFILE_REPORT_INCLUDE_HOST")
+	defer os.Unsetenv("ENVFILE_REPORT_INCLUDE_PORT")
+
+	// included file
+	included := createFile(t, "export ENVFILE_REPORT_INCLUDE_HOST = localhost\n")
+
+	// file including it
+	filename := createFile(t, "include "+included+"\n")
+
+	// loader
+	loader := NewLoader()
+
+	// load file
+	if err := loader.Load(filename); err != nil {
+		t.Fatalf("error loading env file: %v", err)
+	}
+
+	// render report with cached definitions
+	if _, err := loader.Report(); err != nil {
+		t.Fatalf("error rendering report: %v", err)
+	}
+
+	// change the included file
+	if err := ioutil.WriteFile(included, []byte("export ENVFILE_REPORT_INCLUDE_PORT = 8080\n"), 0644); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+
+	// definitions are parsed again
+	data, err := loader.Report()
+	if err != nil {
+		t.Fatalf("error rendering report: %v", err)
+	}
+	if !strings.Contains(string(data), "ENVFILE_REPORT_INCLUDE_PORT") {
+		t.Errorf("expected drift of the changed included file, got %s", data)
+	}
+}
@@ -0,0 +1,127 @@
+package envfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Status is an outcome of loading a key.
+type Status string
+
+const (
+
+	// StatusSet means the value was set to the environment.
+	StatusSet Status = "set"
+
+	// StatusKept means the exported key already existed in the environment and was kept.
+	StatusKept Status = "kept"
+
+	// StatusUnchanged means the overloaded key already had the same value.
+	StatusUnchanged Status = "unchanged"
+)
+
+// Entry is an exported or overloaded key processed by Load.
+type Entry struct {
+
+	// key
+	Key string `json:"key"`
+
+	// value from file
+	Value string `json:"value"`
+
+	// file name
+	File string `json:"file"`
+
+	// line number in file
+	Line int `json:"line"`
+
+	// outcome of loading
+	Status Status `json:"status"`
+
+	// shortest lifetime of the leased secrets in the value, zero if they don't expire
+	TTL time.Duration `json:"ttl,omitempty"`
+}
+
+// Result is the outcome of loading files.
+type Result struct {
+
+	// loaded files in order of loading
+	Files []string `json:"files"`
+
+	// processed keys in order of loading
+	Entries []Entry `json:"entries"`
+
+	// keys defined in more than one file in order of the first definition
+	Duplicates []Duplicate `json:"duplicates,omitempty"`
+
+	// statistics of parsing the files
+	Stats Stats `json:"stats"`
+}
+
+// Definition is a place where a key is defined.
+type Definition struct {
+
+	// file name
+	File string `json:"file"`
+
+	// line number in file
+	Line int `json:"line"`
+
+	// SHA-256 hash of the value, to compare values without revealing them,
+	// empty in reports and redacted results for keys holding secrets
+	Hash string `json:"hash,omitempty"`
+}
+
+// Duplicate is an exported or overloaded key defined in more than one file of the same loading.
+type Duplicate struct {
+
+	// key
+	Key string `json:"key"`
+
+	// definitions in order of loading
+	Definitions []Definition `json:"definitions"`
+}
+
+// hashValue returns the SHA-256 hash of the value as a hex string.
+func hashValue(value string) string {
+
+	// hash of the value
+	sum := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// duplicates returns keys defined in more than one file, the keys are in order of the first definition.
+func duplicates(keys []string, definitions map[string][]Definition) []Duplicate {
+
+	// duplicates list
+	var list []Duplicate
+
+	// iterating over keys
+	for _, key := range keys {
+
+		// definitions of the key
+		defs := definitions[key]
+
+		// iterating over definitions after the first one
+		for _, def := range defs[1:] {
+
+			// key is defined in another file
+			if def.File != defs[0].File {
+
+				// add duplicate to list
+				list = append(list, Duplicate{Key: key, Definitions: defs})
+
+				break
+			}
+		}
+	}
+
+	return list
+}
+
+// LastResult returns the result of the last package-level Load or nil if nothing was loaded.
+func LastResult() *Result {
+	return std.Result()
+}
@@ -0,0 +1,58 @@
+package envfile
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+)
+
+// Roundtrip checks that the file parsed into a document is serialized back byte-identical,
+// which holds for files with the same line ending everywhere and no trailing carriage returns.
+// The error names the first line that differs.
+func Roundtrip(filename string) error {
+
+	// read file
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	// parse document
+	doc, err := NewLoader().readDocument(filename, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+
+	// serialized document
+	serialized := doc.Bytes()
+
+	// document is byte-identical
+	if bytes.Equal(serialized, content) {
+		return nil
+	}
+
+	// original and serialized lines
+	original, lines := bytes.SplitAfter(content, []byte("\n")), bytes.SplitAfter(serialized, []byte("\n"))
+
+	// iterating over original lines
+	for i := range original {
+
+		// line differs
+		if i >= len(lines) || !bytes.Equal(original[i], lines[i]) {
+			return fmt.Errorf("[%s] line %d: serialized line differs from the original: %q, got %q", filename, i+1, original[i], lineAt(lines, i))
+		}
+	}
+
+	return fmt.Errorf("[%s] line %d: serialized document has extra lines", filename, len(original)+1)
+}
+
+// lineAt returns the line at the position or an empty one if there is no such line.
+func lineAt(lines [][]byte, i int) []byte {
+
+	// line is missing
+	if i >= len(lines) {
+		return nil
+	}
+
+	return lines[i]
+}
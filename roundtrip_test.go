@@ -0,0 +1,83 @@
+package envfile
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRoundtrip tests serialization of documents back to their files.
+func TestRoundtrip(t *testing.T) {
+
+	// well-formed contents
+	contents := []string{
+		"",
+		"KEY = value\n",
+		"# comment\r\n\r\n  export KEY = { OTHER }  \r\nOTHER = value\r\n",
+		"KEY = value\nlast line without line ending",
+		"invalid line\n\tKEY=value\t\n",
+	}
+
+	// iterating over contents
+	for _, content := range contents {
+
+		// content is not serialized back
+		if err := Roundtrip(createFile(t, content)); err != nil {
+			t.Errorf("content %q: %v", content, err)
+		}
+	}
+
+	// corpus files
+	filenames, err := filepath.Glob(filepath.Join("envfiletest", "testdata", "*", "*.envfile"))
+	if err != nil {
+		t.Fatalf("error listing corpus files: %v", err)
+	}
+
+	// iterating over corpus files
+	for _, filename := range filenames {
+
+		// file is not serialized back
+		if err := Roundtrip(filename); err != nil {
+			t.Error(err)
+		}
+	}
+
+	// mixed line endings are not preserved
+	err = Roundtrip(createFile(t, "KEY_1 = value\r\nKEY_2 = value\nKEY_3 = value\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2:") {
+		t.Errorf("expected error on line 2, got %v", err)
+	}
+}
+
+// TestRoundtripEdit tests that editing the document keeps untouched lines byte-identical.
+func TestRoundtripEdit(t *testing.T) {
+
+	// file content with odd spacing and CRLF line endings
+	content := "# header  \r\n\r\nKEY_1   =  value\t\r\nexport  KEY_2 ={KEY_1}\r\n   \r\nKEY_3= last\r\n"
+
+	// file name
+	filename := createFile(t, content)
+
+	// parse document
+	doc, err := ParseDocument(filename)
+	if err != nil {
+		t.Fatalf("error parsing document: %v", err)
+	}
+
+	// replace the value of the first key
+	if _, err := doc.ApplyEdit(Range{Start: Position{Line: 3, Column: 11}, End: Position{Line: 3, Column: 16}}, "other"); err != nil {
+		t.Fatalf("error applying edit: %v", err)
+	}
+
+	// only the edited part is changed
+	if expected := strings.Replace(content, "value\t", "other\t", 1); !bytes.Equal(doc.Bytes(), []byte(expected)) {
+		t.Errorf("expected %q, got %q", expected, doc.Bytes())
+	}
+
+	// original file is unchanged
+	if original, err := ioutil.ReadFile(filename); err != nil || string(original) != content {
+		t.Errorf("file is changed: %q, %v", original, err)
+	}
+}
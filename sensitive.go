@@ -0,0 +1,132 @@
+package envfile
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// sensitiveWords are parts of key names that hold secrets.
+var sensitiveWords = []string{
+	"SECRET",
+	"PASSWORD",
+	"PASSWD",
+	"PASS",
+	"TOKEN",
+	"PRIVATE",
+	"CREDENTIAL",
+	"CREDENTIALS",
+	"API_KEY",
+	"APIKEY",
+	"ACCESS_KEY",
+}
+
+// isSensitive reports whether the key name looks like it holds a secret.
+func isSensitive(key string) bool {
+
+	// key name in upper case
+	key = strings.ToUpper(key)
+
+	// iterating over a list of sensitive words
+	for _, word := range sensitiveWords {
+
+		// iterating over the parts of the key name
+		for _, part := range strings.Split(key, "_") {
+
+			// part of the key name is a sensitive word
+			if part == word {
+				return true
+			}
+		}
+
+		// multi-part sensitive word is a part of the key name
+		if strings.Contains(word, "_") && strings.Contains(key, word) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redacted is written in place of values of keys holding secrets.
+const redacted = "******"
+
+const (
+
+	// secretLength is the minimum length of values that look like random secrets.
+	secretLength = 20
+
+	// secretEntropy is the minimum Shannon entropy in bits per character of values
+	// that look like random secrets, e.g. tokens and keys in hex or base64.
+	secretEntropy = 3.5
+)
+
+// IsSecret reports whether the key holds a secret, by default judging by the key name and
+// by the value: long values without spaces and with high entropy look like tokens.
+// It can be replaced to tune the heuristic, e.g. to exclude known keys.
+var IsSecret = func(key, value string) bool {
+	return isSensitive(key) || looksRandom(value)
+}
+
+// looksRandom reports whether the value looks like a random secret: it is long, has no spaces,
+// mixes letters and digits, is not a URL and has high entropy.
+func looksRandom(value string) bool {
+
+	// value is short or a URL
+	if len(value) < secretLength || strings.Contains(value, "://") {
+		return false
+	}
+
+	// value has letters and digits
+	var letters, digits bool
+
+	// iterating over characters
+	for _, c := range value {
+
+		// value with spaces is a text
+		if unicode.IsSpace(c) {
+			return false
+		}
+
+		// update status
+		letters = letters || unicode.IsLetter(c)
+		digits = digits || unicode.IsDigit(c)
+	}
+
+	return letters && digits && entropy(value) >= secretEntropy
+}
+
+// entropy returns the Shannon entropy of the value in bits per character.
+func entropy(value string) float64 {
+
+	// number of occurrences by character
+	counts := make(map[rune]int)
+
+	// number of characters
+	var total int
+
+	// iterating over characters
+	for _, c := range value {
+
+		// increase number of occurrences
+		counts[c]++
+
+		// increase number of characters
+		total++
+	}
+
+	// entropy
+	var result float64
+
+	// iterating over occurrences
+	for _, count := range counts {
+
+		// probability of the character
+		p := float64(count) / float64(total)
+
+		// add information of the character
+		result -= p * math.Log2(p)
+	}
+
+	return result
+}
@@ -0,0 +1,194 @@
+package envfile
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// WithSignatureVerification verifies detached signatures of files before they are parsed:
+// a minisign signature in FILE.minisig or a base64 ed25519 signature of the content in FILE.sig.
+// Files without a valid signature of the key are rejected.
+func WithSignatureVerification(key ed25519.PublicKey) Option {
+	return func(l *Loader) {
+
+		// set public key
+		l.publicKey = key
+	}
+}
+
+// MinisignKey is a minisign public key with the identifier written in the signatures it verifies.
+type MinisignKey struct {
+
+	// identifier of the key
+	ID [8]byte
+
+	// ed25519 key
+	Key ed25519.PublicKey
+}
+
+// String returns the identifier of the key as minisign prints it.
+func (k *MinisignKey) String() string {
+	return minisignKeyID(k.ID[:])
+}
+
+// WithMinisignVerification verifies detached signatures like WithSignatureVerification,
+// minisign signatures made with another key are rejected with the identifiers of both keys.
+func WithMinisignVerification(key *MinisignKey) Option {
+	return func(l *Loader) {
+
+		// set public key and its identifier
+		l.publicKey = key.Key
+		l.keyID = key.ID[:]
+	}
+}
+
+// ParseMinisignPublicKey parses the minisign public key, the content of the .pub file or its base64 line.
+func ParseMinisignPublicKey(text string) (ed25519.PublicKey, error) {
+
+	// parse key with its identifier
+	key, err := ParseMinisignKey(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return key.Key, nil
+}
+
+// ParseMinisignKey parses the minisign public key with its identifier, the content of the .pub file or its base64 line.
+func ParseMinisignKey(text string) (*MinisignKey, error) {
+
+	// lines of the key
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+
+	// decode the last line, the first one is an untrusted comment
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[len(lines)-1]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid minisign public key: %s", err)
+	}
+
+	// algorithm, key identifier and key
+	if len(data) != 2+8+ed25519.PublicKeySize || string(data[:2]) != "Ed" {
+		return nil, errors.New("invalid minisign public key")
+	}
+
+	// key with its identifier
+	key := &MinisignKey{Key: ed25519.PublicKey(data[10:])}
+	copy(key.ID[:], data[2:10])
+
+	return key, nil
+}
+
+// minisignKeyID returns the key identifier as minisign prints it, a little-endian number in hexadecimal.
+func minisignKeyID(id []byte) string {
+	return fmt.Sprintf("%016X", binary.LittleEndian.Uint64(id))
+}
+
+// verifySignature checks the detached signature of the content of the file, the content is read once
+// by the caller and parsed from the same bytes, so a file replaced after the check is never parsed.
+// Signatures are read by the function, from the disk or from the file system the file comes from.
+func (l *Loader) verifySignature(filename string, content []byte, readFile func(name string) ([]byte, error)) error {
+
+	// minisign signature
+	if signature, err := readFile(filename + ".minisig"); err == nil {
+
+		// verify minisign signature
+		if err := verifyMinisign(l.publicKey, l.keyID, content, signature); err != nil {
+			return fmt.Errorf("[%s] %s", filename, err)
+		}
+
+		return nil
+
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// ed25519 signature
+	signature, err := readFile(filename + ".sig")
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("[%s] signature is missing", filename)
+	}
+	if err != nil {
+		return err
+	}
+
+	// decode signature
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("[%s] can't decode signature: %s", filename, err)
+	}
+
+	// signature does not match
+	if !ed25519.Verify(l.publicKey, content, data) {
+		return fmt.Errorf("[%s] signature is invalid", filename)
+	}
+
+	return nil
+}
+
+// verifyMinisign checks the minisign signature of the content: the signature of the content,
+// prehashed with BLAKE2b-512 for the ED algorithm, and the global signature of the trusted comment.
+// The header is checked first, and a signature of another key is reported with both identifiers
+// if the identifier of the key is known.
+func verifyMinisign(key ed25519.PublicKey, keyID []byte, content, signature []byte) error {
+
+	// lines of the signature: untrusted comment, signature, trusted comment, global signature
+	lines := strings.Split(strings.TrimSpace(string(bytes.Replace(signature, []byte("\r\n"), []byte("\n"), -1))), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "untrusted comment: ") || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return errors.New("invalid minisign signature")
+	}
+
+	// decode signature
+	data, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(data) != 2+8+ed25519.SignatureSize {
+		return errors.New("invalid minisign signature")
+	}
+
+	// decode global signature
+	global, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || len(global) != ed25519.SignatureSize {
+		return errors.New("invalid minisign global signature")
+	}
+
+	// signature is made with another key
+	if keyID != nil && !bytes.Equal(data[2:10], keyID) {
+		return fmt.Errorf("signed with key %s, expected %s", minisignKeyID(data[2:10]), minisignKeyID(keyID))
+	}
+
+	// signed message
+	message := content
+
+	switch string(data[:2]) {
+
+	// prehashed content
+	case "ED":
+		sum := blake2b.Sum512(content)
+		message = sum[:]
+
+	// legacy signature of the content
+	case "Ed":
+
+	// any
+	default:
+		return fmt.Errorf("unsupported minisign algorithm '%s'", data[:2])
+	}
+
+	// signature does not match
+	if !ed25519.Verify(key, message, data[10:]) {
+		return errors.New("signature is invalid")
+	}
+
+	// trusted comment is signed along with the signature
+	if !ed25519.Verify(key, append(data[10:], strings.TrimPrefix(lines[2], "trusted comment: ")...), global) {
+		return errors.New("trusted comment signature is invalid")
+	}
+
+	return nil
+}
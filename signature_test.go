@@ -0,0 +1,254 @@
+package envfile
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// minisign returns the minisign signature of the content with the ED algorithm and the key identifier.
+func minisign(key ed25519.PrivateKey, id string, content []byte, comment string) string {
+
+	// prehashed content
+	sum := blake2b.Sum512(content)
+
+	// algorithm, key identifier and signature
+	data := append([]byte("ED"+id), ed25519.Sign(key, sum[:])...)
+
+	// global signature of the signature and the trusted comment
+	global := ed25519.Sign(key, append(data[10:], comment...))
+
+	return "untrusted comment: signature from minisign secret key\n" +
+		base64.StdEncoding.EncodeToString(data) + "\n" +
+		"trusted comment: " + comment + "\n" +
+		base64.StdEncoding.EncodeToString(global) + "\n"
+}
+
+// TestSignatureVerification tests verification of detached signatures before parsing.
+func TestSignatureVerification(t *testing.T) {
+
+	// key pair
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+
+	// minisign public key
+	key, err := ParseMinisignKey("untrusted comment: minisign public key\n" +
+		base64.StdEncoding.EncodeToString(append([]byte("Ed12345678"), public...)) + "\n")
+	if err != nil || !key.Key.Equal(public) || string(key.ID[:]) != "12345678" {
+		t.Fatalf("unexpected public key: %v", err)
+	}
+
+	// file content
+	content := "KEY = value\n"
+
+	// signed files
+	minisigned := createFile(t, content)
+	signed := createFile(t, content)
+
+	// deferred removal of signatures
+	defer os.Remove(minisigned + ".minisig")
+	defer os.Remove(signed + ".sig")
+
+	// write minisign signature
+	if err := ioutil.WriteFile(minisigned+".minisig", []byte(minisign(private, "12345678", []byte(content), "timestamp:1")), 0644); err != nil {
+		t.Fatalf("error writing signature: %v", err)
+	}
+
+	// write ed25519 signature
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(private, []byte(content)))
+	if err := ioutil.WriteFile(signed+".sig", []byte(signature+"\n"), 0644); err != nil {
+		t.Fatalf("error writing signature: %v", err)
+	}
+
+	// loader verifying signatures
+	loader := NewLoader(WithMinisignVerification(key))
+
+	// iterating over signed files
+	for _, filename := range []string{minisigned, signed} {
+
+		// parse signed file
+		if _, err := loader.Parse(filename); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		// change content
+		if err := ioutil.WriteFile(filename, []byte("KEY = other\n"), 0644); err != nil {
+			t.Fatalf("error writing file: %v", err)
+		}
+
+		// changed file is parsed
+		if _, err := loader.Parse(filename); err == nil || err.Error() != "["+filename+"] signature is invalid" {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	// unsigned file is parsed
+	if _, err := loader.Parse(createFile(t, content)); err == nil {
+		t.Error("file is not signed but parse didn't return an error")
+	}
+}
+
+// TestSignatureKeyID tests that minisign signatures of another key and malformed signatures are rejected before verification.
+func TestSignatureKeyID(t *testing.T) {
+
+	// key pair
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+
+	// minisign public key
+	key, err := ParseMinisignKey(base64.StdEncoding.EncodeToString(append([]byte("Ed12345678"), public...)))
+	if err != nil {
+		t.Fatalf("error parsing public key: %v", err)
+	}
+
+	// file content
+	content := "KEY = value\n"
+
+	// valid signature
+	valid := minisign(private, "12345678", []byte(content), "timestamp:1")
+
+	// expected errors by signature
+	signatures := map[string]string{
+		minisign(private, "87654321", []byte(content), "timestamp:1"):   "signed with key 3132333435363738, expected 3837363534333231",
+		strings.Replace(valid, "untrusted comment", "comment", 1):       "invalid minisign signature",
+		strings.Join(strings.Split(valid, "\n")[:3], "\n"):              "invalid minisign signature",
+		strings.Replace(valid, "\ntrusted comment: ", "\ncomment: ", 1): "invalid minisign signature",
+	}
+
+	// iterating over signatures
+	for signature, message := range signatures {
+
+		// verify signature
+		err := verifyMinisign(key.Key, key.ID[:], []byte(content), []byte(signature))
+
+		// error is different from expected
+		if err == nil || err.Error() != message {
+			t.Errorf("expected error %q, got %v", message, err)
+		}
+	}
+
+	// signature of the key is rejected by identifier that isn't known
+	if err := verifyMinisign(key.Key, nil, []byte(content), []byte(minisign(private, "87654321", []byte(content), "timestamp:1"))); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// identifier as minisign prints it
+	if key.String() != "3837363534333231" {
+		t.Errorf("unexpected key identifier %s", key)
+	}
+}
+
+// TestSignatureVerifiedContent tests that the verified content is parsed, even if the file is replaced after the check.
+func TestSignatureVerifiedContent(t *testing.T) {
+
+	// key pair
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+
+	// signed file
+	content := "KEY = value\n"
+	signed := createFile(t, content)
+
+	// deferred removal of signature
+	defer os.Remove(signed + ".sig")
+
+	// write ed25519 signature
+	if err := ioutil.WriteFile(signed+".sig", []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(private, []byte(content)))), 0644); err != nil {
+		t.Fatalf("error writing signature: %v", err)
+	}
+
+	// open file after verifying its signature
+	file, err := NewLoader(WithSignatureVerification(public)).openFile(signed)
+	if err != nil {
+		t.Fatalf("error opening signed file: %v", err)
+	}
+
+	// deferred file close
+	defer file.Close()
+
+	// file is replaced after the check
+	if err := ioutil.WriteFile(signed, []byte("KEY = forged\n"), 0644); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+
+	// read content
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		t.Fatalf("error reading file: %v", err)
+	}
+
+	// content is different from the verified one
+	if string(data) != content {
+		t.Errorf("expected verified content %q, got %q", content, data)
+	}
+}
+
+// TestSignatureVerificationFS tests verification of signatures of files and included files of a file system.
+func TestSignatureVerificationFS(t *testing.T) {
+
+	// key pair
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+
+	// sign returns the ed25519 signature file of the content
+	sign := func(content string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(private, []byte(content))))}
+	}
+
+	// file system with signed files
+	fsys := fstest.MapFS{
+		"app.envfile":          {Data: []byte("include base.envfile\nKEY = value\n")},
+		"app.envfile.sig":      sign("include base.envfile\nKEY = value\n"),
+		"base.envfile":         {Data: []byte("BASE = value\n")},
+		"base.envfile.sig":     sign("BASE = value\n"),
+		"tampered.envfile":     {Data: []byte("KEY = forged\n")},
+		"tampered.envfile.sig": sign("KEY = value\n"),
+		"unsigned.envfile":     {Data: []byte("KEY = value\n")},
+		"included.envfile":     {Data: []byte("include unsigned.envfile\n")},
+		"included.envfile.sig": sign("include unsigned.envfile\n"),
+	}
+
+	// loader verifying signatures
+	loader := NewLoader(WithSignatureVerification(public))
+
+	// parse signed file
+	if _, err := loader.ParseFS(fsys, "app.envfile"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// files with invalid or missing signatures
+	files := map[string]string{
+		"tampered.envfile": "[tampered.envfile] signature is invalid",
+		"unsigned.envfile": "[unsigned.envfile] signature is missing",
+		"included.envfile": "[unsigned.envfile] signature is missing",
+	}
+
+	// iterating over files
+	for name, message := range files {
+
+		// parse file
+		if _, err := loader.ParseFS(fsys, name); err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("expected error '%s' for %s, got %v", message, name, err)
+		}
+	}
+
+	// load file
+	if err := loader.LoadFS(fsys, "unsigned.envfile"); err == nil {
+		t.Error("file is not signed but load didn't return an error")
+	}
+}
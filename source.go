@@ -0,0 +1,84 @@
+package envfile
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// WithValueSources enables value prefixes: base64:SGVsbG8= is decoded and
+// file:./secrets/token is replaced by the contents of the file, relative to the env file.
+// Only prefixes written in the file are resolved: raw and single-quoted values, and values
+// of references that look like a prefixed value, stay as they are.
+func WithValueSources(enabled bool) Option {
+	return func(l *Loader) {
+
+		// set value sources status
+		l.sources = enabled
+	}
+}
+
+// resolveSources replaces values having a source prefix written in the file with the decoded or read data.
+func resolveSources(filename string, payloads []Payload) ([]Payload, error) {
+
+	// iterating over a list of payloads
+	for i, payload := range payloads {
+
+		// raw value is taken as it is written
+		if payload.Literal {
+			continue
+		}
+
+		switch {
+
+		// base64 encoded value
+		case writtenPrefix(payload, "base64:"):
+
+			// decode value
+			data, err := base64.StdEncoding.DecodeString(payload.Value[7:])
+			if err != nil {
+				return nil, fmt.Errorf("[%s] line %d: can't decode base64 value of key '%s': %s",
+					parser.PayloadFile(payload, filename), payload.Line, payload.Key, err)
+			}
+
+			// update value
+			payload.Value = string(data)
+
+		// contents of file
+		case writtenPrefix(payload, "file:"):
+
+			// path to file
+			path := payload.Value[5:]
+
+			// path is relative to the env file the key is written in
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(filepath.Dir(parser.PayloadFile(payload, filename)), path)
+			}
+
+			// read file
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("[%s] line %d: can't read value of key '%s': %s",
+					parser.PayloadFile(payload, filename), payload.Line, payload.Key, err)
+			}
+
+			// update value
+			payload.Value = string(data)
+		}
+
+		// update payload
+		payloads[i] = payload
+	}
+
+	return payloads, nil
+}
+
+// writtenPrefix reports whether the value has the source prefix written in the file,
+// rather than taken from the value of a reference.
+func writtenPrefix(payload Payload, prefix string) bool {
+	return strings.HasPrefix(payload.Raw, prefix) && strings.HasPrefix(payload.Value, prefix)
+}
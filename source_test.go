@@ -0,0 +1,83 @@
+package envfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestParseValueSources tests base64 and file value prefixes.
+func TestParseValueSources(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "")
+
+	// secret file next to the env file
+	secret := filepath.Join(filepath.Dir(filename), "envfile_test_token")
+
+	// write secret file
+	if err := ioutil.WriteFile(secret, []byte("token"), 0600); err != nil {
+		t.Fatalf("error writing secret file: %v", err)
+	}
+
+	// deferred removal of the secret file
+	defer os.Remove(secret)
+
+	// write env file
+	if err := ioutil.WriteFile(filename, []byte("KEY_1 = base64:SGVsbG8=\nKEY_2 = file:envfile_test_token\n"), 0600); err != nil {
+		t.Fatalf("error writing env file: %v", err)
+	}
+
+	// parse file with value prefixes
+	payloads, err := NewLoader(WithValueSources(true)).Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// decoded value is different from expected
+	if payloads[0].Value != "Hello" {
+		t.Errorf("expected KEY_1 to be Hello, got %s", payloads[0].Value)
+	}
+
+	// file contents are different from expected
+	if payloads[1].Value != "token" {
+		t.Errorf("expected KEY_2 to be token, got %s", payloads[1].Value)
+	}
+
+	// parse file without value prefixes
+	payloads, err = Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// value is changed
+	if payloads[0].Value != "base64:SGVsbG8=" {
+		t.Errorf("expected KEY_1 to be left as is, got %s", payloads[0].Value)
+	}
+}
+
+// TestParseValueSourcesWritten tests that only prefixes written in the file are resolved.
+func TestParseValueSourcesWritten(t *testing.T) {
+
+	// file with a raw value, a single-quoted value and references to prefixed values
+	filename := createFile(t, "raw RAW = file:/etc/hostname\nQUOTED = 'base64:SGVsbG8='\nPATH_1 = { SOURCES_PATH }\nPATH_2 = { RAW }\n")
+
+	// parse file with value prefixes, the environment holds a prefixed value
+	payloads, err := NewLoader(WithValueSources(true), WithEnvironment(MapEnvironment{"SOURCES_PATH": "file:/etc/hostname"})).Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// expected values
+	expected := []string{"file:/etc/hostname", "base64:SGVsbG8=", "file:/etc/hostname", "file:/etc/hostname"}
+
+	// iterating over payloads
+	for i, payload := range payloads {
+
+		// value is resolved
+		if payload.Value != expected[i] {
+			t.Errorf("expected %s to be left as %s, got %q", payload.Key, expected[i], payload.Value)
+		}
+	}
+}
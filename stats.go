@@ -0,0 +1,78 @@
+package envfile
+
+import "time"
+
+// Stats are statistics of parsing the files of a loading, so growth of configuration complexity
+// can be tracked over time, e.g. exported as metrics.
+type Stats struct {
+
+	// lines read
+	Lines int `json:"lines"`
+
+	// keys parsed, local ones included
+	Keys int `json:"keys"`
+
+	// files included by include and source directives
+	Includes int `json:"includes"`
+
+	// references replaced with values in the default dialect
+	Expansions int `json:"expansions"`
+
+	// secrets resolved by providers
+	ProviderCalls int `json:"providerCalls"`
+
+	// time of parsing the files and setting the keys
+	Duration time.Duration `json:"duration"`
+}
+
+// add adds the statistics to the statistics.
+func (s *Stats) add(other Stats) {
+	s.Lines += other.Lines
+	s.Keys += other.Keys
+	s.Includes += other.Includes
+	s.Expansions += other.Expansions
+	s.ProviderCalls += other.ProviderCalls
+	s.Duration += other.Duration
+}
+
+// count adds the statistics to the ones recorded for the file.
+func (l *Loader) count(filename string, stats Stats) {
+
+	// lock statistics
+	l.mu.Lock()
+
+	// deferred unlock of statistics
+	defer l.mu.Unlock()
+
+	// statistics are not set yet
+	if l.stats == nil {
+		l.stats = make(map[string]Stats)
+	}
+
+	// current statistics of the file
+	current := l.stats[filename]
+
+	// add statistics
+	current.add(stats)
+
+	// update statistics
+	l.stats[filename] = current
+}
+
+// takeStats returns and forgets the statistics recorded for the file.
+func (l *Loader) takeStats(filename string) Stats {
+
+	// lock statistics
+	l.mu.Lock()
+
+	// deferred unlock of statistics
+	defer l.mu.Unlock()
+
+	// statistics of the file
+	stats := l.stats[filename]
+
+	// forget statistics
+	delete(l.stats, filename)
+
+	return stats
+}
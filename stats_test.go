@@ -0,0 +1,82 @@
+package envfile
+
+import (
+	"os"
+	"testing"
+)
+
+// TestStats tests statistics of parsing in the result of loading.
+func TestStats(t *testing.T) {
+
+	// deferred removal of environment variables
+	defer os.Unsetenv("STATS_HOST")
+	defer os.Unsetenv("STATS_URL")
+	defer os.Unsetenv("STATS_PASSWORD")
+	defer os.Unsetenv("STATS_PORT")
+
+	// files with references and a secret
+	first := createFile(t, "# database\nexport STATS_HOST = localhost\nPORT = 5432\n\nexport STATS_URL = postgres://{ STATS_HOST }:{ PORT }\n")
+	second := createFile(t, "export STATS_PASSWORD = { rotating://db }\nexport STATS_PORT = { PORT:-1 }\n")
+
+	// loader with a provider
+	loader := NewLoader(WithProvider(&rotatingProvider{}))
+
+	// forgotten statistics of earlier parsing
+	if _, err := loader.Parse(first); err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// load files
+	if err := loader.Load(first, second); err != nil {
+		t.Fatalf("error loading env files: %v", err)
+	}
+
+	// statistics of the files
+	stats := loader.Result().Stats
+
+	// expected statistics
+	expected := Stats{Lines: 7, Keys: 5, Expansions: 4, ProviderCalls: 1}
+
+	// duration is measured
+	if stats.Duration <= 0 {
+		t.Errorf("expected duration to be measured, got %s", stats.Duration)
+	}
+
+	// statistics are different from expected
+	if stats.Duration = 0; stats != expected {
+		t.Errorf("expected %+v, got %+v", expected, stats)
+	}
+}
+
+// TestStatsKept tests that statistics are kept by the redacted and the refreshed result.
+func TestStatsKept(t *testing.T) {
+
+	// deferred removal of environment variables
+	defer os.Unsetenv("STATS_KEPT_PASSWORD")
+
+	// loader with the provider of expiring secrets
+	loader := NewLoader(WithProvider(&rotatingProvider{}))
+
+	// load file with a leased secret
+	if err := loader.Load(createFile(t, "export STATS_KEPT_PASSWORD = { rotating://db }\n")); err != nil {
+		t.Fatalf("error loading env file: %v", err)
+	}
+
+	// statistics of loading
+	stats := loader.Result().Stats
+
+	// statistics of the redacted result are different
+	if redacted := loader.Result().Redacted(nil).Stats; redacted != stats {
+		t.Errorf("expected %+v, got %+v", stats, redacted)
+	}
+
+	// refresh leased keys
+	if err := loader.refresh(loader.Result(), nil); err != nil {
+		t.Fatalf("error refreshing keys: %v", err)
+	}
+
+	// statistics of the refreshed result are different
+	if refreshed := loader.Result().Stats; refreshed != stats {
+		t.Errorf("expected %+v, got %+v", stats, refreshed)
+	}
+}
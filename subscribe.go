@@ -0,0 +1,218 @@
+package envfile
+
+import (
+	"slices"
+	"sync"
+)
+
+// Change is a change of a key between two values published by LiveValues.
+type Change struct {
+
+	// key
+	Key string `json:"key"`
+
+	// previous value
+	Previous string `json:"previous"`
+
+	// key existed before the change
+	Existed bool `json:"existed"`
+
+	// new value
+	Value string `json:"value"`
+
+	// key exists after the change
+	Exists bool `json:"exists"`
+
+	// place the new value comes from
+	Source Source `json:"source"`
+}
+
+// subscriptionBuffer is the number of changes waiting for the receiver of a subscription to every key.
+const subscriptionBuffer = 256
+
+// subscription is a receiver of changes of keys.
+type subscription struct {
+
+	// keys of interest, all keys of the files if empty
+	keys []string
+
+	// changes of the keys
+	changes chan Change
+
+	// subscription is cancelled
+	closed bool
+
+	// sending and closing synchronization
+	mu sync.Mutex
+}
+
+// Subscribe returns a channel receiving a change for every key that Reload or Store changes,
+// added and removed keys included; without keys every key of the files is watched. Reload never
+// waits for the receiver: changes of a key the receiver has not taken yet are merged into one, and
+// the oldest changes are dropped when the channel is full. Unsubscribe stops the delivery and closes the channel.
+func (v *LiveValues) Subscribe(keys ...string) <-chan Change {
+
+	// subscription
+	sub := &subscription{keys: keys, changes: make(chan Change, len(keys)+1)}
+
+	// changes of every key of the files
+	if len(keys) == 0 {
+		sub.changes = make(chan Change, subscriptionBuffer)
+	}
+
+	// lock subscriptions
+	v.mu.Lock()
+
+	// deferred unlock of subscriptions
+	defer v.mu.Unlock()
+
+	// add subscription
+	v.subscriptions = append(v.subscriptions, sub)
+
+	return sub.changes
+}
+
+// Unsubscribe stops the delivery of changes to the channel returned by Subscribe and closes it.
+func (v *LiveValues) Unsubscribe(changes <-chan Change) {
+
+	// lock subscriptions
+	v.mu.Lock()
+
+	// index of the subscription
+	i := slices.IndexFunc(v.subscriptions, func(sub *subscription) bool { return sub.changes == changes })
+
+	// channel is not subscribed
+	if i < 0 {
+		v.mu.Unlock()
+		return
+	}
+
+	// subscription
+	sub := v.subscriptions[i]
+
+	// remove subscription
+	v.subscriptions = slices.Delete(v.subscriptions, i, i+1)
+
+	// unlock subscriptions
+	v.mu.Unlock()
+
+	// lock sending, a delivery in progress never blocks and is finished first
+	sub.mu.Lock()
+
+	// deferred unlock of sending
+	defer sub.mu.Unlock()
+
+	// close channel
+	sub.closed = true
+	close(sub.changes)
+}
+
+// notify delivers the changes between the previous and the new values to the subscriptions.
+func (v *LiveValues) notify(previous, values Values) {
+
+	// lock subscriptions
+	v.mu.Lock()
+
+	// subscriptions to notify
+	subscriptions := slices.Clone(v.subscriptions)
+
+	// unlock subscriptions
+	v.mu.Unlock()
+
+	// iterating over subscriptions
+	for _, sub := range subscriptions {
+
+		// keys of interest
+		keys := sub.keys
+
+		// keys of the files, new ones first
+		if len(keys) == 0 {
+
+			// iterating over values
+			for _, set := range []Values{values, previous} {
+
+				// iterating over keys
+				for key := range set.All() {
+
+					// add key once
+					if !slices.Contains(keys, key) {
+						keys = append(keys, key)
+					}
+				}
+			}
+		}
+
+		// iterating over keys
+		for _, key := range keys {
+
+			// states of the key
+			before, existed := previous.Lookup(key)
+			after, source, exists := values.GetWithSource(key)
+
+			// key is changed
+			if before != after || existed != exists {
+				sub.send(Change{Key: key, Previous: before, Existed: existed, Value: after, Exists: exists, Source: source})
+			}
+		}
+	}
+}
+
+// send delivers the change unless the subscription is cancelled, without ever blocking the reload:
+// a change of a key that the receiver has not taken yet is merged with the new one, and the oldest
+// changes are dropped when the channel is still full.
+func (s *subscription) send(change Change) {
+
+	// lock sending
+	s.mu.Lock()
+
+	// deferred unlock of sending
+	defer s.mu.Unlock()
+
+	// subscription is cancelled
+	if s.closed {
+		return
+	}
+
+	// changes not received yet
+	var pending []Change
+
+	// take changes from the channel
+	for taken := false; !taken; {
+		select {
+		case current := <-s.changes:
+			pending = append(pending, current)
+		default:
+			taken = true
+		}
+	}
+
+	// position of the pending change of the same key
+	i := slices.IndexFunc(pending, func(current Change) bool { return current.Key == change.Key })
+
+	// change of a key without pending changes
+	if i < 0 {
+		pending = append(pending, change)
+	} else {
+
+		// merged change, from the state before the pending change to the new one
+		merged := change
+		merged.Previous, merged.Existed = pending[i].Previous, pending[i].Existed
+
+		// key is changed back by the changes together
+		if merged.Previous == merged.Value && merged.Existed == merged.Exists {
+			pending = slices.Delete(pending, i, i+1)
+		} else {
+			pending[i] = merged
+		}
+	}
+
+	// drop the oldest changes that don't fit
+	if len(pending) > cap(s.changes) {
+		pending = pending[len(pending)-cap(s.changes):]
+	}
+
+	// deliver changes
+	for _, current := range pending {
+		s.changes <- current
+	}
+}
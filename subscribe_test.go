@@ -0,0 +1,123 @@
+package envfile
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+// TestSubscribe tests delivery of changes of keys.
+func TestSubscribe(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "export SUBSCRIBE_HOST = first\nexport SUBSCRIBE_PORT = 1\nexport SUBSCRIBE_OLD = old\n")
+
+	// live values
+	live, err := ParseLiveValues(filename)
+	if err != nil {
+		t.Fatalf("error parsing live values: %v", err)
+	}
+
+	// changes of one key and of all keys
+	host := live.Subscribe("SUBSCRIBE_HOST")
+	all := live.Subscribe()
+
+	// change the file
+	if err := ioutil.WriteFile(filename, []byte("export SUBSCRIBE_PORT = 1\nexport SUBSCRIBE_HOST = second\nexport SUBSCRIBE_NEW = new\n"), 0644); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+
+	// reload values, changes wait for the receivers
+	if err := live.Reload(); err != nil {
+		t.Fatalf("error reloading values: %v", err)
+	}
+
+	// change of the key
+	if change := <-host; change != (Change{Key: "SUBSCRIBE_HOST", Previous: "first", Existed: true, Value: "second", Exists: true, Source: Source{File: filename, Line: 2}}) {
+		t.Errorf("unexpected change: %+v", change)
+	}
+
+	// changes of all keys, new ones first
+	for _, expected := range []Change{
+		{Key: "SUBSCRIBE_HOST", Previous: "first", Existed: true, Value: "second", Exists: true, Source: Source{File: filename, Line: 2}},
+		{Key: "SUBSCRIBE_NEW", Value: "new", Exists: true, Source: Source{File: filename, Line: 3}},
+		{Key: "SUBSCRIBE_OLD", Previous: "old", Existed: true},
+	} {
+		if change := <-all; change != expected {
+			t.Errorf("expected %+v, got %+v", expected, change)
+		}
+	}
+
+	// unchanged values have no changes
+	live.Store(live.Values())
+	select {
+	case change := <-all:
+		t.Errorf("unexpected change: %+v", change)
+	default:
+	}
+
+	// unsubscribe while changes are waiting for the receiver
+	time.AfterFunc(10*time.Millisecond, func() { live.Unsubscribe(all) })
+	live.Store(Values{})
+
+	// channel is closed
+	for range all {
+	}
+
+	// channel is closed after the changes already delivered
+	live.Unsubscribe(host)
+	if change := <-host; change.Key != "SUBSCRIBE_HOST" || change.Exists {
+		t.Errorf("expected removal of SUBSCRIBE_HOST, got %+v", change)
+	}
+	if _, ok := <-host; ok {
+		t.Error("channel is unsubscribed but it is not closed")
+	}
+}
+
+// TestSubscribeSlowReceiver tests that a receiver that is behind never blocks reloading.
+func TestSubscribeSlowReceiver(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "export SLOW_HOST = 0\nexport SLOW_PORT = 0\n")
+
+	// live values
+	live, err := ParseLiveValues(filename)
+	if err != nil {
+		t.Fatalf("error parsing live values: %v", err)
+	}
+
+	// changes of the keys, never received while reloading
+	changes := live.Subscribe("SLOW_HOST", "SLOW_PORT")
+
+	// iterating over reloads
+	for i := 1; i <= 10; i++ {
+
+		// change the file
+		if err := ioutil.WriteFile(filename, []byte(fmt.Sprintf("export SLOW_HOST = %d\nexport SLOW_PORT = 0\n", i)), 0644); err != nil {
+			t.Fatalf("error writing file: %v", err)
+		}
+
+		// reload values without waiting for the receiver
+		done := make(chan error)
+		go func() { done <- live.Reload() }()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("error reloading values: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("reload is blocked by the receiver")
+		}
+	}
+
+	// changes of the key are merged into one
+	if change := <-changes; change != (Change{Key: "SLOW_HOST", Previous: "0", Existed: true, Value: "10", Exists: true, Source: Source{File: filename, Line: 1}}) {
+		t.Errorf("unexpected change: %+v", change)
+	}
+	select {
+	case change := <-changes:
+		t.Errorf("unexpected change: %+v", change)
+	default:
+	}
+}
@@ -0,0 +1,196 @@
+package envfile
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// WithCommandSubstitution enables values written as $(command args) in the default dialect: the command
+// is run when the file is parsed and its output without trailing line breaks becomes the value, e.g.
+// TOKEN = $(aws sts get-session-token --query Credentials.SessionToken --output text). Only values written
+// as commands are run: a reference whose value looks like a command stays as it is. The command line as it
+// is written is split like SplitCommand does, without a shell, then references are resolved in every word,
+// so values of references can't add words; raw and single-quoted values are not run. A command running longer than the timeout is killed. Files run commands only
+// with this option, since anyone who can write the file can run any program with it.
+func WithCommandSubstitution(timeout time.Duration) Option {
+	return func(l *Loader) {
+
+		// set command timeout, substitution is enabled
+		l.commandTimeout = timeout
+	}
+}
+
+// WithAllowedCommands restricts command substitution to the commands with the names, compared
+// with the first word of the command line as it is written, before references are resolved,
+// e.g. "aws" or "/usr/bin/gcloud". A command named by a reference is allowed only if the
+// reference as it is written, e.g. "{ CLI }", is one of the names.
+func WithAllowedCommands(names ...string) Option {
+	return func(l *Loader) {
+
+		// set allowed commands, none without names
+		l.allowedCommands = append([]string{}, names...)
+	}
+}
+
+// commandLine returns the command line of the value written as $(command args).
+func commandLine(value string) (string, bool) {
+
+	// value is not a command substitution
+	if !strings.HasPrefix(value, "$(") || !strings.HasSuffix(value, ")") {
+		return "", false
+	}
+
+	return value[2 : len(value)-1], true
+}
+
+// commandWaitDelay is the time a killed command is given to close its output, so a process it started
+// that keeps the output open can't hold parsing after the timeout.
+const commandWaitDelay = time.Second
+
+// substitute replaces values written as $(command args) with the output of the commands.
+func (l *Loader) substitute(filename string, payloads []Payload) ([]Payload, error) {
+
+	// iterating over a list of payloads
+	for i, payload := range payloads {
+
+		// command line of the value as it is written, values of references are never run
+		line, ok := commandLine(payload.Raw)
+		if !ok || payload.Literal {
+			continue
+		}
+
+		// name of the command as it is written and words of the command line with references resolved
+		name, words, err := l.commandWords(filename, payloads, i, line)
+		if err != nil {
+			return nil, err
+		}
+
+		// output of the command
+		output, err := l.runCommand(name, words)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] line %d: command of key '%s': %s", parser.PayloadFile(payload, filename), payload.Line, payload.Key, err)
+		}
+
+		// update value
+		payloads[i].Value = output
+	}
+
+	return payloads, nil
+}
+
+// commandWords splits the command line of the payload at the position into words and resolves
+// references in every word with the resolved values of the other payloads, the first word
+// is also returned as it is written.
+func (l *Loader) commandWords(filename string, payloads []Payload, position int, line string) (string, []string, error) {
+
+	// payload
+	payload := payloads[position]
+
+	// references of the command line
+	references := parser.ScanReferences(l.syntax(), line, 0)
+
+	// command line with references replaced by placeholders, so spaces of references don't split words
+	masked := line
+	for i := len(references) - 1; i >= 0; i-- {
+		span := references[i].Span
+		masked = masked[:span.Start] + fmt.Sprintf("\x00%d\x00", i) + masked[span.End:]
+	}
+
+	// words of the command line as it is written
+	words, err := SplitCommand(masked)
+	if err != nil {
+		return "", nil, fmt.Errorf("[%s] line %d: command of key '%s': %s", parser.PayloadFile(payload, filename), payload.Line, payload.Key, err)
+	}
+
+	// name of the command as it is written
+	var name string
+
+	// iterating over words
+	for i, word := range words {
+
+		// iterating over references
+		for k, reference := range references {
+
+			// placeholder is replaced by the reference as it is written
+			word = strings.Replace(word, fmt.Sprintf("\x00%d\x00", k), line[reference.Span.Start:reference.Span.End], 1)
+		}
+
+		// first word as it is written
+		if i == 0 {
+			name = word
+		}
+
+		// resolved payloads are taken as they are, the word is resolved in place of the payload
+		resolved := make([]Payload, len(payloads))
+		for j := range payloads {
+			resolved[j] = payloads[j]
+			resolved[j].Literal = true
+		}
+		resolved[position] = Payload{Line: payload.Line, Key: payload.Key, Value: word, File: payload.File}
+
+		// resolve references of the word
+		resolved, err := l.expand(filename, resolved)
+		if err != nil {
+			return "", nil, err
+		}
+
+		// update word
+		words[i] = resolved[position].Value
+	}
+
+	return name, words, nil
+}
+
+// runCommand runs the words of the command line and returns its output without trailing line breaks,
+// the name is the command as it is written, checked against the allowed commands.
+func (l *Loader) runCommand(name string, words []string) (string, error) {
+
+	// command is empty
+	if len(words) == 0 {
+		return "", errors.New("command is empty")
+	}
+
+	// command is not allowed
+	if l.allowedCommands != nil && !slices.Contains(l.allowedCommands, name) {
+		return "", fmt.Errorf("command '%s' is not allowed", name)
+	}
+
+	// context killing the command after the timeout
+	ctx, cancel := context.WithTimeout(context.Background(), l.commandTimeout)
+
+	// deferred release of the context
+	defer cancel()
+
+	// output of the command
+	var stdout, stderr bytes.Buffer
+
+	// run command
+	cmd := exec.CommandContext(ctx, words[0], words[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = commandWaitDelay
+	if err := cmd.Run(); err != nil {
+
+		// command is killed
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("'%s' timed out after %s", words[0], l.commandTimeout)
+		}
+
+		// error message of the command
+		if message := strings.TrimSpace(stderr.String()); len(message) > 0 {
+			return "", fmt.Errorf("'%s' failed: %s", words[0], message)
+		}
+
+		return "", fmt.Errorf("'%s' failed: %s", words[0], err)
+	}
+
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
@@ -0,0 +1,122 @@
+//go:build !windows
+
+package envfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCommandSubstitution tests replacing of values with the output of commands.
+func TestCommandSubstitution(t *testing.T) {
+
+	// content with commands
+	content := "NAME = world\n" +
+		"GREETING = $(echo hello { NAME })\n" +
+		"QUOTED = \"$(printf '%s\\n\\n' quoted)\"\n" +
+		"RAW = '$(echo raw)'\n" +
+		"TEXT = say $(echo nothing)\n"
+
+	// commands are not run by default
+	payloads, err := ParseString(content)
+	if err != nil {
+		t.Fatalf("error parsing content: %v", err)
+	}
+	if payloads[1].Value != "$(echo hello world)" {
+		t.Errorf("expected command to be kept, got %q", payloads[1].Value)
+	}
+
+	// commands are run
+	payloads, err = NewLoader(WithCommandSubstitution(5 * time.Second)).ParseString(content)
+	if err != nil {
+		t.Fatalf("error parsing content: %v", err)
+	}
+
+	// expected values
+	expected := map[string]string{"NAME": "world", "GREETING": "hello world", "QUOTED": "quoted", "RAW": "$(echo raw)", "TEXT": "say $(echo nothing)"}
+
+	// iterating over payloads
+	for _, payload := range payloads {
+		if value := expected[payload.Key]; payload.Value != value {
+			t.Errorf("expected %s = %q, got %q", payload.Key, value, payload.Value)
+		}
+	}
+
+	// expected errors by loader
+	cases := []struct {
+		loader   *Loader
+		content  string
+		expected string
+	}{
+		{NewLoader(WithCommandSubstitution(time.Second), WithAllowedCommands("printf")), "KEY = $(echo hello)\n", "[reader] line 1: command of key 'KEY': command 'echo' is not allowed"},
+		{NewLoader(WithCommandSubstitution(time.Second), WithAllowedCommands()), "KEY = $(echo hello)\n", "[reader] line 1: command of key 'KEY': command 'echo' is not allowed"},
+		{NewLoader(WithCommandSubstitution(100 * time.Millisecond)), "KEY = $(sleep 5)\n", "[reader] line 1: command of key 'KEY': 'sleep' timed out after 100ms"},
+		{NewLoader(WithCommandSubstitution(time.Second)), "KEY = $(sh -c 'echo denied >&2; exit 1')\n", "[reader] line 1: command of key 'KEY': 'sh' failed: denied"},
+		{NewLoader(WithCommandSubstitution(time.Second)), "KEY = $( )\n", "[reader] line 1: command of key 'KEY': command is empty"},
+		{NewLoader(WithCommandSubstitution(time.Second), WithAllowedCommands("echo")), "CLI = echo\nKEY = $({ CLI } hello)\n", "[reader] line 2: command of key 'KEY': command '{ CLI }' is not allowed"},
+		{NewLoader(WithCommandSubstitution(100 * time.Millisecond)), "KEY = $(sh -c 'sleep 5 & sleep 5')\n", "[reader] line 1: command of key 'KEY': 'sh' timed out after 100ms"},
+	}
+
+	// iterating over cases
+	for _, c := range cases {
+		if _, err := c.loader.ParseString(c.content); err == nil || err.Error() != c.expected {
+			t.Errorf("%s: expected %q, got %v", strings.TrimSpace(c.content), c.expected, err)
+		}
+	}
+}
+
+// TestCommandSubstitutionReferences tests that values of references are never run as commands.
+func TestCommandSubstitutionReferences(t *testing.T) {
+
+	// temporary directory for the file the command would create
+	dir, err := ioutil.TempDir("", "substitution")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v", err)
+	}
+
+	// deferred directory removal
+	defer os.RemoveAll(dir)
+
+	// file created if the command of the environment is run
+	pwned := filepath.Join(dir, "pwned")
+
+	// set environment variables with a command and words
+	os.Setenv("SUBSTITUTION_UPSTREAM", "$(touch "+pwned+")")
+	os.Setenv("SUBSTITUTION_WORDS", "a b; $(touch "+pwned+")")
+
+	// deferred removal of environment variables
+	defer os.Unsetenv("SUBSTITUTION_UPSTREAM")
+	defer os.Unsetenv("SUBSTITUTION_WORDS")
+
+	// content referencing the variables
+	content := "UPSTREAM = { SUBSTITUTION_UPSTREAM }\nCOPY = { UPSTREAM }\nWORDS = $(printf %s| { SUBSTITUTION_WORDS })\n"
+
+	// parse content with commands
+	payloads, err := NewLoader(WithCommandSubstitution(5 * time.Second)).ParseString(content)
+	if err != nil {
+		t.Fatalf("error parsing content: %v", err)
+	}
+
+	// expected values
+	expected := map[string]string{
+		"UPSTREAM": "$(touch " + pwned + ")",
+		"COPY":     "$(touch " + pwned + ")",
+		"WORDS":    "a b; $(touch " + pwned + ")|",
+	}
+
+	// iterating over payloads
+	for _, payload := range payloads {
+		if value := expected[payload.Key]; payload.Value != value {
+			t.Errorf("expected %s = %q, got %q", payload.Key, value, payload.Value)
+		}
+	}
+
+	// command of the environment is not run
+	if _, err := os.Stat(pwned); err == nil {
+		t.Errorf("expected command of the environment not to be run")
+	}
+}
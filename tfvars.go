@@ -0,0 +1,16 @@
+package envfile
+
+import (
+	"io"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// TFVarsEncoder writes payloads as terraform variables.
+type TFVarsEncoder = parser.TFVarsEncoder
+
+// EncodeTFVars writes payloads as terraform variables with lower-cased names,
+// numbers and booleans are written without quotes.
+func EncodeTFVars(payloads []Payload, w io.Writer) error {
+	return parser.EncodeTFVars(payloads, w)
+}
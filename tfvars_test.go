@@ -0,0 +1,62 @@
+package envfile
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestEncodeTFVars tests the terraform variables output.
+func TestEncodeTFVars(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "REGION = eu-west-1\nINSTANCES = 3\nRATIO = 0.5\nPUBLIC = TRUE\nZONES = a, b\nTAG = ${{var.name}}\n")
+
+	// parse file
+	payloads, err := Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// expected output by encoder
+	expected := map[*TFVarsEncoder]string{
+		{Typed: true}: `region    = "eu-west-1"
+instances = 3
+ratio     = 0.5
+public    = true
+zones     = "a, b"
+tag       = "$${var.name}"
+`,
+		{ListSeparator: ","}: `region    = "eu-west-1"
+instances = "3"
+ratio     = "0.5"
+public    = "TRUE"
+zones     = ["a", "b"]
+tag       = "$${var.name}"
+`,
+	}
+
+	// iterating over encoders
+	for encoder, output := range expected {
+
+		// variables output
+		var buffer bytes.Buffer
+
+		// encode variables
+		if err := encoder.Encode(payloads, &buffer); err != nil {
+			t.Fatalf("error encoding variables: %v", err)
+		}
+
+		// output is different from expected
+		if buffer.String() != output {
+			t.Errorf("expected variables:\n%s\ngot:\n%s", output, buffer.String())
+		}
+	}
+
+	// default encoder output
+	var buffer bytes.Buffer
+
+	// encode variables
+	if err := EncodeTFVars(payloads[:2], &buffer); err != nil || buffer.String() != "region    = \"eu-west-1\"\ninstances = 3\n" {
+		t.Errorf("unexpected output %q, error: %v", buffer.String(), err)
+	}
+}
@@ -0,0 +1,44 @@
+package envfile
+
+import "github.com/afonichev/envfile/parser"
+
+// TokenKind is a kind of token of a line.
+type TokenKind = parser.TokenKind
+
+const (
+
+	// TokenDirective is the export, overload or raw directive before the key, or the include directive.
+	TokenDirective = parser.TokenDirective
+
+	// TokenKey is the key.
+	TokenKey = parser.TokenKey
+
+	// TokenOperator is the assignment operator, = or ?=.
+	TokenOperator = parser.TokenOperator
+
+	// TokenValue is a part of the value outside of references.
+	TokenValue = parser.TokenValue
+
+	// TokenReference is a reference to a variable including its delimiters.
+	TokenReference = parser.TokenReference
+
+	// TokenComment is a comment line or a comment after the value, including the number sign.
+	TokenComment = parser.TokenComment
+
+	// TokenSection is a section line including its square brackets.
+	TokenSection = parser.TokenSection
+)
+
+// Token is a part of a line with its position, tokens of a line do not overlap and are in order of columns.
+type Token = parser.Token
+
+// Tokenize splits the line of the default dialect into tokens, see Loader.Tokenize.
+func Tokenize(line string) ([]Token, error) {
+	return NewLoader().Tokenize(line)
+}
+
+// Tokenize splits the line into tokens for syntax highlighters and formatters, following the syntax
+// of the loader. Blank lines have no tokens. Tokens found so far are returned with the problem of the line.
+func (l *Loader) Tokenize(line string) ([]Token, error) {
+	return parser.Tokenize(l.syntax(), line)
+}
@@ -0,0 +1,49 @@
+package envfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestTokenize tests splitting of lines into tokens.
+func TestTokenize(t *testing.T) {
+
+	// tokens of the entry
+	tokens, err := Tokenize("  export overload URL = http://{ HOST }:{ PORT }/api")
+	if err != nil {
+		t.Fatalf("error tokenizing line: %v", err)
+	}
+
+	// expected tokens
+	expected := []Token{
+		{Kind: TokenDirective, Text: "export", Span: Span{Start: 2, End: 8}},
+		{Kind: TokenDirective, Text: "overload", Span: Span{Start: 9, End: 17}},
+		{Kind: TokenKey, Text: "URL", Span: Span{Start: 18, End: 21}},
+		{Kind: TokenOperator, Text: "=", Span: Span{Start: 22, End: 23}},
+		{Kind: TokenValue, Text: "http://", Span: Span{Start: 24, End: 31}},
+		{Kind: TokenReference, Text: "{ HOST }", Span: Span{Start: 31, End: 39}},
+		{Kind: TokenValue, Text: ":", Span: Span{Start: 39, End: 40}},
+		{Kind: TokenReference, Text: "{ PORT }", Span: Span{Start: 40, End: 48}},
+		{Kind: TokenValue, Text: "/api", Span: Span{Start: 48, End: 52}},
+	}
+
+	// tokens are different from expected
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("expected %+v, got %+v", expected, tokens)
+	}
+
+	// conditional operator
+	if tokens, _ := Tokenize("KEY ?= value"); len(tokens) != 3 || tokens[1].Text != "?=" {
+		t.Errorf("expected operator ?=, got %+v", tokens)
+	}
+
+	// comment
+	if tokens, _ := Tokenize(" # comment "); !reflect.DeepEqual(tokens, []Token{{Kind: TokenComment, Text: "# comment", Span: Span{Start: 1, End: 10}}}) {
+		t.Errorf("expected comment token, got %+v", tokens)
+	}
+
+	// line that can't be split
+	if _, err := Tokenize("invalid line"); err == nil {
+		t.Error("line is invalid but tokenize didn't return an error")
+	}
+}
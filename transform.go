@@ -0,0 +1,166 @@
+package envfile
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// Transformer changes the value of a key after references are resolved, e.g. TrimSpace or AbsPath.
+// The source is the file and line defining the value.
+type Transformer func(value string, source Source) (string, error)
+
+// transform is a chain of transformers applied to keys matching the pattern.
+type transform struct {
+
+	// glob pattern of keys, e.g. "*_DIR"
+	pattern string
+
+	// transformers in order of application
+	transformers []Transformer
+}
+
+// WithTransform adds the chain of transformers applied to values of keys matching the glob pattern
+// of path.Match, e.g. WithTransform("*_DIR", ExpandHome, AbsPath). Chains are applied in order they are
+// added, references to a key see its value before transformation.
+func WithTransform(pattern string, transformers ...Transformer) Option {
+	return func(l *Loader) {
+
+		// add chain
+		l.transforms = append(l.transforms, transform{pattern: pattern, transformers: transformers})
+	}
+}
+
+// WithPaths treats values of keys matching the glob patterns as paths: ~ and ~user are replaced with
+// home directories and relative paths are made absolute, relative to the directory of the file defining them.
+func WithPaths(patterns ...string) Option {
+	return func(l *Loader) {
+
+		// iterating over patterns
+		for _, pattern := range patterns {
+
+			// add chain of path transformers
+			l.transforms = append(l.transforms, transform{pattern: pattern, transformers: []Transformer{ExpandHome, AbsPath}})
+		}
+	}
+}
+
+// TrimSpace removes leading and trailing white space of the value.
+func TrimSpace(value string, source Source) (string, error) {
+	return strings.TrimSpace(value), nil
+}
+
+// ExpandHome replaces the leading ~ of the value with the home directory of the current user
+// and ~user with the home directory of the user.
+func ExpandHome(value string, source Source) (string, error) {
+
+	// value does not start with a home directory
+	if !strings.HasPrefix(value, "~") {
+		return value, nil
+	}
+
+	// end of the user name
+	end := strings.IndexFunc(value, func(r rune) bool {
+		return r == '/' || r == filepath.Separator
+	})
+	if end < 0 {
+		end = len(value)
+	}
+
+	// home directory of the current user
+	if end == 1 {
+
+		// home directory
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		return home + value[end:], nil
+	}
+
+	// user of the name
+	u, err := user.Lookup(value[1:end])
+	if err != nil {
+		return "", err
+	}
+
+	return u.HomeDir + value[end:], nil
+}
+
+// AbsPath makes the relative path of the value absolute, relative to the directory of the file defining it.
+// Empty values are kept.
+func AbsPath(value string, source Source) (string, error) {
+
+	// value is empty or already absolute
+	if len(value) == 0 || filepath.IsAbs(value) {
+		return value, nil
+	}
+
+	return filepath.Abs(filepath.Join(filepath.Dir(source.File), value))
+}
+
+// NormalizeURL lowercases the scheme and the host of the URL and removes the default port of http and https.
+func NormalizeURL(value string, source Source) (string, error) {
+
+	// parse URL
+	u, err := parser.ParseURL(value)
+	if err != nil {
+		return "", err
+	}
+
+	// lowercase scheme and host
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+
+	// default port of the scheme
+	if (u.Scheme == "http" && u.Port() == "80") || (u.Scheme == "https" && u.Port() == "443") {
+		u.Host = strings.TrimSuffix(u.Host, ":"+u.Port())
+	}
+
+	return u.String(), nil
+}
+
+// transform applies the chains of transformers to the payloads of matching keys.
+func (l *Loader) transform(filename string, payloads []Payload) ([]Payload, error) {
+
+	// iterating over a list of payloads
+	for i, payload := range payloads {
+
+		// iterating over chains
+		for _, chain := range l.transforms {
+
+			// key does not match the pattern
+			matched, err := path.Match(chain.pattern, payload.Key)
+			if err != nil {
+				return nil, fmt.Errorf("transform pattern '%s': %s", chain.pattern, err)
+			}
+			if !matched {
+				continue
+			}
+
+			// iterating over transformers
+			for _, transformer := range chain.transformers {
+
+				// transform value
+				value, err := transformer(payload.Value, Source{File: parser.PayloadFile(payload, filename), Line: payload.Line})
+				if err != nil {
+					return nil, fmt.Errorf("[%s] line %d: key '%s': %s", parser.PayloadFile(payload, filename), payload.Line, payload.Key, err)
+				}
+
+				// update value
+				payload.Value = value
+			}
+		}
+
+		// update payload
+		payloads[i] = payload
+	}
+
+	return payloads, nil
+}
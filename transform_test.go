@@ -0,0 +1,105 @@
+package envfile
+
+import (
+	"errors"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestTransform tests chains of transformers applied to matching keys.
+func TestTransform(t *testing.T) {
+
+	// home directory of the current user
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("home directory is unknown:", err)
+	}
+
+	// loader with chains of transformers
+	loader := NewLoader(
+		WithTransform("*_DIR", ExpandHome, AbsPath),
+		WithTransform("*_URL", NormalizeURL),
+		WithTransform("*", func(value string, source Source) (string, error) {
+			return strings.TrimSuffix(value, "!"), nil
+		}),
+	)
+
+	// file with paths and URLs
+	filename := createFile(t, "CACHE_DIR = ~/cache\nDATA_DIR = data\nAPI_URL = HTTPS://Example.COM:443/v1\nNAME = name!\n")
+
+	// parse file
+	payloads, err := loader.Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// expected values
+	expected := map[string]string{
+		"CACHE_DIR": filepath.Join(home, "cache"),
+		"DATA_DIR":  filepath.Join(filepath.Dir(filename), "data"),
+		"API_URL":   "https://example.com/v1",
+		"NAME":      "name",
+	}
+
+	// iterating over expected values
+	for key, value := range expected {
+		if payload, _ := payloads.Lookup(key); payload.Value != value {
+			t.Errorf("expected %s to be %s, got %s", key, value, payload.Value)
+		}
+	}
+
+	// failed transformer
+	failing := NewLoader(WithTransform("KEY", func(value string, source Source) (string, error) {
+		return "", errors.New("invalid value")
+	}))
+
+	// error of the transformer with the line
+	if _, err := failing.Parse(createFile(t, "KEY = value\n")); err == nil || !strings.HasSuffix(err.Error(), "line 1: key 'KEY': invalid value") {
+		t.Errorf("expected error of the transformer, got %v", err)
+	}
+}
+
+// TestWithPaths tests expansion of home directories and relative paths.
+func TestWithPaths(t *testing.T) {
+
+	// current user
+	current, err := user.Current()
+	if err != nil {
+		t.Skip("current user is unknown:", err)
+	}
+
+	// user name without the domain of Windows
+	name := current.Username[strings.LastIndex(current.Username, `\`)+1:]
+
+	// file with paths
+	filename := createFile(t, "HOME_DIR = ~"+name+"/data\nLOG_FILE = logs/app.log\nROOT_DIR = "+os.TempDir()+"\nNAME = ~"+name+"\n")
+
+	// parse file
+	payloads, err := NewLoader(WithPaths("*_DIR", "*_FILE")).Parse(filename)
+	if err != nil {
+		t.Fatalf("error parsing env file: %v", err)
+	}
+
+	// expected values
+	expected := map[string]string{
+		"HOME_DIR": current.HomeDir + "/data",
+		"LOG_FILE": filepath.Join(filepath.Dir(filename), "logs", "app.log"),
+		"ROOT_DIR": os.TempDir(),
+		"NAME":     "~" + name,
+	}
+
+	// iterating over expected values
+	for key, value := range expected {
+		if payload, _ := payloads.Lookup(key); payload.Value != value {
+			t.Errorf("expected %s to be %s, got %s", key, value, payload.Value)
+		}
+	}
+
+	// unknown user
+	if _, err := NewLoader(WithPaths("*_DIR")).Parse(createFile(t, "DATA_DIR = ~envfile-unknown-user/data\n")); err == nil {
+		t.Error("user doesn't exist but parse didn't return an error")
+	}
+}
@@ -0,0 +1,66 @@
+package envfile
+
+import "fmt"
+
+// UndefinedPolicy is a behavior for references to variables that do not exist.
+type UndefinedPolicy int
+
+const (
+
+	// UndefinedError fails parsing with the "variable does not exist" error.
+	UndefinedError UndefinedPolicy = iota
+
+	// UndefinedEmpty replaces the reference with an empty string.
+	UndefinedEmpty
+
+	// UndefinedLiteral leaves the reference as it is written.
+	UndefinedLiteral
+)
+
+// WithUndefinedPolicy sets the behavior for references to variables that do not exist.
+func WithUndefinedPolicy(policy UndefinedPolicy) Option {
+	return func(l *Loader) {
+
+		// set policy
+		l.undefinedPolicy = policy
+	}
+}
+
+// WithUndefinedFallback sets the function returning values of variables that do not exist,
+// it takes precedence over the policy.
+func WithUndefinedFallback(fallback func(variable string) (string, error)) Option {
+	return func(l *Loader) {
+
+		// set fallback
+		l.undefinedFallback = fallback
+	}
+}
+
+// undefined returns the value of the variable that does not exist according to the policy,
+// false leaves the reference as it is written.
+func (l *Loader) undefined(variable string) (string, bool, error) {
+
+	// fallback function
+	if l.undefinedFallback != nil {
+
+		// value of the fallback
+		value, err := l.undefinedFallback(variable)
+
+		return value, true, err
+	}
+
+	switch l.undefinedPolicy {
+
+	// empty string
+	case UndefinedEmpty:
+		return "", true, nil
+
+	// reference is left as it is written
+	case UndefinedLiteral:
+		return "", false, nil
+
+	// error
+	default:
+		return "", false, fmt.Errorf("variable '%s' does not exist", variable)
+	}
+}
@@ -0,0 +1,52 @@
+package envfile
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestParseUndefinedPolicy tests behaviors for references to variables that do not exist.
+func TestParseUndefinedPolicy(t *testing.T) {
+
+	// file content
+	filename := createFile(t, "KEY = a{ ENVFILE_UNDEFINED }b\n")
+
+	// expected values by loader
+	expected := map[*Loader]string{
+		NewLoader(WithUndefinedPolicy(UndefinedEmpty)):   "ab",
+		NewLoader(WithUndefinedPolicy(UndefinedLiteral)): "a{ ENVFILE_UNDEFINED }b",
+		NewLoader(WithUndefinedFallback(func(variable string) (string, error) {
+			return "<" + variable + ">", nil
+		})): "a<ENVFILE_UNDEFINED>b",
+	}
+
+	// iterating over loaders
+	for loader, value := range expected {
+
+		// parse file
+		payloads, err := loader.Parse(filename)
+		if err != nil {
+			t.Fatalf("error parsing env file: %v", err)
+		}
+
+		// value is different from expected
+		if payloads[0].Value != value {
+			t.Errorf("expected KEY to be %s, got %s", value, payloads[0].Value)
+		}
+	}
+
+	// default policy
+	if _, err := Parse(filename); err == nil {
+		t.Error("variable doesn't exist but parse didn't return an error")
+	}
+
+	// fallback error
+	_, err := NewLoader(WithUndefinedFallback(func(variable string) (string, error) {
+		return "", errors.New("no fallback")
+	})).Parse(filename)
+
+	// fallback error is not returned
+	if err == nil || err.Error() != "["+filename+"] line 1: no fallback" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
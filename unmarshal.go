@@ -0,0 +1,131 @@
+package envfile
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Unmarshal parses the file and stores its values in the struct pointed to by v, see Loader.Unmarshal.
+func Unmarshal(filename string, v any) error {
+	return NewLoader().Unmarshal(filename, v)
+}
+
+// Unmarshal parses the file and stores its values in the fields of the struct pointed to by v
+// tagged with `env:"KEY"`, converted as by Get. Fields of missing keys keep their values, so defaults
+// can be set before, unless the tag has the required option, e.g. `env:"PORT,required"`. Untagged
+// struct fields are filled recursively, fields tagged with `env:"-"` are skipped.
+// A value that can't be converted is reported as *ConversionError.
+func (l *Loader) Unmarshal(filename string, v any) error {
+
+	// pointer to the struct
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Pointer || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return errors.New("unmarshal target must be a non-nil pointer to a struct")
+	}
+
+	// parse file
+	payloads, err := l.Parse(filename)
+	if err != nil {
+		return err
+	}
+
+	return l.unmarshal(filename, payloads, target.Elem())
+}
+
+// unmarshal stores the values of the payloads in the tagged fields of the struct.
+func (l *Loader) unmarshal(filename string, payloads Payloads, target reflect.Value) error {
+
+	// iterating over fields
+	for i := 0; i < target.NumField(); i++ {
+
+		// field and its description
+		field, description := target.Field(i), target.Type().Field(i)
+
+		// unexported field
+		if !description.IsExported() {
+			continue
+		}
+
+		// tag of the field
+		tag, tagged := description.Tag.Lookup("env")
+
+		// field is skipped
+		if tag == "-" {
+			continue
+		}
+
+		// untagged nested struct
+		if !tagged {
+
+			// struct that is not a value itself
+			if field.Kind() == reflect.Struct && !convertible(field) {
+				if err := l.unmarshal(filename, payloads, field); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		// key and options of the tag
+		key, options, _ := strings.Cut(tag, ",")
+
+		// payload of the key
+		payload, ok := l.lookupPayload(payloads, key)
+		if !ok {
+
+			// key is required
+			if options == "required" {
+				return fmt.Errorf("[%s] key '%s' of field %s is not set", filename, key, description.Name)
+			}
+
+			continue
+		}
+
+		// convert value
+		if err := convert(payload.Value, field); err != nil {
+			return &ConversionError{
+				Key:    payload.Key,
+				Raw:    payload.Value,
+				Target: field.Type().String(),
+				File:   filename,
+				Line:   payload.Line,
+				Err:    err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// lookupPayload returns the payload of the key, keys are compared as the loader does.
+func (l *Loader) lookupPayload(payloads Payloads, key string) (Payload, bool) {
+
+	// iterating over a list of payloads
+	for _, payload := range payloads {
+
+		// payload is found
+		if l.sameKey(payload.Key, key) {
+			return payload, true
+		}
+	}
+
+	return Payload{}, false
+}
+
+// convertible reports whether the struct is converted from a value as a whole, e.g. time.Time.
+func convertible(field reflect.Value) bool {
+
+	// custom converter
+	if _, ok := converters.Load(field.Type()); ok {
+		return true
+	}
+
+	// type parses text itself
+	_, ok := field.Addr().Interface().(encoding.TextUnmarshaler)
+
+	return ok
+}
@@ -0,0 +1,64 @@
+package envfile
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestUnmarshal tests decoding of the file into a struct.
+func TestUnmarshal(t *testing.T) {
+
+	// configuration
+	var config struct {
+		Host     string        `env:"HOST"`
+		Port     int           `env:"PORT,required"`
+		Debug    bool          `env:"DEBUG"`
+		Ratio    float64       `env:"RATIO"`
+		Timeout  time.Duration `env:"TIMEOUT"`
+		Hosts    []string      `env:"HOSTS"`
+		Name     string        `env:"NAME"`
+		Skipped  string        `env:"-"`
+		Database struct {
+			URL string `env:"DB_URL"`
+		}
+	}
+
+	// default value of the missing key
+	config.Name = "default"
+
+	// file with values
+	filename := createFile(t, "HOST = localhost\nPORT = 8080\nDEBUG = true\nRATIO = 0.5\nTIMEOUT = 5s\nHOSTS = a, b\nDB_URL = postgres://{ HOST }\n")
+
+	// decode file
+	if err := Unmarshal(filename, &config); err != nil {
+		t.Fatalf("error unmarshaling env file: %v", err)
+	}
+
+	// decoded values
+	if config.Host != "localhost" || config.Port != 8080 || !config.Debug || config.Ratio != 0.5 || config.Timeout != 5*time.Second {
+		t.Errorf("unexpected values: %+v", config)
+	}
+
+	// list, default and nested values
+	if !reflect.DeepEqual(config.Hosts, []string{"a", "b"}) || config.Name != "default" || config.Database.URL != "postgres://localhost" {
+		t.Errorf("unexpected values: %+v", config)
+	}
+
+	// required key is missing
+	if err := Unmarshal(createFile(t, "HOST = localhost\n"), &config); err == nil {
+		t.Error("required key is missing but unmarshal didn't return an error")
+	}
+
+	// value is not a number
+	var conversion *ConversionError
+	if err := Unmarshal(createFile(t, "PORT = abc\n"), &config); !errors.As(err, &conversion) || conversion.Line != 1 || conversion.Key != "PORT" {
+		t.Errorf("expected conversion error of PORT on line 1, got %v", err)
+	}
+
+	// target is not a pointer to a struct
+	if err := Unmarshal(filename, config); err == nil {
+		t.Error("target is not a pointer but unmarshal didn't return an error")
+	}
+}
@@ -0,0 +1,10 @@
+package envfile
+
+import "github.com/afonichev/envfile/parser"
+
+// BuildURL composes the URL from parts escaping the credentials and the path, so passwords with
+// special characters do not break it, e.g. BuildURL("postgres", user, password, host+":"+port, name).
+// Credentials are omitted if the username is empty, the password is omitted if it is empty.
+func BuildURL(scheme, username, password, host, path string) string {
+	return parser.BuildURL(scheme, username, password, host, path)
+}
@@ -0,0 +1,51 @@
+package envfile
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// userConfigFilename is the name of the file in the per-user configuration directory of the application.
+const userConfigFilename = "envfile"
+
+// LoadUserConfig loads the first existing file of the working directory or of the per-user
+// configuration directory of the application: $XDG_CONFIG_HOME/<app>/envfile on Unix,
+// ~/Library/Application Support/<app>/envfile on macOS and %AppData%\<app>\envfile on Windows.
+// It returns the name of the loaded file, which is empty if none of the files exists.
+func LoadUserConfig(appName string) (string, error) {
+	return std.LoadUserConfig(appName)
+}
+
+// LoadUserConfig loads the first existing file of the working directory or of the per-user
+// configuration directory of the application, see the package-level LoadUserConfig.
+func (l *Loader) LoadUserConfig(appName string) (string, error) {
+
+	// iterating over files in order of search
+	for _, filename := range userConfigFiles(appName) {
+
+		// file does not exist
+		if _, err := os.Stat(filename); err != nil {
+			continue
+		}
+
+		return filename, l.Load(filename)
+	}
+
+	return "", nil
+}
+
+// userConfigFiles returns the default files of the working directory followed by
+// the file of the per-user configuration directory.
+func userConfigFiles(app string) []string {
+
+	// files of the working directory
+	files := defaultFiles(app)
+
+	// per-user configuration directory is unknown, e.g. neither $XDG_CONFIG_HOME nor $HOME is set
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return files
+	}
+
+	return append(files, filepath.Join(dir, app, userConfigFilename))
+}
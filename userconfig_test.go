@@ -0,0 +1,61 @@
+package envfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// TestLoadUserConfig tests loading of the file from the XDG configuration directory.
+func TestLoadUserConfig(t *testing.T) {
+
+	// XDG configuration directory is used on Unix only
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" || runtime.GOOS == "plan9" {
+		t.Skip("XDG configuration directory is not used on", runtime.GOOS)
+	}
+
+	// temporary directory
+	dir, err := ioutil.TempDir("", "userconfig")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %v", err)
+	}
+
+	// deferred removal of the directory
+	defer os.RemoveAll(dir)
+
+	// configuration directory
+	config := os.Getenv("XDG_CONFIG_HOME")
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	defer os.Setenv("XDG_CONFIG_HOME", config)
+
+	// missing file is not an error
+	if filename, err := LoadUserConfig("app"); err != nil || len(filename) > 0 {
+		t.Errorf("expected nothing to be loaded, got %q, %v", filename, err)
+	}
+
+	// file in the configuration directory
+	user := filepath.Join(dir, "app", userConfigFilename)
+	os.MkdirAll(filepath.Dir(user), 0755)
+	ioutil.WriteFile(user, []byte("export USER_CONFIG_KEY = value\n"), 0644)
+
+	// deferred unset of the variable
+	defer os.Unsetenv("USER_CONFIG_KEY")
+
+	// load file
+	filename, err := LoadUserConfig("app")
+	if err != nil {
+		t.Fatalf("error loading user config: %v", err)
+	}
+
+	// file of the configuration directory is loaded
+	if filename != user {
+		t.Errorf("expected %s, got %s", user, filename)
+	}
+
+	// value of the variable
+	if value := os.Getenv("USER_CONFIG_KEY"); value != "value" {
+		t.Errorf("expected value, got %s", value)
+	}
+}
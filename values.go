@@ -0,0 +1,217 @@
+package envfile
+
+import (
+	"fmt"
+	"iter"
+	"path"
+	"strings"
+
+	"github.com/afonichev/envfile/parser"
+)
+
+// Values are the exported and overloaded keys of files as they would be in the environment after Load,
+// without changing it: a key already set in the environment keeps its value unless it is overloaded.
+// Keys the files do not define are looked up in the environment. Values are never changed after they
+// are parsed, so they are safe for concurrent use.
+type Values struct {
+
+	// keys of the files in order of the first definition
+	keys []string
+
+	// effective values by the name of the key used in indexes
+	values map[string]value
+
+	// lookup of keys in the environment
+	lookup func(key string) (string, bool)
+
+	// name of the key used in indexes, the key itself if nil
+	index func(key string) string
+}
+
+// Source is the place an effective value comes from: a line of a file or the environment.
+type Source struct {
+
+	// file name, empty for the environment
+	File string `json:"file,omitempty"`
+
+	// line number in file
+	Line int `json:"line,omitempty"`
+}
+
+// Environment reports whether the value comes from the environment.
+func (s Source) Environment() bool {
+	return len(s.File) == 0
+}
+
+// String returns the source in the form "file:line" or "process environment".
+func (s Source) String() string {
+
+	// value from the environment
+	if s.Environment() {
+		return "process environment"
+	}
+
+	return fmt.Sprintf("%s:%d", s.File, s.Line)
+}
+
+// value is an effective value of the key.
+type value struct {
+
+	// value
+	value string
+
+	// file defining the value, empty if the value comes from the environment
+	file string
+
+	// line number in file
+	line int
+}
+
+// ParseValues parses files given in precedence order into values, see Loader.ParseValues.
+func ParseValues(filenames ...string) (Values, error) {
+	return NewLoader().ParseValues(filenames...)
+}
+
+// ParseValues parses files given in precedence order into values, the default file is parsed
+// if no file names are given. The environment is not changed.
+func (l *Loader) ParseValues(filenames ...string) (Values, error) {
+
+	// file name list is empty
+	if len(filenames) == 0 {
+
+		// default file and drop-in fragments
+		filenames = l.defaultNames()
+	}
+
+	// values
+	values := Values{values: make(map[string]value), lookup: l.lookupEnv, index: l.indexKey}
+
+	// iterating over a list of filenames
+	for _, filename := range filenames {
+
+		// parse file
+		payloads, err := l.Parse(filename)
+		if err != nil {
+			return Values{}, err
+		}
+
+		// iteration over payloads
+		for _, payload := range payloads {
+
+			// local key is not loaded
+			if !payload.Export && !payload.Overload {
+				continue
+			}
+
+			// current value of the key
+			current, ok := values.values[l.indexKey(payload.Key)]
+
+			// key is defined for the first time
+			if !ok {
+
+				// add key to list
+				values.keys = append(values.keys, payload.Key)
+
+				// value from the environment
+				if env, exists := l.lookupEnv(payload.Key); exists {
+					current, ok = value{value: env}, true
+				}
+			}
+
+			// key does not exist yet or is overloaded
+			if !ok || payload.Overload {
+				current = value{value: payload.Value, file: parser.PayloadFile(payload, filename), line: payload.Line}
+			}
+
+			// update value
+			values.values[l.indexKey(payload.Key)] = current
+		}
+	}
+
+	return values, nil
+}
+
+// Lookup returns the value of the key and whether it exists in the files or in the environment.
+func (v Values) Lookup(key string) (string, bool) {
+
+	// key of the files
+	if current, ok := v.values[v.indexKey(key)]; ok {
+		return current.value, true
+	}
+
+	// values are empty
+	if v.lookup == nil {
+		return "", false
+	}
+
+	return v.lookup(key)
+}
+
+// GetWithSource returns the value of the key with the place it comes from and whether it exists,
+// so error messages can tell where to fix a bad setting.
+func (v Values) GetWithSource(key string) (string, Source, bool) {
+
+	// key of the files
+	if current, ok := v.values[v.indexKey(key)]; ok {
+		return current.value, Source{File: current.file, Line: current.line}, true
+	}
+
+	// value from the environment
+	value, ok := v.Lookup(key)
+
+	return value, Source{}, ok
+}
+
+// indexKey returns the name of the key used in indexes, see Loader.indexKey.
+func (v Values) indexKey(key string) string {
+
+	// keys are taken as they are written
+	if v.index == nil {
+		return key
+	}
+
+	return v.index(key)
+}
+
+// All returns the keys of the files with their values in order of the first definition.
+func (v Values) All() iter.Seq2[string, string] {
+	return v.filter(func(string) bool { return true })
+}
+
+// WithPrefix returns the keys of the files starting with the prefix, e.g. "FLAG_",
+// with their values in order of the first definition.
+func (v Values) WithPrefix(prefix string) iter.Seq2[string, string] {
+	return v.filter(func(key string) bool { return strings.HasPrefix(key, prefix) })
+}
+
+// Match returns the keys of the files matching the glob, e.g. "DB_*_URL", with their values
+// in order of the first definition. Nothing matches a malformed glob.
+func (v Values) Match(glob string) iter.Seq2[string, string] {
+	return v.filter(func(key string) bool {
+
+		// key matches the glob
+		matched, err := path.Match(glob, key)
+
+		return err == nil && matched
+	})
+}
+
+// filter returns the keys of the files accepted by the function with their values.
+func (v Values) filter(accept func(key string) bool) iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+
+		// iterating over keys
+		for _, key := range v.keys {
+
+			// key is not accepted
+			if !accept(key) {
+				continue
+			}
+
+			// stop iteration
+			if !yield(key, v.values[v.indexKey(key)].value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package envfile
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+// TestValuesIterators tests iteration over subsets of values.
+func TestValuesIterators(t *testing.T) {
+
+	// file content
+	filename := createFile(t, `
+export FLAG_SEARCH = true
+export DB_MAIN_URL = postgres://main
+export FLAG_BETA = false
+export DB_PORT = 5432
+export DB_REPLICA_URL = postgres://replica
+`)
+
+	// parse values
+	values, err := ParseValues(filename)
+	if err != nil {
+		t.Fatalf("error parsing values: %v", err)
+	}
+
+	// collect returns the keys and values of the iterator
+	collect := func(seq func(func(string, string) bool)) []string {
+
+		// keys and values
+		var list []string
+
+		// iterating over keys
+		for key, value := range seq {
+			list = append(list, key+"="+value)
+		}
+
+		return list
+	}
+
+	// all keys in order of the file
+	if all := collect(values.All()); len(all) != 5 || all[0] != "FLAG_SEARCH=true" || all[4] != "DB_REPLICA_URL=postgres://replica" {
+		t.Errorf("unexpected values: %v", all)
+	}
+
+	// keys with the prefix
+	if flags, expected := collect(values.WithPrefix("FLAG_")), []string{"FLAG_SEARCH=true", "FLAG_BETA=false"}; !reflect.DeepEqual(flags, expected) {
+		t.Errorf("expected %v, got %v", expected, flags)
+	}
+
+	// keys matching the glob
+	if urls, expected := collect(values.Match("DB_*_URL")), []string{"DB_MAIN_URL=postgres://main", "DB_REPLICA_URL=postgres://replica"}; !reflect.DeepEqual(urls, expected) {
+		t.Errorf("expected %v, got %v", expected, urls)
+	}
+
+	// malformed glob matches nothing
+	if none := collect(values.Match("[")); len(none) > 0 {
+		t.Errorf("expected nothing to match, got %v", none)
+	}
+
+	// iteration is stopped
+	for key := range values.All() {
+		if key != "FLAG_SEARCH" {
+			t.Errorf("expected iteration to stop after FLAG_SEARCH, got %s", key)
+		}
+		break
+	}
+}
+
+// TestValuesGetWithSource tests sources of values.
+func TestValuesGetWithSource(t *testing.T) {
+
+	// set environment variables for the test
+	os.Setenv("ENVFILE_SOURCE_KEPT", "environment")
+	os.Setenv("ENVFILE_SOURCE_ONLY_ENV", "environment")
+
+	// deferred removal of the environment variables
+	defer os.Unsetenv("ENVFILE_SOURCE_KEPT")
+	defer os.Unsetenv("ENVFILE_SOURCE_ONLY_ENV")
+
+	// base and override files
+	base := createFile(t, "export PORT = 80\nexport ENVFILE_SOURCE_KEPT = file\nexport HOST = localhost\n")
+	local := createFile(t, "overload PORT = 8080\nexport HOST = other\n")
+
+	// parse values
+	values, err := ParseValues(base, local)
+	if err != nil {
+		t.Fatalf("error parsing values: %v", err)
+	}
+
+	// expected sources by key
+	expected := map[string]string{
+		"PORT":                    local + ":1",
+		"HOST":                    base + ":3",
+		"ENVFILE_SOURCE_KEPT":     "process environment",
+		"ENVFILE_SOURCE_ONLY_ENV": "process environment",
+	}
+
+	// iterating over keys
+	for key, source := range expected {
+
+		// value with source
+		_, src, ok := values.GetWithSource(key)
+
+		// source is different from expected
+		if !ok || src.String() != source {
+			t.Errorf("expected %s from %s, got %s, %v", key, source, src, ok)
+		}
+	}
+
+	// missing key
+	if _, _, ok := values.GetWithSource("ENVFILE_SOURCE_MISSING"); ok {
+		t.Error("expected missing key not to exist")
+	}
+}